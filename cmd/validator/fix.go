@@ -0,0 +1,98 @@
+package main
+
+import (
+	"config-validator/internal/fix"
+	"config-validator/internal/parser"
+	"config-validator/internal/pipeline"
+	"config-validator/internal/rule"
+	"fmt"
+	"os"
+)
+
+// maxFixPasses --fix 最多重複修正的次數
+// 互相重疊的修正（如同一個陣列中的排序與值修正）每次只能套用一個，修正後重新驗證再套用其餘的
+const maxFixPasses = 5
+
+// fixSummary 一次修正的統計
+type fixSummary struct {
+	changed  map[string]bool // 內容有變更的檔案
+	fixed    int             // 已修正（dry-run 時為可修正）的問題數
+	deferred int             // 需要下一輪才能修正的問題數
+}
+
+// applyFixes 套用結果中描述的自動修正
+// dryRun 為 true 時只輸出 unified diff，不修改檔案
+func applyFixes(jobs []*pipeline.Job, results []*rule.ValidationResult, dryRun bool) *fixSummary {
+	byFile := make(map[string][]*rule.ValidationResult)
+	for _, result := range results {
+		if result.Fix != nil && !result.Suppressed {
+			byFile[result.File] = append(byFile[result.File], result)
+		}
+	}
+
+	summary := &fixSummary{changed: make(map[string]bool)}
+	for _, job := range jobs {
+		fileResults := byFile[job.Path]
+		if len(fileResults) == 0 {
+			continue
+		}
+		if job.Format != parser.FormatYAML {
+			fmt.Fprintf(os.Stderr, "⚠️  %s: 目前只支援自動修正 YAML 檔案，略過 %d 個可修正的問題\n", job.Path, len(fileResults))
+			continue
+		}
+
+		info, err := os.Stat(job.Path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %s: 讀取檔案失敗: %v\n", job.Path, err)
+			continue
+		}
+		content, err := os.ReadFile(job.Path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %s: 讀取檔案失敗: %v\n", job.Path, err)
+			continue
+		}
+
+		outcome, err := fix.Apply(content, fileResults)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %s: 無法自動修正: %v\n", job.Path, err)
+			continue
+		}
+		for _, skipped := range outcome.Skipped {
+			fmt.Fprintf(os.Stderr, "⚠️  %s: 無法修正 [%s] %s: %s\n", job.Path, skipped.Result.RuleID, skipped.Result.Path, skipped.Reason)
+		}
+		summary.deferred += len(outcome.Deferred)
+		if len(outcome.Applied) == 0 {
+			continue
+		}
+
+		if dryRun {
+			fmt.Print(fix.UnifiedDiff(job.Path, content, outcome.Content))
+		} else if err := os.WriteFile(job.Path, outcome.Content, info.Mode().Perm()); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %s: 寫入檔案失敗: %v\n", job.Path, err)
+			continue
+		}
+
+		summary.changed[job.Path] = true
+		summary.fixed += len(outcome.Applied)
+	}
+	return summary
+}
+
+// reparseJobs 重新解析修正過的檔案，並更新工作區
+func reparseJobs(jobs []*pipeline.Job, changed map[string]bool, workspace *rule.Workspace) error {
+	for _, job := range jobs {
+		if !changed[job.Path] {
+			continue
+		}
+		p, err := parser.NewParser(job.Format)
+		if err != nil {
+			return err
+		}
+		if err := p.ParseFile(job.Path); err != nil {
+			return fmt.Errorf("重新解析修正後的檔案 %s 失敗: %w", job.Path, err)
+		}
+		job.Parser = p
+		workspace.Add(job.Path, job.MatchPath, p)
+	}
+	return nil
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"config-validator/internal/parser"
+	"config-validator/internal/pipeline"
+	"config-validator/internal/product"
+	"config-validator/internal/rule"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// runGit 在目前目錄執行 git 指令並返回標準輸出
+func runGit(args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return out, nil
+}
+
+// gitRoot 返回目前所在 git 儲存庫的根目錄
+func gitRoot() (string, error) {
+	out, err := runGit("rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// gitChangedFiles 返回有變更的檔案（絕對路徑 -> 相對於儲存庫根目錄的路徑）
+// staged 為 true 時為已加入暫存區的變更（含暫存區中已刪除的檔案，讀取內容時略過）；
+// 否則為 ref 與 HEAD 的分岔點之後的所有變更（含工作目錄中尚未提交的變更），已刪除的檔案不包含在內
+func gitChangedFiles(ref string, staged bool) (map[string]string, error) {
+	root, err := gitRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"diff", "--name-only", "-z"}
+	if staged {
+		args = append(args, "--cached")
+	} else {
+		base, err := runGit("merge-base", ref, "HEAD")
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--diff-filter=ACMR", strings.TrimSpace(string(base)))
+	}
+
+	out, err := runGit(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make(map[string]string)
+	for _, name := range strings.Split(string(out), "\x00") {
+		if name != "" {
+			changed[filepath.Join(root, filepath.FromSlash(name))] = name
+		}
+	}
+	return changed, nil
+}
+
+// existingPaths 返回工作目錄中存在的路徑
+// --staged 時指定的檔案可能只存在於暫存區（工作目錄中已刪除或搬移），不視為錯誤
+func existingPaths(paths []string) []string {
+	var existing []string
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			existing = append(existing, path)
+		}
+	}
+	return existing
+}
+
+// collectStagedFiles 收集指定路徑中有暫存變更的配置檔，內容讀自暫存區，驗證的是即將提交的版本而不是工作目錄
+// 候選檔案來自 git diff --cached，工作目錄中已刪除或搬移的檔案同樣會驗證；暫存區中已刪除的檔案略過
+// 檔案的選擇與 collectConfigFiles 相同：目錄中的檔案以 ScanFormat 篩選，直接指定的檔案以 FileFormat 判斷
+func collectStagedFiles(detector *product.Detector, paths []string, changed map[string]string) ([]pipeline.File, error) {
+	cwd, err := resolvedWorkingDir()
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]string, 0, len(changed))
+	for abs := range changed {
+		candidates = append(candidates, abs)
+	}
+	sort.Strings(candidates)
+
+	var files []pipeline.File
+	var names []string
+	for _, path := range paths {
+		target := absPath(cwd, path)
+		for _, abs := range candidates {
+			rel, err := filepath.Rel(target, abs)
+			if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				continue
+			}
+
+			var cf pipeline.File
+			if rel == "." {
+				cf = pipeline.File{Path: path, MatchPath: pipeline.MatchPath(path)}
+				cf.Format = pipeline.FileFormat(detector, cf)
+			} else {
+				cf = pipeline.File{Path: filepath.Join(path, rel)}
+				cf.MatchPath = pipeline.MatchPath(cf.Path)
+				cf.Format = pipeline.ScanFormat(detector, cf)
+			}
+			if cf.Format != "" {
+				files = append(files, cf)
+				names = append(names, changed[abs])
+			}
+		}
+	}
+
+	contents, err := gitIndexContents(names)
+	if err != nil {
+		return nil, err
+	}
+	staged := files[:0]
+	for i, cf := range files {
+		if content, ok := contents[names[i]]; ok {
+			cf.Content = content
+			staged = append(staged, cf)
+		}
+	}
+	return staged, nil
+}
+
+// gitIndexContents 以 git cat-file --batch 一次讀取多個檔案在暫存區中的內容
+// 暫存區中不存在的檔案（已刪除）不包含在返回的結果中
+func gitIndexContents(names []string) (map[string][]byte, error) {
+	contents := make(map[string][]byte, len(names))
+	if len(names) == 0 {
+		return contents, nil
+	}
+
+	var input bytes.Buffer
+	for _, name := range names {
+		fmt.Fprintf(&input, ":%s\n", name)
+	}
+	cmd := exec.Command("git", "cat-file", "--batch")
+	cmd.Stdin = &input
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git cat-file: %w", err)
+	}
+
+	// 每個物件的輸出為 "<sha> <type> <size>\n<內容>\n"，找不到時為 "<name> missing\n"
+	reader := bufio.NewReader(bytes.NewReader(out))
+	for _, name := range names {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("git cat-file: 讀取 %s 失敗: %w", name, err)
+		}
+		if strings.HasSuffix(header, " missing\n") {
+			continue
+		}
+		fields := strings.Fields(header)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("git cat-file: 無法解析 %s 的輸出: %q", name, header)
+		}
+		size, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("git cat-file: 無法解析 %s 的大小: %s", name, fields[2])
+		}
+		content := make([]byte, size+1)
+		if _, err := io.ReadFull(reader, content); err != nil {
+			return nil, fmt.Errorf("git cat-file: 讀取 %s 失敗: %w", name, err)
+		}
+		if fields[1] == "blob" {
+			contents[name] = content[:size]
+		}
+	}
+	return contents, nil
+}
+
+// splitChanged 將配置檔分為有變更的檔案與其他檔案
+// 其他檔案不會被驗證，只作為跨檔案規則的查詢對象；不是從磁碟讀取的檔案（如標準輸入）一律視為有變更
+func splitChanged(files []pipeline.File, changed map[string]string) (changedFiles, others []pipeline.File, err error) {
+	cwd, err := resolvedWorkingDir()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, cf := range files {
+		if _, ok := changed[absPath(cwd, cf.Path)]; ok || cf.Content != nil {
+			changedFiles = append(changedFiles, cf)
+		} else {
+			others = append(others, cf)
+		}
+	}
+	return changedFiles, others, nil
+}
+
+// baselineRoot 返回 baseline 記錄檔案路徑時的根目錄
+// 在 git 儲存庫中為儲存庫的根目錄，否則為目前目錄
+// 根目錄以未解析符號連結的目前目錄表示，與結果中的相對路徑轉換為絕對路徑後的形式一致
+func baselineRoot() string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	root, err := gitRoot()
+	if err != nil {
+		return cwd
+	}
+	resolved, err := resolvedWorkingDir()
+	if err != nil {
+		return cwd
+	}
+	rel, err := filepath.Rel(resolved, root)
+	if err != nil {
+		return cwd
+	}
+	return filepath.Join(cwd, rel)
+}
+
+// resolvedWorkingDir 返回解析符號連結後的目前目錄
+// git 返回的是實際路徑，目前目錄經過符號連結時需要先解析才能比對
+func resolvedWorkingDir() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(cwd); err == nil {
+		cwd = resolved
+	}
+	return cwd, nil
+}
+
+// absPath 將相對於 cwd 的路徑轉換為絕對路徑
+func absPath(cwd, path string) string {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(cwd, path)
+	}
+	return filepath.Clean(path)
+}
+
+// addContextFiles 解析未變更的配置檔並加入工作區，供跨檔案規則查詢
+// 這些檔案不會被驗證，無法解析或無法識別產品的檔案直接略過
+func addContextFiles(workspace *rule.Workspace, detector *product.Detector, files []pipeline.File, numJobs int) {
+	parsers := make([]parser.Document, len(files))
+	runParallel(numJobs, len(files), func(i int) {
+		p, err := files[i].Parse()
+		if err == nil {
+			parsers[i] = p
+		}
+	})
+
+	for i, cf := range files {
+		if parsers[i] == nil || len(detector.Detect(cf.MatchPath, parsers[i])) == 0 {
+			continue
+		}
+		workspace.Add(cf.Path, cf.MatchPath, parsers[i])
+	}
+}
+
+// anyUsesWorkspace 檢查是否有任何檔案適用跨檔案規則
+func anyUsesWorkspace(jobs []*pipeline.Job) bool {
+	for _, job := range jobs {
+		if job.UsesWorkspace() {
+			return true
+		}
+	}
+	return false
+}
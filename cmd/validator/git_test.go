@@ -0,0 +1,154 @@
+package main
+
+import (
+	"config-validator/internal/pipeline"
+	"config-validator/internal/product"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// initGitRepo 在暫存目錄建立 git 儲存庫並切換到該目錄，沒有安裝 git 時略過測試
+func initGitRepo(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("沒有安裝 git")
+	}
+	chdir(t, t.TempDir())
+	git(t, "init", "-q")
+	git(t, "config", "user.name", "test")
+	git(t, "config", "user.email", "test@example.com")
+	git(t, "config", "commit.gpgsign", "false")
+}
+
+// git 執行 git 指令，失敗時中止測試
+func git(t *testing.T, args ...string) {
+	t.Helper()
+	if _, err := runGit(args...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGitIndexContents(t *testing.T) {
+	initGitRepo(t)
+	writeFile(t, "a.yaml", "name: staged\n")
+	writeFile(t, "dir/b c.yaml", "")
+	git(t, "add", ".")
+
+	// 工作目錄中尚未 git add 的修改不影響暫存區的內容
+	writeFile(t, "a.yaml", "name: working\n")
+
+	contents, err := gitIndexContents([]string{"a.yaml", "dir/b c.yaml", "missing.yaml"})
+	if err != nil {
+		t.Fatalf("gitIndexContents: %v", err)
+	}
+	if got := string(contents["a.yaml"]); got != "name: staged\n" {
+		t.Errorf("a.yaml = %q, want %q", got, "name: staged\n")
+	}
+	if content, ok := contents["dir/b c.yaml"]; !ok || len(content) != 0 {
+		t.Errorf("dir/b c.yaml = %q, %v, want 空的內容", content, ok)
+	}
+	if _, ok := contents["missing.yaml"]; ok {
+		t.Error("暫存區中不存在的檔案不應包含在結果中")
+	}
+
+	if contents, err := gitIndexContents(nil); err != nil || len(contents) != 0 {
+		t.Errorf("gitIndexContents(nil) = %v, %v", contents, err)
+	}
+}
+
+func TestCollectStagedFiles(t *testing.T) {
+	initGitRepo(t)
+	writeFile(t, "configs/app.yaml", "name: app\n")
+	writeFile(t, "configs/old.yaml", "name: old\n")
+	writeFile(t, "configs/removed.yaml", "name: removed\n")
+	writeFile(t, "configs/unchanged.yaml", "name: unchanged\n")
+	git(t, "add", ".")
+	git(t, "commit", "-q", "-m", "init")
+
+	// 修改後加入暫存區，再從工作目錄刪除
+	writeFile(t, "configs/app.yaml", "name: staged\n")
+	git(t, "add", "configs/app.yaml")
+	if err := os.Remove("configs/app.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	// 搬移後加入暫存區，再把工作目錄中的檔案搬到別處
+	git(t, "mv", "configs/old.yaml", "configs/new.yaml")
+	if err := os.Rename("configs/new.yaml", "moved.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	// 從暫存區刪除
+	git(t, "rm", "-q", "configs/removed.yaml")
+	// 不支援的格式與指定路徑以外的檔案
+	writeFile(t, "configs/notes.txt", "text\n")
+	writeFile(t, "other/app.yaml", "name: other\n")
+	git(t, "add", "configs/notes.txt", "other/app.yaml")
+
+	changed, err := gitChangedFiles("", true)
+	if err != nil {
+		t.Fatalf("gitChangedFiles: %v", err)
+	}
+	detector, err := product.NewDetectorFromConfig(product.ProductsConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := collectStagedFiles(detector, []string{"configs"}, changed)
+	if err != nil {
+		t.Fatalf("collectStagedFiles: %v", err)
+	}
+	want := map[string]string{
+		filepath.Join("configs", "app.yaml"): "name: staged\n",
+		filepath.Join("configs", "new.yaml"): "name: old\n",
+	}
+	if len(files) != len(want) {
+		t.Fatalf("files = %v, want %d 個檔案", files, len(want))
+	}
+	for _, cf := range files {
+		if content, ok := want[cf.Path]; !ok || string(cf.Content) != content {
+			t.Errorf("%s = %q, want %q", cf.Path, cf.Content, content)
+		}
+		if cf.MatchPath != filepath.ToSlash(cf.Path) {
+			t.Errorf("%s: MatchPath = %q, want 相對於目前目錄的路徑", cf.Path, cf.MatchPath)
+		}
+	}
+
+	// 直接指定只存在於暫存區的檔案
+	path := filepath.Join("configs", "app.yaml")
+	files, err = collectStagedFiles(detector, []string{path}, changed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Path != path || files[0].MatchPath != "configs/app.yaml" {
+		t.Errorf("files = %v, want [%s]", files, path)
+	}
+}
+
+func TestSplitChanged(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	cwd, err := resolvedWorkingDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := []pipeline.File{
+		{Path: filepath.Join("configs", "app.yaml")},
+		{Path: filepath.Join("configs", "db.yaml")},
+		{Path: "stdin.yaml", Content: []byte("name: stdin\n")},
+	}
+	changed := map[string]string{filepath.Join(cwd, "configs", "app.yaml"): "configs/app.yaml"}
+
+	// 不是從磁碟讀取的檔案（標準輸入）一律視為有變更
+	changedFiles, others, err := splitChanged(files, changed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changedFiles) != 2 || changedFiles[0].Path != files[0].Path || changedFiles[1].Path != "stdin.yaml" {
+		t.Errorf("changed = %v, want [configs/app.yaml stdin.yaml]", changedFiles)
+	}
+	if len(others) != 1 || others[0].Path != files[1].Path {
+		t.Errorf("others = %v, want [configs/db.yaml]", others)
+	}
+}
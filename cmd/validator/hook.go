@@ -0,0 +1,125 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// hookMarker 標記由 validator hook install 產生的 hook，重新安裝時可以直接覆蓋
+const hookMarker = "# config-validator pre-commit hook"
+
+// runHook 執行 `validator hook install`，返回退出碼
+func runHook(args []string) int {
+	if len(args) == 0 || args[0] != "install" {
+		fmt.Fprintln(os.Stderr, "用法: validator hook install [--force] [path...]")
+		return 1
+	}
+
+	flags := flag.NewFlagSet("hook install", flag.ExitOnError)
+	force := flags.Bool("force", false, "覆蓋既有的 pre-commit hook")
+	flags.Parse(args[1:])
+
+	hookPath, err := hookFilePath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "找不到 git 儲存庫: %v\n", err)
+		return 1
+	}
+
+	// hook 在儲存庫根目錄執行，路徑改為相對於根目錄；未指定時驗證整個儲存庫中暫存的配置檔
+	paths, err := repoRelativePaths(flags.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	if existing, err := os.ReadFile(hookPath); err == nil && !strings.Contains(string(existing), hookMarker) && !*force {
+		fmt.Fprintf(os.Stderr, "❌ %s 已存在，使用 --force 覆蓋\n", hookPath)
+		return 1
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "取得執行檔路徑失敗: %v\n", err)
+		return 1
+	}
+
+	if err := os.MkdirAll(filepath.Dir(hookPath), 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "建立 hook 目錄失敗: %v\n", err)
+		return 1
+	}
+	if err := os.WriteFile(hookPath, []byte(hookScript(executable, paths)), 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "寫入 hook 失敗: %v\n", err)
+		return 1
+	}
+	// 覆蓋既有檔案時 WriteFile 不會改變權限
+	if err := os.Chmod(hookPath, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "設定 hook 權限失敗: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("✅ 已安裝 pre-commit hook: %s\n", hookPath)
+	return 0
+}
+
+// hookFilePath 返回 pre-commit hook 的路徑（支援 worktree 與 core.hooksPath）
+func hookFilePath() (string, error) {
+	out, err := runGit("rev-parse", "--git-path", "hooks/pre-commit")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// repoRelativePaths 將相對於目前目錄的路徑轉換為相對於儲存庫根目錄的路徑
+func repoRelativePaths(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		return []string{"."}, nil
+	}
+
+	root, err := gitRoot()
+	if err != nil {
+		return nil, err
+	}
+	cwd, err := resolvedWorkingDir()
+	if err != nil {
+		return nil, err
+	}
+
+	relative := make([]string, len(paths))
+	for i, path := range paths {
+		rel, err := filepath.Rel(root, absPath(cwd, path))
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return nil, fmt.Errorf("路徑不在 git 儲存庫中: %s", path)
+		}
+		relative[i] = filepath.ToSlash(rel)
+	}
+	return relative, nil
+}
+
+// hookScript 產生 pre-commit hook 的內容
+// hook 以 --staged 驗證暫存區中的內容，有 error 時退出碼不為 0，git 會中止提交
+// 環境變數 CONFIG_VALIDATOR 可以指定其他的執行檔
+func hookScript(executable string, paths []string) string {
+	quoted := make([]string, len(paths))
+	for i, path := range paths {
+		quoted[i] = shellQuote(path)
+	}
+
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	b.WriteString(hookMarker + "（由 validator hook install 產生）\n")
+	b.WriteString("# 驗證暫存區中的配置檔，有 error 時中止提交；略過檢查請使用 git commit --no-verify\n")
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "VALIDATOR=${CONFIG_VALIDATOR:-%s}\n", shellQuote(executable))
+	b.WriteString("cd \"$(git rev-parse --show-toplevel)\" || exit 1\n")
+	fmt.Fprintf(&b, "exec \"$VALIDATOR\" --staged %s\n", strings.Join(quoted, " "))
+	return b.String()
+}
+
+// shellQuote 以單引號包住字串，供 sh 使用
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,610 @@
+package main
+
+import (
+	"config-validator/internal/fix"
+	"config-validator/internal/lsp"
+	"config-validator/internal/parser"
+	"config-validator/internal/pipeline"
+	"config-validator/internal/product"
+	"config-validator/internal/rule"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"unicode/utf8"
+)
+
+// lspSource 診斷的來源名稱
+const lspSource = "config-validator"
+
+// runLSP 執行 `validator lsp`，以 stdio 提供 Language Server Protocol 服務，返回退出碼
+func runLSP(args []string) int {
+	if len(args) > 0 {
+		fmt.Fprintln(os.Stderr, "用法: validator lsp")
+		return 1
+	}
+
+	s := &lspServer{
+		conn: lsp.NewConn(os.Stdin, os.Stdout),
+		docs: make(map[string]*lspDocument),
+	}
+	return s.serve()
+}
+
+// lspServer LSP 伺服器狀態
+// 所有請求依序在同一個 goroutine 中處理
+type lspServer struct {
+	conn        *lsp.Conn
+	initialized bool
+	shutdown    bool
+	root        string
+
+	// 與 CLI 相同的產品檢測與規則載入；載入失敗時為 nil，不發布診斷
+	detector  *product.Detector
+	rules     *ruleCache
+	workspace *rule.Workspace
+
+	docs map[string]*lspDocument // 以 URI 為 key 的開啟中文件
+}
+
+// lspDocument 編輯器中開啟的文件
+type lspDocument struct {
+	uri     string
+	version int
+	file    pipeline.File
+	text    *lsp.Text
+	job     *pipeline.Job // 無法識別產品或解析失敗時為 nil
+	results []*rule.ValidationResult
+}
+
+// serve 讀取並處理訊息直到 exit
+func (s *lspServer) serve() int {
+	for {
+		req, err := s.conn.Read()
+		if err == io.EOF {
+			return s.exitCode()
+		}
+		if respErr, ok := err.(*lsp.ResponseError); ok {
+			s.conn.ReplyError(nil, respErr.Code, respErr.Message)
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+
+		if req.Method == "exit" {
+			return s.exitCode()
+		}
+		if err := s.handle(req); err != nil {
+			fmt.Fprintf(os.Stderr, "處理 %s 失敗: %v\n", req.Method, err)
+		}
+	}
+}
+
+// exitCode 依協定，收到 shutdown 之後結束才算正常結束
+func (s *lspServer) exitCode() int {
+	if s.shutdown {
+		return 0
+	}
+	return 1
+}
+
+// handle 處理單一請求或通知
+func (s *lspServer) handle(req *lsp.Request) error {
+	if !s.initialized && req.Method != "initialize" {
+		if req.IsNotification() {
+			return nil
+		}
+		return s.conn.ReplyError(req.ID, lsp.CodeServerNotInitialized, "伺服器尚未初始化")
+	}
+
+	switch req.Method {
+	case "initialize":
+		var params lsp.InitializeParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return s.conn.ReplyError(req.ID, lsp.CodeInvalidParams, err.Error())
+		}
+		return s.conn.Reply(req.ID, s.initialize(&params))
+
+	case "initialized":
+		return nil
+
+	case "shutdown":
+		s.shutdown = true
+		return s.conn.Reply(req.ID, nil)
+
+	case "textDocument/didOpen":
+		var params lsp.DidOpenTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return err
+		}
+		s.didOpen(&params)
+		return nil
+
+	case "textDocument/didChange":
+		var params lsp.DidChangeTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return err
+		}
+		s.didChange(&params)
+		return nil
+
+	case "textDocument/didSave":
+		var params lsp.DidSaveTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return err
+		}
+		s.didSave(&params)
+		return nil
+
+	case "textDocument/didClose":
+		var params lsp.DidCloseTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return err
+		}
+		s.didClose(&params)
+		return nil
+
+	case "textDocument/hover":
+		var params lsp.TextDocumentPositionParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return s.conn.ReplyError(req.ID, lsp.CodeInvalidParams, err.Error())
+		}
+		return s.conn.Reply(req.ID, s.hover(&params))
+
+	case "textDocument/codeAction":
+		var params lsp.CodeActionParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return s.conn.ReplyError(req.ID, lsp.CodeInvalidParams, err.Error())
+		}
+		return s.conn.Reply(req.ID, s.codeActions(&params))
+	}
+
+	if req.IsNotification() {
+		return nil
+	}
+	return s.conn.ReplyError(req.ID, lsp.CodeMethodNotFound, "不支援的方法: "+req.Method)
+}
+
+// initialize 切換到工作區根目錄並載入產品與規則
+// 之後的產品配置、規則目錄與路徑匹配都與在根目錄執行 CLI 相同
+func (s *lspServer) initialize(params *lsp.InitializeParams) *lsp.InitializeResult {
+	root := lsp.PathFromURI(params.RootURI)
+	if root == "" {
+		root = params.RootPath
+	}
+	if root == "" && len(params.WorkspaceFolders) > 0 {
+		root = lsp.PathFromURI(params.WorkspaceFolders[0].URI)
+	}
+	if root != "" {
+		if err := os.Chdir(root); err != nil {
+			s.showError("切換到工作區 %s 失敗: %v", root, err)
+		}
+	}
+	s.root, _ = os.Getwd()
+
+	if err := s.load(); err != nil {
+		s.showError("%v", err)
+	}
+	s.initialized = true
+
+	return &lsp.InitializeResult{
+		Capabilities: lsp.ServerCapabilities{
+			TextDocumentSync: lsp.TextDocumentSyncOptions{OpenClose: true, Change: lsp.SyncFull, Save: true},
+			HoverProvider:    true,
+			CodeActionProvider: lsp.CodeActionOptions{
+				CodeActionKinds: []string{lsp.CodeActionQuickFix, lsp.CodeActionFixAll},
+			},
+		},
+		ServerInfo: lsp.ServerInfo{Name: "config-validator"},
+	}
+}
+
+// load 載入產品配置與規則，並解析工作區中所有配置檔供跨檔案規則查詢
+func (s *lspServer) load() error {
+	s.detector, s.rules, s.workspace = nil, nil, nil
+
+	detector, err := product.NewDetector(productsConfigPath())
+	if err != nil {
+		return fmt.Errorf("載入產品配置失敗: %v", err)
+	}
+	rules, err := newRuleCache(detector)
+	if err != nil {
+		return fmt.Errorf("載入產品配置失敗: %v", err)
+	}
+	configFiles, _, err := collectConfigFiles(detector, []string{"."})
+	if err != nil {
+		return err
+	}
+
+	parsers := make([]parser.Document, len(configFiles))
+	runParallel(runtime.NumCPU(), len(configFiles), func(i int) {
+		parsers[i], _ = configFiles[i].Parse()
+	})
+	workspace := rule.NewWorkspace()
+	for i, cf := range configFiles {
+		if parsers[i] != nil && len(detector.Detect(cf.MatchPath, parsers[i])) > 0 {
+			workspace.Add(cf.Path, cf.MatchPath, parsers[i])
+		}
+	}
+
+	s.detector, s.rules, s.workspace = detector, rules, workspace
+	return nil
+}
+
+func (s *lspServer) didOpen(params *lsp.DidOpenTextDocumentParams) {
+	item := params.TextDocument
+	path := lsp.PathFromURI(item.URI)
+	if path == "" {
+		return
+	}
+
+	doc := &lspDocument{
+		uri:     item.URI,
+		version: item.Version,
+		file:    s.configFileOf(path),
+		text:    lsp.NewText(item.Text),
+	}
+	s.docs[item.URI] = doc
+	s.update(doc)
+}
+
+func (s *lspServer) didChange(params *lsp.DidChangeTextDocumentParams) {
+	doc, ok := s.docs[params.TextDocument.URI]
+	if !ok || len(params.ContentChanges) == 0 {
+		return
+	}
+
+	// 伺服器只接受完整內容的同步，最後一個變更即為目前的內容
+	doc.version = params.TextDocument.Version
+	doc.text = lsp.NewText(params.ContentChanges[len(params.ContentChanges)-1].Text)
+	s.update(doc)
+}
+
+// didSave 儲存產品配置或規則檔案時重新載入，並重新驗證所有開啟的文件
+func (s *lspServer) didSave(params *lsp.DidSaveTextDocumentParams) {
+	path := lsp.PathFromURI(params.TextDocument.URI)
+	if path == "" || !s.isRuleSource(path) {
+		return
+	}
+
+	if err := s.load(); err != nil {
+		s.showError("%v", err)
+	}
+	for _, uri := range sortedKeys(s.docs) {
+		doc := s.docs[uri]
+		doc.file = s.configFileOf(lsp.PathFromURI(uri))
+		s.validate(doc)
+		s.publish(doc)
+	}
+}
+
+// didClose 關閉文件後，工作區改回使用磁碟上的內容
+func (s *lspServer) didClose(params *lsp.DidCloseTextDocumentParams) {
+	doc, ok := s.docs[params.TextDocument.URI]
+	if !ok {
+		return
+	}
+	delete(s.docs, doc.uri)
+	s.conn.Notify("textDocument/publishDiagnostics", &lsp.PublishDiagnosticsParams{
+		URI:         doc.uri,
+		Diagnostics: []lsp.Diagnostic{},
+	})
+
+	// 不論關閉時的內容能否驗證，工作區都恢復為磁碟上的版本，其他文件的跨檔案規則不再看到未儲存的內容
+	if s.workspace == nil {
+		return
+	}
+	s.workspace.Remove(doc.file.Path)
+	if p, err := doc.file.Parse(); err == nil && len(s.detector.Detect(doc.file.MatchPath, p)) > 0 {
+		s.workspace.Add(doc.file.Path, doc.file.MatchPath, p)
+	}
+	s.revalidateDependents(doc)
+}
+
+// update 驗證變更的文件，並重新驗證使用跨檔案規則的其他開啟文件
+func (s *lspServer) update(doc *lspDocument) {
+	s.validate(doc)
+	s.publish(doc)
+	s.revalidateDependents(doc)
+}
+
+// revalidateDependents 重新驗證 changed 以外使用跨檔案規則的開啟文件
+func (s *lspServer) revalidateDependents(changed *lspDocument) {
+	for _, uri := range sortedKeys(s.docs) {
+		doc := s.docs[uri]
+		if doc == changed || doc.job == nil || !doc.job.UsesWorkspace() {
+			continue
+		}
+		s.validate(doc)
+		s.publish(doc)
+	}
+}
+
+// configFileOf 以與 CLI 在工作區根目錄執行時相同的方式決定檔案的路徑與格式
+func (s *lspServer) configFileOf(path string) pipeline.File {
+	cf := pipeline.File{Path: path, MatchPath: filepath.ToSlash(path)}
+	if rel, err := filepath.Rel(s.root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		cf.Path = rel
+		cf.MatchPath = filepath.ToSlash(rel)
+	}
+	if s.detector != nil {
+		cf.Format = pipeline.FileFormat(s.detector, cf)
+	}
+	return cf
+}
+
+// isRuleSource 檢查檔案是否為產品配置或規則目錄中的檔案
+func (s *lspServer) isRuleSource(path string) bool {
+	if sameFile(path, productsConfigPath()) {
+		return true
+	}
+	if s.rules == nil {
+		return false
+	}
+	for _, dir := range s.rules.dirs() {
+		abs, err := filepath.Abs(dir)
+		if err == nil && strings.HasPrefix(path, abs+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameFile(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
+
+// validate 解析並驗證文件，結果存於 doc.results
+// 解析失敗時以單一診斷回報錯誤
+func (s *lspServer) validate(doc *lspDocument) {
+	doc.job, doc.results = nil, nil
+	if s.detector == nil || doc.file.Format == "" {
+		return
+	}
+
+	p, err := parser.NewParser(doc.file.Format)
+	if err == nil {
+		err = p.ParseBytes([]byte(doc.text.String()))
+	}
+	if err != nil {
+		// 無法解析或不再屬於任何產品時，從工作區移除上一次成功解析的內容
+		s.workspace.Remove(doc.file.Path)
+		doc.results = []*rule.ValidationResult{parseErrorResult(doc.file.Path, err)}
+		return
+	}
+
+	detections := s.detector.Detect(doc.file.MatchPath, p)
+	if len(detections) == 0 {
+		s.workspace.Remove(doc.file.Path)
+		return
+	}
+	job, err := pipeline.NewJob(doc.file, p, detections, s.rules.load)
+	if err != nil {
+		s.workspace.Remove(doc.file.Path)
+		s.showError("%v", err)
+		return
+	}
+
+	doc.job = job
+	s.workspace.Add(job.Path, job.MatchPath, job.Parser)
+	doc.results = job.Validate(s.workspace)
+}
+
+// publish 發布文件的診斷（不含被抑制的結果）
+func (s *lspServer) publish(doc *lspDocument) {
+	diagnostics := []lsp.Diagnostic{}
+	for _, result := range doc.results {
+		if !result.Suppressed {
+			diagnostics = append(diagnostics, diagnosticOf(doc.text, result))
+		}
+	}
+	version := doc.version
+	s.conn.Notify("textDocument/publishDiagnostics", &lsp.PublishDiagnosticsParams{
+		URI:         doc.uri,
+		Version:     &version,
+		Diagnostics: diagnostics,
+	})
+}
+
+// diagnosticOf 將驗證結果轉換為診斷
+func diagnosticOf(text *lsp.Text, result *rule.ValidationResult) lsp.Diagnostic {
+	message := result.Message
+	if result.ActualValue != "" {
+		message += "\n實際值: " + result.ActualValue
+	}
+	if result.ExpectedValue != "" {
+		message += "\n期望值: " + result.ExpectedValue
+	}
+	return lsp.Diagnostic{
+		Range:    resultRange(text, result),
+		Severity: diagnosticSeverity(result.Severity),
+		Code:     result.RuleID,
+		Source:   lspSource,
+		Message:  message,
+	}
+}
+
+// resultRange 驗證結果在文件中的範圍
+// 沒有結束位置時標示到行尾；沒有位置（如整個檔案的問題）時標示在第一行
+func resultRange(text *lsp.Text, result *rule.ValidationResult) lsp.Range {
+	if result.Line <= 0 {
+		return lsp.Range{End: text.LineEnd(1)}
+	}
+	start := text.Position(result.Line, result.Column)
+	if result.EndLine <= 0 {
+		return lsp.Range{Start: start, End: text.LineEnd(result.Line)}
+	}
+	return lsp.Range{Start: start, End: text.Position(result.EndLine, result.EndColumn)}
+}
+
+func diagnosticSeverity(severity rule.Severity) lsp.DiagnosticSeverity {
+	switch severity {
+	case rule.SeverityError:
+		return lsp.SeverityError
+	case rule.SeverityWarning:
+		return lsp.SeverityWarning
+	default:
+		return lsp.SeverityInformation
+	}
+}
+
+// hover 顯示游標所在位置的問題與規則說明（ValidationRule.Description）
+func (s *lspServer) hover(params *lsp.TextDocumentPositionParams) *lsp.Hover {
+	doc, ok := s.docs[params.TextDocument.URI]
+	if !ok || doc.job == nil {
+		return nil
+	}
+
+	var sections []string
+	var hoverRange *lsp.Range
+	for _, result := range doc.results {
+		r := resultRange(doc.text, result)
+		if result.Suppressed || !r.Contains(params.Position) {
+			continue
+		}
+		if hoverRange == nil {
+			hoverRange = &r
+		}
+
+		section := fmt.Sprintf("**[%s] %s**", result.RuleID, result.RuleName)
+		if validationRule := findRule(doc.job.Rules, result.RuleID); validationRule != nil && validationRule.Description != "" {
+			section += "\n\n" + strings.TrimSpace(validationRule.Description)
+		}
+		section += fmt.Sprintf("\n\n%s %s", severityIcon(result.Severity), result.Message)
+		sections = append(sections, section)
+	}
+	if len(sections) == 0 {
+		return nil
+	}
+
+	return &lsp.Hover{
+		Contents: lsp.MarkupContent{Kind: "markdown", Value: strings.Join(sections, "\n\n---\n\n")},
+		Range:    hoverRange,
+	}
+}
+
+// findRule 依 ID 取得規則
+func findRule(rules []*rule.ValidationRule, id string) *rule.ValidationRule {
+	for _, r := range rules {
+		if r.ID == id {
+			return r
+		}
+	}
+	return nil
+}
+
+// codeActions 為範圍內可自動修正的問題提供修正，並提供一次修正整個文件的動作
+// 與 --fix 相同，目前只支援 YAML 檔案
+func (s *lspServer) codeActions(params *lsp.CodeActionParams) []lsp.CodeAction {
+	actions := []lsp.CodeAction{}
+	doc, ok := s.docs[params.TextDocument.URI]
+	if !ok || doc.job == nil || doc.job.Format != parser.FormatYAML {
+		return actions
+	}
+
+	content := []byte(doc.text.String())
+	var fixable []*rule.ValidationResult
+	for _, result := range doc.results {
+		if result.Fix != nil && !result.Suppressed {
+			fixable = append(fixable, result)
+		}
+	}
+
+	if codeActionAllowed(params.Context.Only, lsp.CodeActionQuickFix) {
+		for _, result := range fixable {
+			diagnostic := diagnosticOf(doc.text, result)
+			if !diagnostic.Range.Overlaps(params.Range) {
+				continue
+			}
+			edit := s.fixEdit(doc, content, []*rule.ValidationResult{result})
+			if edit == nil {
+				continue
+			}
+			actions = append(actions, lsp.CodeAction{
+				Title:       fmt.Sprintf("%s [%s]", result.Fix.Description, result.RuleID),
+				Kind:        lsp.CodeActionQuickFix,
+				Diagnostics: []lsp.Diagnostic{diagnostic},
+				IsPreferred: true,
+				Edit:        edit,
+			})
+		}
+	}
+
+	if len(fixable) > 0 && codeActionAllowed(params.Context.Only, lsp.CodeActionFixAll) {
+		if edit := s.fixEdit(doc, content, fixable); edit != nil {
+			actions = append(actions, lsp.CodeAction{
+				Title: "修正所有可自動修正的問題",
+				Kind:  lsp.CodeActionFixAll,
+				Edit:  edit,
+			})
+		}
+	}
+	return actions
+}
+
+// fixEdit 套用修正並轉換為只替換變更部分的編輯，沒有可套用的修正時返回 nil
+// 互相重疊的修正只會套用第一個，其餘在文件更新並重新驗證後再提供
+func (s *lspServer) fixEdit(doc *lspDocument, content []byte, results []*rule.ValidationResult) *lsp.WorkspaceEdit {
+	outcome, err := fix.Apply(content, results)
+	if err != nil || len(outcome.Applied) == 0 {
+		return nil
+	}
+	return &lsp.WorkspaceEdit{
+		Changes: map[string][]lsp.TextEdit{doc.uri: {minimalEdit(doc.text, content, outcome.Content)}},
+	}
+}
+
+// minimalEdit 以共同的開頭與結尾縮小替換範圍，避免編輯器整份文件重新載入而失去游標位置
+func minimalEdit(text *lsp.Text, before, after []byte) lsp.TextEdit {
+	prefix := 0
+	for prefix < len(before) && prefix < len(after) && before[prefix] == after[prefix] {
+		prefix++
+	}
+	for prefix > 0 && prefix < len(before) && !utf8.RuneStart(before[prefix]) {
+		prefix--
+	}
+
+	suffix := 0
+	for suffix < len(before)-prefix && suffix < len(after)-prefix &&
+		before[len(before)-1-suffix] == after[len(after)-1-suffix] {
+		suffix++
+	}
+	for suffix > 0 && !utf8.RuneStart(before[len(before)-suffix]) {
+		suffix--
+	}
+
+	return lsp.TextEdit{
+		Range: lsp.Range{
+			Start: text.OffsetPosition(prefix),
+			End:   text.OffsetPosition(len(before) - suffix),
+		},
+		NewText: string(after[prefix : len(after)-suffix]),
+	}
+}
+
+// codeActionAllowed 檢查 kind 是否符合用戶端要求的類型（未指定時全部允許）
+func codeActionAllowed(only []string, kind string) bool {
+	if len(only) == 0 {
+		return true
+	}
+	for _, k := range only {
+		if kind == k || strings.HasPrefix(kind, k+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// showError 在編輯器中顯示錯誤訊息
+func (s *lspServer) showError(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	fmt.Fprintf(os.Stderr, "%s\n", message)
+	s.conn.Notify("window/showMessage", &lsp.ShowMessageParams{Type: lsp.MessageError, Message: message})
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"config-validator/internal/lsp"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// workspaceName 返回工作區中檔案的 name 欄位，檔案不在工作區時返回空字串
+func workspaceName(s *lspServer, path string) string {
+	p, ok := s.workspace.Parser(path)
+	if !ok {
+		return ""
+	}
+	name, _ := p.GetString("name")
+	return name
+}
+
+func TestLSPCloseRestoresWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	writeFile(t, "products.yaml", `products:
+  - name: app
+    rules_dir: rules/app
+    path_patterns: ["**/app*.yaml"]
+`)
+	writeFile(t, "rules/app/app-001.yaml", `id: app-001
+name: "name 必填"
+enabled: true
+severity: error
+targets:
+  file_patterns: ["**/app*.yaml"]
+rule:
+  type: required_field
+  path: name
+  message: "缺少 name"
+`)
+	writeFile(t, "app.yaml", "name: disk\n")
+
+	s := &lspServer{
+		conn: lsp.NewConn(strings.NewReader(""), io.Discard),
+		root: dir,
+		docs: make(map[string]*lspDocument),
+	}
+	if err := s.load(); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	uri := lsp.URIFromPath(filepath.Join(dir, "app.yaml"))
+	change := func(text string) {
+		s.didChange(&lsp.DidChangeTextDocumentParams{
+			TextDocument:   lsp.VersionedTextDocumentIdentifier{URI: uri, Version: 2},
+			ContentChanges: []lsp.TextDocumentContentChangeEvent{{Text: text}},
+		})
+	}
+
+	// 開啟的文件以編輯器中的內容取代工作區中的版本
+	s.didOpen(&lsp.DidOpenTextDocumentParams{TextDocument: lsp.TextDocumentItem{URI: uri, Version: 1, Text: "name: editor\n"}})
+	if got := workspaceName(s, "app.yaml"); got != "editor" {
+		t.Fatalf("開啟後 name = %q, want editor", got)
+	}
+
+	// 編輯後無法解析時，其他文件的跨檔案規則不再看到上一次成功解析的內容
+	change("name: [editor\n")
+	if got := workspaceName(s, "app.yaml"); got != "" {
+		t.Errorf("無法解析後 name = %q, want 不在工作區中", got)
+	}
+
+	// 在無法解析的狀態下關閉，工作區恢復為磁碟上的內容
+	s.didClose(&lsp.DidCloseTextDocumentParams{TextDocument: lsp.TextDocumentIdentifier{URI: uri}})
+	if got := workspaceName(s, "app.yaml"); got != "disk" {
+		t.Errorf("關閉後 name = %q, want disk", got)
+	}
+	if len(s.docs) != 0 {
+		t.Errorf("docs = %d, want 0", len(s.docs))
+	}
+}
@@ -1,152 +1,318 @@
 package main
 
 import (
-	"config-validator/internal/parser"
+	"config-validator/internal/baseline"
+	"config-validator/internal/pipeline"
 	"config-validator/internal/product"
 	"config-validator/internal/reporter"
 	"config-validator/internal/rule"
+	"context"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
-	"strings"
+	"regexp"
+	"runtime"
+	"strconv"
 )
 
 func main() {
+	// 子命令
+	if len(os.Args) > 2 && os.Args[1] == "rules" && os.Args[2] == "test" {
+		os.Exit(runRulesTest(os.Args[3:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "watch" {
+		os.Exit(runWatch(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "lsp" {
+		os.Exit(runLSP(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		os.Exit(runServe(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "hook" {
+		os.Exit(runHook(os.Args[2:]))
+	}
+
 	// 解析命令行參數
-	jsonOutput := flag.Bool("json", false, "輸出 JSON 格式")
+	jsonOutput := flag.Bool("json", false, "輸出 JSON 格式（等同 --format json）")
+	format := flag.String("format", "console", "輸出格式: console, json, sarif, junit")
+	baselinePath := flag.String("baseline", "", "只回報不在 baseline 檔案中的問題")
+	writeBaselinePath := flag.String("write-baseline", "", "將目前的問題寫入 baseline 檔案")
+	numJobs := flag.Int("jobs", runtime.NumCPU(), "同時驗證的檔案數量")
+	explainDetection := flag.Bool("explain-detection", false, "輸出每個檔案的產品檢測依據")
+	fixMode := flag.Bool("fix", false, "自動修正可修正的問題並寫回檔案")
+	fixDryRun := flag.Bool("fix-dry-run", false, "以 unified diff 輸出自動修正的內容，不修改檔案")
+	stdinMode := flag.Bool("stdin", false, "從標準輸入讀取配置檔內容")
+	stdinPath := flag.String("stdin-path", "", "標準輸入內容的邏輯路徑，用於產品檢測與規則匹配")
+	changedSince := flag.String("changed-since", "", "只驗證與指定 git ref 的分岔點之後有變更的配置檔")
+	stagedMode := flag.Bool("staged", false, "只驗證已加入 git 暫存區的配置檔（以暫存區中的內容驗證）")
 	flag.Parse()
 
-	if flag.NArg() < 1 {
-		fmt.Fprintln(os.Stderr, "用法: validator [--json] <path1> [path2] [path3] ...")
+	if flag.NArg() < 1 && !*stdinMode {
+		fmt.Fprintln(os.Stderr, "用法: validator [--json] [--format <格式>] <path1> [path2] [path3] ...")
+		fmt.Fprintln(os.Stderr, "      validator rules test [rules_dir...]")
+		fmt.Fprintln(os.Stderr, "      validator watch [--interval 500ms] <path1> [path2] ...")
+		fmt.Fprintln(os.Stderr, "      validator lsp")
+		fmt.Fprintln(os.Stderr, "      validator serve [--addr :8080]")
+		fmt.Fprintln(os.Stderr, "      validator hook install [--force] [path...]")
 		fmt.Fprintln(os.Stderr, "")
 		fmt.Fprintln(os.Stderr, "參數說明:")
-		fmt.Fprintln(os.Stderr, "  <path>   配置檔或目錄路徑（可指定多個）")
-		fmt.Fprintln(os.Stderr, "  --json   輸出 JSON 格式")
+		fmt.Fprintln(os.Stderr, "  <path>     配置檔或目錄路徑（可指定多個）")
+		fmt.Fprintln(os.Stderr, "  --json     輸出 JSON 格式")
+		fmt.Fprintln(os.Stderr, "  --format   輸出格式: console（預設）、json、sarif、junit")
+		fmt.Fprintln(os.Stderr, "  --baseline <file>        只回報不在 baseline 中的新問題")
+		fmt.Fprintln(os.Stderr, "  --write-baseline <file>  將目前的問題寫入 baseline")
+		fmt.Fprintln(os.Stderr, "  --jobs <N>               同時驗證的檔案數量（預設為 CPU 數量）")
+		fmt.Fprintln(os.Stderr, "  --explain-detection      輸出每個檔案的產品檢測依據")
+		fmt.Fprintln(os.Stderr, "  --fix                    自動修正可修正的問題並寫回檔案")
+		fmt.Fprintln(os.Stderr, "  --fix-dry-run            以 unified diff 輸出自動修正的內容，不修改檔案")
+		fmt.Fprintln(os.Stderr, "  --stdin --stdin-path <path>  從標準輸入讀取配置檔，以 <path> 檢測產品與匹配規則")
+		fmt.Fprintln(os.Stderr, "  --changed-since <ref>    只驗證與 <ref> 的分岔點之後有變更的配置檔")
+		fmt.Fprintln(os.Stderr, "  --staged                 只驗證已加入 git 暫存區的配置檔（以暫存區中的內容驗證）")
 		fmt.Fprintln(os.Stderr, "")
 		fmt.Fprintln(os.Stderr, "範例:")
 		fmt.Fprintln(os.Stderr, "  validator configs/")
 		fmt.Fprintln(os.Stderr, "  validator configs/api.yaml configs/db.yaml")
 		fmt.Fprintln(os.Stderr, "  validator --json testdata/")
+		fmt.Fprintln(os.Stderr, "  validator --format sarif testdata/ > results.sarif")
+		fmt.Fprintln(os.Stderr, "  validator --format junit testdata/ > junit.xml")
+		fmt.Fprintln(os.Stderr, "  validator --write-baseline baseline.json configs/")
+		fmt.Fprintln(os.Stderr, "  validator --baseline baseline.json configs/")
+		fmt.Fprintln(os.Stderr, "  validator --fix-dry-run configs/")
+		fmt.Fprintln(os.Stderr, "  render-config | validator --stdin --stdin-path services/api/api-prod.yaml")
+		fmt.Fprintln(os.Stderr, "  validator --changed-since origin/main .")
+		os.Exit(1)
+	}
+
+	if *jsonOutput {
+		*format = "json"
+	}
+	switch *format {
+	case "console", "json", "sarif", "junit":
+	default:
+		fmt.Fprintf(os.Stderr, "不支援的輸出格式: %s\n", *format)
+		os.Exit(1)
+	}
+	if *stdinMode && *stdinPath == "" {
+		fmt.Fprintln(os.Stderr, "--stdin 必須搭配 --stdin-path 指定邏輯路徑")
+		os.Exit(1)
+	}
+	if !*stdinMode && *stdinPath != "" {
+		fmt.Fprintln(os.Stderr, "--stdin-path 只能搭配 --stdin 使用")
+		os.Exit(1)
+	}
+	if *stdinMode && (*fixMode || *fixDryRun) {
+		fmt.Fprintln(os.Stderr, "--stdin 不支援 --fix 與 --fix-dry-run")
+		os.Exit(1)
+	}
+	if *stagedMode && *changedSince != "" {
+		fmt.Fprintln(os.Stderr, "--staged 與 --changed-since 只能擇一使用")
+		os.Exit(1)
+	}
+	if *stagedMode && (*fixMode || *fixDryRun) {
+		fmt.Fprintln(os.Stderr, "--staged 不支援 --fix 與 --fix-dry-run")
+		os.Exit(1)
+	}
+	// 只驗證變更的檔案時寫入 baseline 會遺失其他檔案的既有項目
+	if *writeBaselinePath != "" && (*stagedMode || *changedSince != "") {
+		fmt.Fprintln(os.Stderr, "--write-baseline 不能與 --staged 或 --changed-since 一起使用")
 		os.Exit(1)
 	}
 
 	// 獲取所有路徑參數
 	paths := flag.Args()
 
-	// 決定產品配置檔路徑（Docker 環境使用 /products.yaml，本地使用 ./products.yaml）
-	productsConfig := "/products.yaml"
-	if _, err := os.Stat(productsConfig); os.IsNotExist(err) {
-		productsConfig = "./products.yaml"
+	// 載入 baseline
+	var base *baseline.Baseline
+	if *baselinePath != "" {
+		var err error
+		base, err = baseline.Load(*baselinePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "載入 baseline 失敗: %v\n", err)
+			os.Exit(1)
+		}
 	}
 
 	// 載入產品檢測器
-	detector, err := product.NewDetector(productsConfig)
+	detector, err := product.NewDetector(productsConfigPath())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "載入產品配置失敗: %v\n", err)
 		os.Exit(1)
 	}
 
-	// 收集所有配置檔
-	var allConfigFiles []string
-	for _, path := range paths {
-		// 檢查路徑是否存在
-		info, err := os.Stat(path)
-		if os.IsNotExist(err) {
-			fmt.Fprintf(os.Stderr, "路徑不存在: %s\n", path)
+	// 建立各產品的分層規則設定（extends / shared_rules_dirs），規則在第一次用到時才載入
+	rules, err := newRuleCache(detector)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "載入產品配置失敗: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 收集所有配置檔；--staged 時指定的檔案可能只存在於暫存區，由暫存區補上
+	diskPaths := paths
+	if *stagedMode {
+		diskPaths = existingPaths(paths)
+	}
+	allConfigFiles, skipped, err := collectConfigFiles(detector, diskPaths)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	for _, path := range skipped {
+		fmt.Fprintf(os.Stderr, "⚠️  跳過不支援格式的檔案: %s\n", path)
+	}
+
+	// 標準輸入的內容以 --stdin-path 作為路徑，同一路徑的磁碟檔案以標準輸入的內容取代
+	if *stdinMode {
+		cf, err := readStdinConfig(detector, *stdinPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
 			os.Exit(1)
 		}
+		allConfigFiles = replaceConfigFile(allConfigFiles, cf)
+	}
+
+	if len(allConfigFiles) == 0 && !*stagedMode {
+		fmt.Fprintf(os.Stderr, "在指定路徑中沒有找到配置檔\n")
+		os.Exit(1)
+	}
+
+	// 只驗證 git 中有變更的配置檔，其他配置檔在需要時作為跨檔案規則的查詢對象
+	var contextFiles []pipeline.File
+	if *stagedMode || *changedSince != "" {
+		changed, err := gitChangedFiles(*changedSince, *stagedMode)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "讀取路徑失敗 %s: %v\n", path, err)
+			fmt.Fprintf(os.Stderr, "取得 git 變更失敗: %v\n", err)
 			os.Exit(1)
 		}
-
-		// 如果是目錄，掃描其中的配置檔
-		if info.IsDir() {
-			configFiles, err := scanConfigFiles(path)
+		allConfigFiles, contextFiles, err = splitChanged(allConfigFiles, changed)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "取得 git 變更失敗: %v\n", err)
+			os.Exit(1)
+		}
+		if *stagedMode {
+			// 有變更的檔案改為從暫存區收集並讀取內容（含工作目錄中已刪除或搬移的檔案），標準輸入的內容仍然優先
+			staged, err := collectStagedFiles(detector, paths, changed)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "掃描配置檔失敗 %s: %v\n", path, err)
+				fmt.Fprintf(os.Stderr, "讀取暫存區內容失敗: %v\n", err)
 				os.Exit(1)
 			}
-			allConfigFiles = append(allConfigFiles, configFiles...)
-		} else {
-			// 如果是檔案，直接添加（只處理 YAML 檔案）
-			if strings.HasSuffix(info.Name(), ".yaml") || strings.HasSuffix(info.Name(), ".yml") {
-				allConfigFiles = append(allConfigFiles, path)
-			} else {
-				fmt.Fprintf(os.Stderr, "⚠️  跳過非 YAML 檔案: %s\n", path)
+			for _, cf := range allConfigFiles {
+				if cf.Content != nil {
+					staged = replaceConfigFile(staged, cf)
+				}
 			}
+			allConfigFiles = staged
+		}
+		if len(allConfigFiles) == 0 {
+			fmt.Fprintf(os.Stderr, "沒有變更的配置檔\n")
 		}
 	}
 
-	if len(allConfigFiles) == 0 {
-		fmt.Fprintf(os.Stderr, "在指定路徑中沒有找到配置檔\n")
+	// 平行解析所有配置檔並檢測產品類型，解析失敗的檔案只比對路徑
+	var explain func(pipeline.File, []*product.Detection)
+	if *explainDetection {
+		explain = func(cf pipeline.File, detections []*product.Detection) {
+			explainProducts(cf.Path, detections)
+		}
+	}
+	jobs, unknown, err := pipeline.Prepare(context.Background(), detector, allConfigFiles, rules.load, *numJobs, explain)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
 	}
+	for _, cf := range unknown {
+		fmt.Fprintf(os.Stderr, "⚠️  無法識別配置檔 %s 的產品類型，跳過驗證\n", cf.Path)
+	}
 
-	// 建立輸出器
-	rep := reporter.NewReporter()
+	// 所有已解析的配置檔，供跨檔案規則查詢
+	workspace := pipeline.NewWorkspace(jobs)
+	if len(contextFiles) > 0 && anyUsesWorkspace(jobs) {
+		addContextFiles(workspace, detector, contextFiles, *numJobs)
+	}
 
-	// 規則緩存和統計資訊
-	productRules := make(map[string][]*rule.ValidationRule)
-	totalRulesCount := 0
+	// 所有檔案解析完成後才開始驗證，跨檔案規則才能看到完整的工作區
+	rep := validateJobs(jobs, workspace, *numJobs)
 
-	// 驗證每個配置檔
-	for _, configFile := range allConfigFiles {
-		// 檢測產品類型
-		prod := detector.DetectProduct(configFile)
-		if prod == nil {
-			fmt.Fprintf(os.Stderr, "⚠️  無法識別配置檔 %s 的產品類型，跳過驗證\n", configFile)
-			continue
+	// 自動修正：dry-run 只輸出 diff；--fix 寫回檔案後重新驗證，只回報修正後仍存在的問題
+	if *fixDryRun {
+		summary := applyFixes(jobs, rep.Results(), true)
+		fmt.Fprintf(os.Stderr, "🔧 可自動修正 %d 個問題（%d 個檔案），使用 --fix 套用\n", summary.fixed, len(summary.changed))
+		if summary.deferred > 0 {
+			fmt.Fprintf(os.Stderr, "   另有 %d 個修正與上述修正重疊，--fix 會在套用後接著修正\n", summary.deferred)
 		}
-
-		// 如果是第一次處理此產品，載入規則並緩存
-		rules, exists := productRules[prod.Name]
-		if !exists {
-			// 決定規則目錄（Docker 環境使用絕對路徑，本地使用相對路徑）
-			rulesDir := "/" + prod.RulesDir
-			if _, err := os.Stat(rulesDir); os.IsNotExist(err) {
-				rulesDir = "./" + prod.RulesDir
+		return
+	}
+	if *fixMode {
+		fixed, files := 0, make(map[string]bool)
+		candidates := rep.Results()
+		for pass := 0; pass < maxFixPasses; pass++ {
+			summary := applyFixes(jobs, candidates, false)
+			if len(summary.changed) == 0 {
+				break
 			}
-
-			// 載入該產品的規則
-			loader := rule.NewLoader(rulesDir)
-			var err error
-			rules, err = loader.LoadRules()
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "載入產品 %s 的規則失敗: %v\n", prod.Name, err)
+			if err := reparseJobs(jobs, summary.changed, workspace); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
 				os.Exit(1)
 			}
+			rep = validateJobs(jobs, workspace, *numJobs)
+			fixed += summary.fixed
+			for file := range summary.changed {
+				files[file] = true
+			}
 
-			// 緩存規則
-			productRules[prod.Name] = rules
-			totalRulesCount += len(rules)
+			// 下一輪只處理這一輪有變更的檔案
+			candidates = nil
+			for _, result := range rep.Results() {
+				if summary.changed[result.File] {
+					candidates = append(candidates, result)
+				}
+			}
 		}
+		fmt.Fprintf(os.Stderr, "🔧 已自動修正 %d 個問題（%d 個檔案）\n", fixed, len(files))
+	}
 
-		// 驗證配置檔（使用緩存的規則）
-		if err := validateFile(configFile, rules, rep); err != nil {
-			fmt.Fprintf(os.Stderr, "驗證檔案 %s 失敗: %v\n", configFile, err)
+	// 寫入 baseline 後直接結束，不輸出報告
+	if *writeBaselinePath != "" {
+		b := baseline.FromResults(rep.Results(), baselineRoot())
+		if err := b.Write(*writeBaselinePath); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
 			os.Exit(1)
 		}
+		fmt.Fprintf(os.Stderr, "📝 已將 %d 個問題寫入 baseline: %s\n", len(b.Entries), *writeBaselinePath)
+		return
+	}
+
+	// 移除 baseline 中已記錄的問題，只回報新問題
+	if base != nil {
+		matcher := base.NewMatcher(baselineRoot())
+		removed := rep.Filter(func(result *rule.ValidationResult) bool {
+			return result.Suppressed || !matcher.Match(result)
+		})
+		fmt.Fprintf(os.Stderr, "📌 已略過 %d 個 baseline 中的既有問題\n", removed)
 	}
 
 	// 輸出結果
-	if *jsonOutput {
+	switch *format {
+	case "json":
 		if err := rep.PrintJSON(); err != nil {
 			fmt.Fprintf(os.Stderr, "輸出結果失敗: %v\n", err)
 			os.Exit(1)
 		}
-	} else {
-		// 顯示載入的產品規則統計
-		if len(productRules) > 0 {
-			fmt.Printf("📋 載入了 %d 個產品的規則：\n", len(productRules))
-			for prodName, rules := range productRules {
-				fmt.Printf("   • %s: %d 條規則\n", prodName, len(rules))
-			}
-			fmt.Println()
+	case "sarif":
+		if err := rep.PrintSARIF(rules.all()); err != nil {
+			fmt.Fprintf(os.Stderr, "輸出結果失敗: %v\n", err)
+			os.Exit(1)
 		}
-
-		rep.PrintConsole(totalRulesCount)
+	case "junit":
+		if err := rep.PrintJUnit(); err != nil {
+			fmt.Fprintf(os.Stderr, "輸出結果失敗: %v\n", err)
+			os.Exit(1)
+		}
+	default:
+		rules.printSummary()
+		rep.PrintConsole(len(rules.all()))
 	}
 
 	// 設置退出碼
@@ -155,7 +321,64 @@ func main() {
 	}
 }
 
-// scanConfigFiles 掃描配置檔目錄
+// productsConfigPath 決定產品配置檔路徑（Docker 環境使用 /products.yaml，本地使用 ./products.yaml）
+func productsConfigPath() string {
+	productsConfig := "/products.yaml"
+	if _, err := os.Stat(productsConfig); os.IsNotExist(err) {
+		productsConfig = "./products.yaml"
+	}
+	return productsConfig
+}
+
+// resolveRulesDir 決定規則目錄（Docker 環境使用絕對路徑，本地使用相對路徑）
+func resolveRulesDir(dir string) string {
+	rulesDir := "/" + dir
+	if _, err := os.Stat(rulesDir); os.IsNotExist(err) {
+		rulesDir = "./" + dir
+	}
+	return rulesDir
+}
+
+// collectConfigFiles 收集所有路徑中支援格式的配置檔
+// 目錄中不支援的檔案直接略過；直接指定但不支援的檔案列於 skipped
+func collectConfigFiles(detector *product.Detector, paths []string) (files []pipeline.File, skipped []string, err error) {
+	for _, path := range paths {
+		// 檢查路徑是否存在
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			return nil, nil, fmt.Errorf("路徑不存在: %s", path)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("讀取路徑失敗 %s: %v", path, err)
+		}
+
+		// 如果是目錄，掃描其中的配置檔
+		if info.IsDir() {
+			configFiles, err := scanConfigFiles(path)
+			if err != nil {
+				return nil, nil, fmt.Errorf("掃描配置檔失敗 %s: %v", path, err)
+			}
+			for _, file := range configFiles {
+				cf := pipeline.File{Path: file, MatchPath: pipeline.MatchPath(file)}
+				if cf.Format = pipeline.ScanFormat(detector, cf); cf.Format != "" {
+					files = append(files, cf)
+				}
+			}
+			continue
+		}
+
+		// 如果是檔案，直接添加（只處理支援的格式）
+		cf := pipeline.File{Path: path, MatchPath: pipeline.MatchPath(path)}
+		if cf.Format = pipeline.FileFormat(detector, cf); cf.Format != "" {
+			files = append(files, cf)
+		} else {
+			skipped = append(skipped, path)
+		}
+	}
+	return files, skipped, nil
+}
+
+// scanConfigFiles 掃描目錄中的所有檔案，由呼叫端以 scanFormat 篩選
 func scanConfigFiles(dir string) ([]string, error) {
 	var files []string
 
@@ -168,34 +391,86 @@ func scanConfigFiles(dir string) ([]string, error) {
 			return nil
 		}
 
-		// 只處理 YAML 檔案
-		if strings.HasSuffix(info.Name(), ".yaml") || strings.HasSuffix(info.Name(), ".yml") {
-			files = append(files, path)
-		}
-
+		files = append(files, path)
 		return nil
 	})
 
 	return files, err
 }
 
-// validateFile 驗證單個配置檔
-func validateFile(filePath string, rules []*rule.ValidationRule, rep *reporter.Reporter) error {
-	// 解析 YAML 檔案
-	p := parser.NewYAMLParser()
-	if err := p.ParseFile(filePath); err != nil {
-		return fmt.Errorf("解析檔案失敗: %w", err)
+// readStdinConfig 讀取標準輸入的配置檔，logicalPath 決定格式、產品檢測與規則匹配
+func readStdinConfig(detector *product.Detector, logicalPath string) (pipeline.File, error) {
+	content, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return pipeline.File{}, fmt.Errorf("讀取標準輸入失敗: %v", err)
+	}
+
+	cf := pipeline.File{
+		Path:      logicalPath,
+		MatchPath: pipeline.MatchPath(logicalPath),
+		Content:   content,
+	}
+	if cf.Format = pipeline.FileFormat(detector, cf); cf.Format == "" {
+		return pipeline.File{}, fmt.Errorf("不支援的檔案格式: %s", logicalPath)
+	}
+	return cf, nil
+}
+
+// replaceConfigFile 加入配置檔，已有相同路徑的檔案時取代它
+func replaceConfigFile(files []pipeline.File, cf pipeline.File) []pipeline.File {
+	for i, existing := range files {
+		if filepath.Clean(existing.Path) == filepath.Clean(cf.Path) {
+			files[i] = cf
+			return files
+		}
 	}
+	return append(files, cf)
+}
 
-	// 匹配適用的規則
-	matchedRules := rule.MatchRules(rules, filePath)
+// parseErrorLine 取出解析錯誤訊息中的行號，如 "yaml: line 15: ..." 或 "第 15 行: ..."
+var parseErrorLine = regexp.MustCompile(`(?:line |第 )(\d+)`)
 
-	// 執行每條規則
-	executor := rule.NewExecutor(p)
-	for _, r := range matchedRules {
-		results := executor.Execute(r, filePath)
-		rep.AddResults(results)
+// parseErrorResult 將解析失敗轉換為驗證結果，讓 lsp 與 watch 以一般的問題回報
+func parseErrorResult(path string, err error) *rule.ValidationResult {
+	line := 1
+	if m := parseErrorLine.FindStringSubmatch(err.Error()); m != nil {
+		line, _ = strconv.Atoi(m[1])
+	}
+	return &rule.ValidationResult{
+		File:     path,
+		RuleID:   "parse-error",
+		Severity: rule.SeverityError,
+		Message:  fmt.Sprintf("解析檔案失敗: %v", err),
+		Line:     line,
+		Column:   1,
 	}
+}
 
-	return nil
+// explainProducts 輸出檔案的產品檢測依據
+func explainProducts(path string, detections []*product.Detection) {
+	if len(detections) == 0 {
+		fmt.Fprintf(os.Stderr, "🔎 %s → （無）: %s\n", path, product.NoMatchReason)
+		return
+	}
+	for _, detection := range detections {
+		fmt.Fprintf(os.Stderr, "🔎 %s → %s: %s\n", path, detection.Product.Name, detection.Reason)
+	}
+}
+
+// runParallel 以最多 workers 個 goroutine 執行 fn(0) ~ fn(count-1)，不會中途取消
+func runParallel(workers, count int, fn func(i int)) {
+	pipeline.ForEach(context.Background(), workers, count, fn)
+}
+
+// validateJobs 驗證所有檔案，結果依檔案原本的順序加入輸出器
+func validateJobs(jobs []*pipeline.Job, workspace *rule.Workspace, numJobs int) *reporter.Reporter {
+	fileResults, _ := pipeline.ValidateAll(context.Background(), jobs, workspace, numJobs)
+
+	// 依檔案原本的順序加入結果，輸出不受排程順序影響
+	rep := reporter.NewReporter()
+	for i, job := range jobs {
+		rep.AddFileRun(job.Path, job.MatchedRules())
+		rep.AddResults(fileResults[i])
+	}
+	return rep
 }
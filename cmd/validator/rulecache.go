@@ -0,0 +1,150 @@
+package main
+
+import (
+	"config-validator/internal/product"
+	"config-validator/internal/rule"
+	"fmt"
+	"sort"
+)
+
+// ruleCache 依產品快取已載入的規則
+// 規則在產品第一次被用到時才載入，沒有檔案屬於的產品不會讀取規則目錄
+type ruleCache struct {
+	layers map[string]*rule.Layer // 各產品的分層規則設定（extends / shared_rules_dirs）
+	loader *rule.LayeredLoader
+	rules  map[string][]*rule.ValidationRule
+	order  []string // 產品第一次載入的順序
+}
+
+// newRuleCache 依產品配置建立規則快取
+func newRuleCache(detector *product.Detector) (*ruleCache, error) {
+	layers, err := detector.RuleLayers(resolveRulesDir)
+	if err != nil {
+		return nil, err
+	}
+	return &ruleCache{
+		layers: layers,
+		loader: rule.NewLayeredLoader(),
+		rules:  make(map[string][]*rule.ValidationRule),
+	}, nil
+}
+
+// load 返回產品的規則（含繼承的規則），第一次呼叫時載入並緩存
+func (c *ruleCache) load(name string) ([]*rule.ValidationRule, error) {
+	if rules, exists := c.rules[name]; exists {
+		return rules, nil
+	}
+
+	rules, err := c.loadLayer(name)
+	if err != nil {
+		return nil, err
+	}
+	c.rules[name] = rules
+	c.order = append(c.order, name)
+	return rules, nil
+}
+
+// loadAll 載入所有產品的規則
+// 載入後的快取只會被讀取，可以在多個 goroutine 間共用
+func (c *ruleCache) loadAll(products []product.ProductConfig) error {
+	for _, prod := range products {
+		if _, err := c.load(prod.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadLayer 從分層規則載入器取得產品的規則
+func (c *ruleCache) loadLayer(name string) ([]*rule.ValidationRule, error) {
+	layerRules, err := c.loader.Load(c.layers[name])
+	if err != nil {
+		return nil, fmt.Errorf("載入產品 %s 的規則失敗: %w", name, err)
+	}
+
+	// 繼承的規則可能被多個產品共用，複製後再標記所屬產品
+	rules := make([]*rule.ValidationRule, len(layerRules))
+	for i, r := range layerRules {
+		tagged := *r
+		tagged.Product = name
+		rules[i] = &tagged
+	}
+	return rules, nil
+}
+
+// reload 重新載入使用指定規則目錄的產品（含繼承它的產品），返回重新載入的產品名稱
+// 載入失敗的產品保留原本的規則
+func (c *ruleCache) reload(dir string) ([]string, error) {
+	c.loader.Invalidate(dir)
+
+	var reloaded []string
+	for _, name := range c.order {
+		if !c.layers[name].DependsOn(dir) {
+			continue
+		}
+		rules, err := c.loadLayer(name)
+		if err != nil {
+			return reloaded, err
+		}
+		c.rules[name] = rules
+		reloaded = append(reloaded, name)
+	}
+	return reloaded, nil
+}
+
+// dirs 返回已載入的產品用到的所有規則目錄（含繼承的上層）
+func (c *ruleCache) dirs() []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	var walk func(layer *rule.Layer)
+	walk = func(layer *rule.Layer) {
+		if layer.Dir != "" && !seen[layer.Dir] {
+			seen[layer.Dir] = true
+			dirs = append(dirs, layer.Dir)
+		}
+		for _, parent := range layer.Parents {
+			walk(parent)
+		}
+	}
+	for _, name := range c.order {
+		walk(c.layers[name])
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// all 依載入順序返回所有已載入的規則
+// 繼承的規則會複製到每個產品，同一個規則檔案（來源與 ID 相同）只列出一次
+func (c *ruleCache) all() []*rule.ValidationRule {
+	type ruleKey struct{ source, id string }
+	seen := make(map[ruleKey]bool)
+
+	var rules []*rule.ValidationRule
+	for _, name := range c.order {
+		for _, r := range c.rules[name] {
+			key := ruleKey{r.Source, r.ID}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+// printSummary 輸出載入的產品規則統計
+func (c *ruleCache) printSummary() {
+	if len(c.order) == 0 {
+		return
+	}
+
+	fmt.Printf("📋 載入了 %d 個產品的規則：\n", len(c.order))
+	names := make([]string, len(c.order))
+	copy(names, c.order)
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("   • %s: %d 條規則\n", name, len(c.rules[name]))
+	}
+	fmt.Println()
+}
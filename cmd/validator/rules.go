@@ -0,0 +1,84 @@
+package main
+
+import (
+	"config-validator/internal/product"
+	"config-validator/internal/rule"
+	"config-validator/internal/ruletest"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// runRulesTest 執行 `validator rules test [rules_dir...]`
+// 未指定目錄時測試 products.yaml 中所有產品的規則，返回退出碼
+func runRulesTest(args []string) int {
+	rulesDirs := args
+	if len(rulesDirs) == 0 {
+		detector, err := product.NewDetector(productsConfigPath())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "載入產品配置失敗: %v\n", err)
+			return 1
+		}
+		seen := make(map[string]bool)
+		for _, prod := range detector.Products() {
+			for _, dir := range append([]string{prod.RulesDir}, prod.SharedRulesDirs...) {
+				if dir == "" || seen[dir] {
+					continue
+				}
+				seen[dir] = true
+				rulesDirs = append(rulesDirs, resolveRulesDir(dir))
+			}
+		}
+	}
+
+	passed, failed, untested := 0, 0, 0
+	for _, dir := range rulesDirs {
+		rules, err := rule.NewLoader(dir).LoadRules()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "載入規則目錄 %s 失敗: %v\n", dir, err)
+			return 1
+		}
+
+		for _, r := range rules {
+			cases, err := ruletest.Run(r)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "執行規則 %s 的測試失敗: %v\n", r.ID, err)
+				return 1
+			}
+			if len(cases) == 0 {
+				untested++
+				continue
+			}
+
+			fmt.Printf("🧪 [%s] %s\n", r.ID, r.Name)
+			fixtureDir := ruletest.FixtureDir(r)
+			for _, c := range cases {
+				name, _ := filepath.Rel(fixtureDir, c.Fixture)
+				if c.Passed() {
+					passed++
+					fmt.Printf("   ✅ %s\n", filepath.ToSlash(name))
+					continue
+				}
+				failed++
+				fmt.Printf("   ❌ %s\n", filepath.ToSlash(name))
+				for _, f := range c.Failures {
+					fmt.Printf("      %s\n", f)
+				}
+			}
+		}
+	}
+
+	fmt.Println("==================================================")
+	fmt.Printf("✅ %d 個通過\n", passed)
+	if failed > 0 {
+		fmt.Printf("❌ %d 個失敗\n", failed)
+	}
+	if untested > 0 {
+		fmt.Printf("⚪ %d 條規則沒有測試 fixture\n", untested)
+	}
+
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
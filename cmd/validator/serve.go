@@ -0,0 +1,362 @@
+package main
+
+import (
+	"config-validator/internal/parser"
+	"config-validator/internal/pipeline"
+	"config-validator/internal/product"
+	"config-validator/internal/rule"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// shutdownTimeout 收到結束訊號後，等待處理中請求完成的時間
+const shutdownTimeout = 10 * time.Second
+
+// runServe 執行 `validator serve [--addr :8080]`，以 HTTP 提供驗證服務，返回退出碼
+func runServe(args []string) int {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", ":8080", "監聽位址")
+	maxBodyBytes := flags.Int64("max-body-bytes", 10<<20, "單一請求內容的大小上限（位元組）")
+	maxDocuments := flags.Int("max-documents", 1000, "單一請求的配置檔數量上限")
+	numJobs := flags.Int("jobs", runtime.NumCPU(), "單一請求中同時驗證的檔案數量")
+	readTimeout := flags.Duration("read-timeout", 30*time.Second, "讀取整個請求（含內容）的時間上限")
+	writeTimeout := flags.Duration("write-timeout", 60*time.Second, "從讀完請求標頭到寫完回應的時間上限（含驗證時間）")
+	flags.Parse(args)
+
+	if flags.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, "用法: validator serve [--addr :8080] [--max-body-bytes N] [--max-documents N] [--read-timeout 30s] [--write-timeout 60s]")
+		return 1
+	}
+
+	s := &validationServer{
+		maxBodyBytes: *maxBodyBytes,
+		maxDocuments: *maxDocuments,
+		numJobs:      *numJobs,
+	}
+	if err := s.reload(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           s.routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       *readTimeout,
+		WriteTimeout:      *writeTimeout,
+	}
+
+	// 收到 SIGINT / SIGTERM 時停止接受新連線，等待處理中的請求完成
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	done := make(chan error, 1)
+	go func() {
+		<-stop
+		fmt.Fprintln(os.Stderr, "👋 正在關閉服務...")
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		done <- srv.Shutdown(ctx)
+	}()
+
+	state := s.current()
+	fmt.Fprintf(os.Stderr, "🚀 驗證服務已啟動: %s（%d 個產品，%d 條規則）\n", *addr, len(state.detector.Products()), len(state.rules.all()))
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(os.Stderr, "啟動服務失敗: %v\n", err)
+		return 1
+	}
+	if err := <-done; err != nil {
+		fmt.Fprintf(os.Stderr, "關閉服務失敗: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// validationServer HTTP 驗證服務
+type validationServer struct {
+	maxBodyBytes int64
+	maxDocuments int
+	numJobs      int
+
+	mu    sync.RWMutex
+	state *serverState
+}
+
+// serverState 已載入的產品與規則
+// 所有產品的規則在載入時就全部讀取，之後只會被讀取，可以在多個請求間共用
+type serverState struct {
+	detector *product.Detector
+	rules    *ruleCache
+	loadedAt time.Time
+}
+
+// current 返回目前的產品與規則，重新載入不會影響已取得的狀態
+func (s *validationServer) current() *serverState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state
+}
+
+// reload 重新載入產品配置與所有規則，失敗時保留原本的狀態
+func (s *validationServer) reload() error {
+	detector, err := product.NewDetector(productsConfigPath())
+	if err != nil {
+		return fmt.Errorf("載入產品配置失敗: %v", err)
+	}
+	rules, err := newRuleCache(detector)
+	if err != nil {
+		return fmt.Errorf("載入產品配置失敗: %v", err)
+	}
+	if err := rules.loadAll(detector.Products()); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = &serverState{detector: detector, rules: rules, loadedAt: time.Now()}
+	return nil
+}
+
+// routes 建立路由
+func (s *validationServer) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/validate", allowMethod(http.MethodPost, s.handleValidate))
+	mux.HandleFunc("/v1/rules", allowMethod(http.MethodGet, s.handleRules))
+	mux.HandleFunc("/v1/products", allowMethod(http.MethodGet, s.handleProducts))
+	mux.HandleFunc("/v1/reload", allowMethod(http.MethodPost, s.handleReload))
+	return mux
+}
+
+// allowMethod 只允許指定的 HTTP 方法
+func allowMethod(method string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			writeError(w, http.StatusMethodNotAllowed, "不支援的方法: %s", r.Method)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// validateRequest POST /v1/validate 的請求內容
+type validateRequest struct {
+	Documents []*validateDocument `json:"documents"`
+}
+
+// validateDocument 要驗證的配置檔
+// path 為邏輯路徑（如 services/api/api-prod.yaml），用於產品檢測與規則的 file_patterns 匹配
+type validateDocument struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+	Format  string `json:"format,omitempty"` // 未指定時與 CLI 相同，依產品設定或副檔名判斷
+}
+
+// handleValidate 驗證請求中的配置檔，回應與 --format json 相同的結構
+// 無法識別產品的配置檔不驗證，列於回應的 skipped；跨檔案規則只會看到同一個請求中可以識別產品的配置檔
+func (s *validationServer) handleValidate(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, s.maxBodyBytes)
+	var req validateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, "請求內容超過 %d 位元組", s.maxBodyBytes)
+			return
+		}
+		writeError(w, http.StatusBadRequest, "解析請求失敗: %v", err)
+		return
+	}
+	if len(req.Documents) == 0 {
+		writeError(w, http.StatusBadRequest, "documents 不能為空")
+		return
+	}
+	if len(req.Documents) > s.maxDocuments {
+		writeError(w, http.StatusRequestEntityTooLarge, "documents 超過 %d 個", s.maxDocuments)
+		return
+	}
+
+	jobs, skipped, status, err := s.buildJobs(r.Context(), s.current(), req.Documents)
+	if err != nil {
+		writeError(w, status, "%v", err)
+		return
+	}
+	rep := validateJobs(jobs, pipeline.NewWorkspace(jobs), s.numJobs)
+	for _, path := range skipped {
+		rep.AddSkipped(path)
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := rep.WriteJSON(w); err != nil {
+		fmt.Fprintf(os.Stderr, "輸出結果失敗: %v\n", err)
+	}
+}
+
+// buildJobs 解析請求中的配置檔並檢測產品，返回驗證工作與無法識別產品的配置檔路徑
+// 錯誤時返回對應的 HTTP 狀態碼
+func (s *validationServer) buildJobs(ctx context.Context, state *serverState, docs []*validateDocument) ([]*pipeline.Job, []string, int, error) {
+	seen := make(map[string]bool)
+	configFiles := make([]pipeline.File, len(docs))
+	for i, doc := range docs {
+		if doc.Path == "" {
+			return nil, nil, http.StatusBadRequest, fmt.Errorf("documents[%d] 缺少 path", i)
+		}
+		logical := strings.TrimPrefix(path.Clean(strings.ReplaceAll(doc.Path, "\\", "/")), "./")
+		if seen[logical] {
+			return nil, nil, http.StatusBadRequest, fmt.Errorf("path 重複: %s", logical)
+		}
+		seen[logical] = true
+
+		cf := pipeline.File{Path: logical, MatchPath: logical, Format: doc.Format, Content: []byte(doc.Content)}
+		if cf.Format == "" {
+			cf.Format = pipeline.FileFormat(state.detector, cf)
+		}
+		if !parser.IsFormat(cf.Format) {
+			return nil, nil, http.StatusUnprocessableEntity, fmt.Errorf("%s: 不支援的檔案格式", logical)
+		}
+		configFiles[i] = cf
+	}
+
+	jobs, unknown, err := pipeline.Prepare(ctx, state.detector, configFiles, state.rules.load, s.numJobs, nil)
+	if err != nil {
+		var parseErr *pipeline.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, nil, http.StatusUnprocessableEntity, err
+		}
+		return nil, nil, http.StatusInternalServerError, err
+	}
+	var skipped []string
+	for _, cf := range unknown {
+		skipped = append(skipped, cf.Path)
+	}
+	return jobs, skipped, http.StatusOK, nil
+}
+
+// ruleInfo GET /v1/rules 回應中的規則
+type ruleInfo struct {
+	ID           string        `json:"id"`
+	Name         string        `json:"name"`
+	Product      string        `json:"product"`
+	Severity     rule.Severity `json:"severity"`
+	Type         rule.RuleType `json:"type"`
+	Description  string        `json:"description,omitempty"`
+	FilePatterns []string      `json:"file_patterns"`
+	Source       string        `json:"source"`
+}
+
+// handleRules 列出所有產品的規則，可用 ?product=api 篩選
+func (s *validationServer) handleRules(w http.ResponseWriter, r *http.Request) {
+	state := s.current()
+	filter := r.URL.Query().Get("product")
+
+	rules := []*ruleInfo{}
+	for _, prod := range state.detector.Products() {
+		if filter != "" && prod.Name != filter {
+			continue
+		}
+		productRules, _ := state.rules.load(prod.Name)
+		for _, r := range productRules {
+			rules = append(rules, &ruleInfo{
+				ID:           r.ID,
+				Name:         r.Name,
+				Product:      r.Product,
+				Severity:     r.Severity,
+				Type:         r.Rule.Type,
+				Description:  r.Description,
+				FilePatterns: r.Targets.FilePatterns,
+				Source:       r.Source,
+			})
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"total": len(rules),
+		"rules": rules,
+	})
+}
+
+// productInfo GET /v1/products 回應中的產品
+type productInfo struct {
+	Name            string   `json:"name"`
+	Description     string   `json:"description,omitempty"`
+	RulesDir        string   `json:"rules_dir,omitempty"`
+	PathPatterns    []string `json:"path_patterns"`
+	Format          string   `json:"format,omitempty"`
+	ContentMatch    bool     `json:"content_match"`
+	MultiProduct    bool     `json:"multi_product"`
+	Extends         []string `json:"extends,omitempty"`
+	SharedRulesDirs []string `json:"shared_rules_dirs,omitempty"`
+	DisabledRules   []string `json:"disabled_rules,omitempty"`
+	RuleCount       int      `json:"rule_count"`
+}
+
+// handleProducts 列出所有產品與規則數量
+func (s *validationServer) handleProducts(w http.ResponseWriter, r *http.Request) {
+	state := s.current()
+
+	products := []*productInfo{}
+	for _, prod := range state.detector.Products() {
+		productRules, _ := state.rules.load(prod.Name)
+		products = append(products, &productInfo{
+			Name:            prod.Name,
+			Description:     prod.Description,
+			RulesDir:        prod.RulesDir,
+			PathPatterns:    prod.PathPatterns,
+			Format:          prod.Format,
+			ContentMatch:    prod.ContentMatch != nil,
+			MultiProduct:    prod.MultiProduct,
+			Extends:         prod.Extends,
+			SharedRulesDirs: prod.SharedRulesDirs,
+			DisabledRules:   prod.DisabledRules,
+			RuleCount:       len(productRules),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"total":    len(products),
+		"products": products,
+	})
+}
+
+// handleReload 重新載入產品配置與規則，失敗時繼續使用原本的設定
+func (s *validationServer) handleReload(w http.ResponseWriter, r *http.Request) {
+	if err := s.reload(); err != nil {
+		writeError(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+
+	state := s.current()
+	fmt.Fprintf(os.Stderr, "🔄 已重新載入 %d 個產品，%d 條規則\n", len(state.detector.Products()), len(state.rules.all()))
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"products":  len(state.detector.Products()),
+		"rules":     len(state.rules.all()),
+		"loaded_at": state.loadedAt.Format(time.RFC3339),
+	})
+}
+
+// writeJSON 輸出 JSON 回應
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	data, err := json.MarshalIndent(body, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(append(data, '\n'))
+}
+
+// writeError 以 {"error": "..."} 輸出錯誤
+func writeError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	writeJSON(w, status, map[string]string{"error": fmt.Sprintf(format, args...)})
+}
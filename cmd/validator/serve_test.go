@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestServer 在暫存目錄中建立只有 app 產品的驗證服務
+func newTestServer(t *testing.T) *validationServer {
+	t.Helper()
+	chdir(t, t.TempDir())
+	writeFile(t, "products.yaml", `products:
+  - name: app
+    rules_dir: rules/app
+    path_patterns: ["**/app*.yaml"]
+`)
+	writeFile(t, "rules/app/app-001.yaml", `id: app-001
+name: "name 必填"
+enabled: true
+severity: error
+targets:
+  file_patterns: ["**/app*.yaml"]
+rule:
+  type: required_field
+  path: name
+  message: "缺少 name"
+`)
+	s := &validationServer{maxBodyBytes: 1 << 20, maxDocuments: 10, numJobs: 2}
+	if err := s.reload(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	return s
+}
+
+// postValidate 送出驗證請求，返回狀態碼與解碼後的回應
+func postValidate(t *testing.T, s *validationServer, body string) (int, map[string]interface{}) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/v1/validate", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, req)
+
+	var output map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &output); err != nil {
+		t.Fatalf("回應不是 JSON: %s", rec.Body.String())
+	}
+	return rec.Code, output
+}
+
+func TestServeValidateSkipped(t *testing.T) {
+	s := newTestServer(t)
+
+	// 無法識別產品的配置檔列於 skipped，其他配置檔照常驗證
+	status, output := postValidate(t, s, `{"documents": [
+		{"path": "configs/app.yaml", "content": "port: 8080\n"},
+		{"path": "configs/other.yaml", "content": "a: 1\n"}
+	]}`)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %v", status, output)
+	}
+	if output["total"] != 1.0 {
+		t.Errorf("total = %v, want 1", output["total"])
+	}
+	if skipped, _ := output["skipped"].([]interface{}); len(skipped) != 1 || skipped[0] != "configs/other.yaml" {
+		t.Errorf("skipped = %v, want [configs/other.yaml]", output["skipped"])
+	}
+
+	// 全部無法識別產品時仍回應 200
+	status, output = postValidate(t, s, `{"documents": [{"path": "other.yaml", "content": "a: 1\n"}]}`)
+	if status != http.StatusOK || output["total"] != 0.0 {
+		t.Errorf("status = %d, output = %v", status, output)
+	}
+}
+
+func TestServeValidateErrors(t *testing.T) {
+	s := newTestServer(t)
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"不是 JSON", `{`, http.StatusBadRequest},
+		{"documents 為空", `{"documents": []}`, http.StatusBadRequest},
+		{"缺少 path", `{"documents": [{"content": "a: 1"}]}`, http.StatusBadRequest},
+		{"path 重複", `{"documents": [{"path": "app.yaml"}, {"path": "./app.yaml"}]}`, http.StatusBadRequest},
+		{"不支援的格式", `{"documents": [{"path": "app.txt", "content": "a"}]}`, http.StatusUnprocessableEntity},
+		{"無法解析", `{"documents": [{"path": "app.yaml", "content": "a: [1"}]}`, http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		status, output := postValidate(t, s, tt.body)
+		if status != tt.status || output["error"] == nil {
+			t.Errorf("%s: status = %d, output = %v, want %d", tt.name, status, output, tt.status)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"config-validator/internal/pipeline"
+	"testing"
+)
+
+func TestReplaceConfigFile(t *testing.T) {
+	files := []pipeline.File{{Path: "configs/app.yaml"}, {Path: "configs/db.yaml"}}
+
+	// 相同路徑（清理後）的磁碟檔案以標準輸入的內容取代，位置不變
+	stdin := pipeline.File{Path: "./configs/db.yaml", Content: []byte("name: db\n")}
+	files = replaceConfigFile(files, stdin)
+	if len(files) != 2 || files[1].Content == nil {
+		t.Errorf("replaceConfigFile() = %v, want 取代 configs/db.yaml", files)
+	}
+
+	// 沒有相同路徑時加在最後
+	files = replaceConfigFile(files, pipeline.File{Path: "new.yaml", Content: []byte{}})
+	if len(files) != 3 || files[2].Path != "new.yaml" {
+		t.Errorf("replaceConfigFile() = %v, want 加入 new.yaml", files)
+	}
+}
@@ -0,0 +1,467 @@
+package main
+
+import (
+	"config-validator/internal/baseline"
+	"config-validator/internal/pipeline"
+	"config-validator/internal/product"
+	"config-validator/internal/reporter"
+	"config-validator/internal/rule"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// runWatch 執行 `validator watch [--interval 500ms] <path...>`
+// 產品配置、規則與已解析的檔案都保留在記憶體中，只重新驗證有變更的檔案，返回退出碼
+func runWatch(args []string) int {
+	flags := flag.NewFlagSet("watch", flag.ExitOnError)
+	interval := flags.Duration("interval", 500*time.Millisecond, "檢查檔案變更的間隔")
+	numJobs := flags.Int("jobs", runtime.NumCPU(), "同時驗證的檔案數量")
+	flags.Parse(args)
+
+	if flags.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "用法: validator watch [--interval 500ms] [--jobs N] <path1> [path2] ...")
+		return 1
+	}
+
+	w := &watcher{
+		paths:        flags.Args(),
+		numJobs:      *numJobs,
+		productsPath: productsConfigPath(),
+	}
+	if err := w.load(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	w.printInitial()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			fmt.Println("👋 停止監看")
+			return 0
+		case <-ticker.C:
+			w.poll()
+		}
+	}
+}
+
+// watcher watch 模式的狀態
+type watcher struct {
+	paths        []string
+	numJobs      int
+	productsPath string
+
+	productsStamp string
+	detector      *product.Detector
+	rules         *ruleCache
+	ruleStamps    map[string]string // 規則目錄 -> 內容簽章
+	workspace     *rule.Workspace
+	files         map[string]*watchedFile
+	results       map[string][]*rule.ValidationResult // 各檔案目前的驗證結果
+	lastError     string                              // 最後一次掃描錯誤，避免重複輸出
+}
+
+// watchedFile 監看中的配置檔
+type watchedFile struct {
+	config pipeline.File
+	stamp  string
+	job    *pipeline.Job // 無法識別產品或從未成功解析時為 nil
+
+	// parseError 最後一次解析失敗的結果，成功解析後清除
+	// 解析失敗時 job 保留上一次成功解析的內容供跨檔案規則查詢，但檔案本身只回報解析錯誤
+	parseError *rule.ValidationResult
+}
+
+// load 載入產品配置、規則與所有配置檔，並驗證所有檔案
+// 失敗時不會修改目前的狀態
+func (w *watcher) load() error {
+	detector, err := product.NewDetector(w.productsPath)
+	if err != nil {
+		return fmt.Errorf("載入產品配置失敗: %v", err)
+	}
+	rules, err := newRuleCache(detector)
+	if err != nil {
+		return fmt.Errorf("載入產品配置失敗: %v", err)
+	}
+	configFiles, skipped, err := collectConfigFiles(detector, w.paths)
+	if err != nil {
+		return err
+	}
+	for _, path := range skipped {
+		fmt.Fprintf(os.Stderr, "⚠️  跳過不支援格式的檔案: %s\n", path)
+	}
+
+	next := &watcher{
+		paths:         w.paths,
+		numJobs:       w.numJobs,
+		productsPath:  w.productsPath,
+		productsStamp: fileStamp(w.productsPath),
+		detector:      detector,
+		rules:         rules,
+		ruleStamps:    make(map[string]string),
+		workspace:     rule.NewWorkspace(),
+		files:         make(map[string]*watchedFile),
+		results:       make(map[string][]*rule.ValidationResult),
+	}
+	for _, cf := range configFiles {
+		if _, err := next.track(cf); err != nil {
+			return err
+		}
+	}
+	next.trackRuleDirs()
+
+	next.validate(next.jobPaths())
+	*w = *next
+	return nil
+}
+
+// track 解析並加入新的配置檔，返回是否有可以驗證的工作
+// 只有載入規則失敗時返回錯誤；解析失敗或無法識別產品的檔案會保留在監看清單中，等待下次變更
+func (w *watcher) track(cf pipeline.File) (bool, error) {
+	file := &watchedFile{config: cf, stamp: fileStamp(cf.Path)}
+	w.files[cf.Path] = file
+	return w.refresh(file)
+}
+
+// refresh 重新解析並檢測檔案的產品
+// 解析失敗時以 parse-error 結果取代檔案目前的結果，並保留上一次成功解析的內容
+func (w *watcher) refresh(file *watchedFile) (bool, error) {
+	p, err := file.config.Parse()
+	if err != nil {
+		file.parseError = parseErrorResult(file.config.Path, err)
+		w.results[file.config.Path] = []*rule.ValidationResult{file.parseError}
+		return false, nil
+	}
+	file.parseError = nil
+
+	detections := w.detector.Detect(file.config.MatchPath, p)
+	if len(detections) == 0 {
+		if file.job != nil {
+			w.untrackJob(file)
+		}
+		fmt.Fprintf(os.Stderr, "⚠️  無法識別配置檔 %s 的產品類型，跳過驗證\n", file.config.Path)
+		return false, nil
+	}
+
+	job, err := pipeline.NewJob(file.config, p, detections, w.rules.load)
+	if err != nil {
+		return false, err
+	}
+	file.job = job
+	w.workspace.Add(job.Path, job.MatchPath, job.Parser)
+	return true, nil
+}
+
+// untrackJob 移除檔案的驗證工作與結果
+func (w *watcher) untrackJob(file *watchedFile) {
+	file.job = nil
+	w.workspace.Remove(file.config.Path)
+	delete(w.results, file.config.Path)
+}
+
+// trackRuleDirs 記錄目前用到的規則目錄的內容簽章（已記錄的目錄不變）
+func (w *watcher) trackRuleDirs() {
+	for _, dir := range w.rules.dirs() {
+		if _, exists := w.ruleStamps[dir]; !exists {
+			w.ruleStamps[dir] = dirStamp(dir)
+		}
+	}
+}
+
+// jobPaths 返回所有有驗證工作的檔案路徑（已排序）
+func (w *watcher) jobPaths() []string {
+	var paths []string
+	for path, file := range w.files {
+		if file.job != nil {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// validate 重新驗證指定的檔案並更新結果
+func (w *watcher) validate(paths []string) {
+	fileResults := make([][]*rule.ValidationResult, len(paths))
+	runParallel(w.numJobs, len(paths), func(i int) {
+		file := w.files[paths[i]]
+		if file.parseError != nil {
+			fileResults[i] = []*rule.ValidationResult{file.parseError}
+			return
+		}
+		fileResults[i] = file.job.Validate(w.workspace)
+	})
+	for i, path := range paths {
+		w.results[path] = fileResults[i]
+	}
+}
+
+// printInitial 輸出第一次完整驗證的結果（含解析失敗的檔案）
+func (w *watcher) printInitial() {
+	rep := reporter.NewReporter()
+	for _, path := range sortedKeys(w.results) {
+		if job := w.files[path].job; job != nil {
+			rep.AddFileRun(job.Path, job.MatchedRules())
+		}
+		rep.AddResults(w.results[path])
+	}
+	w.rules.printSummary()
+	rep.PrintConsole(len(w.rules.all()))
+	fmt.Println()
+	fmt.Printf("👀 監看 %d 個配置檔與 %d 個規則目錄的變更（Ctrl+C 結束）\n", len(w.files), len(w.ruleStamps))
+}
+
+// poll 檢查變更並重新驗證受影響的檔案
+func (w *watcher) poll() {
+	before := w.activeResults()
+
+	// 產品配置變更時重新載入所有內容
+	if stamp := fileStamp(w.productsPath); stamp != w.productsStamp {
+		w.productsStamp = stamp
+		if err := w.load(); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v（保留原本的設定）\n", err)
+			return
+		}
+		w.printChanges([]string{w.productsPath}, before)
+		return
+	}
+
+	var changes []string
+	revalidate := make(map[string]bool)
+
+	// 規則目錄變更時重新載入使用該目錄的產品規則
+	for _, dir := range sortedKeys(w.ruleStamps) {
+		stamp := dirStamp(dir)
+		if stamp == w.ruleStamps[dir] {
+			continue
+		}
+		w.ruleStamps[dir] = stamp
+		changes = append(changes, dir)
+
+		reloaded, err := w.rules.reload(dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v（保留原本的規則）\n", err)
+		}
+		for _, file := range w.files {
+			if file.job == nil || !containsAny(file.job.Products, reloaded) {
+				continue
+			}
+			if err := file.job.LoadRules(w.rules.load); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+				continue
+			}
+			revalidate[file.config.Path] = true
+		}
+	}
+
+	// 配置檔的新增、修改與刪除
+	fileChanges := w.pollFiles(revalidate)
+	changes = append(changes, fileChanges...)
+	filesChanged := len(fileChanges) > 0
+
+	if len(changes) == 0 {
+		return
+	}
+	w.trackRuleDirs()
+
+	// 跨檔案規則會查詢其他檔案，任何配置檔變更時都要重新執行
+	if filesChanged {
+		for path, file := range w.files {
+			if file.job != nil && file.job.UsesWorkspace() {
+				revalidate[path] = true
+			}
+		}
+	}
+
+	w.validate(sortedKeys(revalidate))
+	w.printChanges(changes, before)
+}
+
+// pollFiles 處理配置檔的新增、修改與刪除，需要重新驗證的檔案加入 revalidate，返回有變更的檔案
+func (w *watcher) pollFiles(revalidate map[string]bool) []string {
+	configFiles, _, err := collectConfigFiles(w.detector, w.paths)
+	if err != nil {
+		if err.Error() != w.lastError {
+			fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+			w.lastError = err.Error()
+		}
+		return nil
+	}
+	w.lastError = ""
+
+	var changes []string
+	current := make(map[string]bool)
+	for _, cf := range configFiles {
+		current[cf.Path] = true
+		stamp := fileStamp(cf.Path)
+		file, exists := w.files[cf.Path]
+		if exists && file.stamp == stamp {
+			continue
+		}
+
+		changes = append(changes, cf.Path)
+		var ok bool
+		if exists {
+			file.stamp = stamp
+			ok, err = w.refresh(file)
+		} else {
+			ok, err = w.track(cf)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			continue
+		}
+		if ok {
+			revalidate[cf.Path] = true
+		}
+	}
+
+	for _, path := range sortedKeys(w.files) {
+		if current[path] {
+			continue
+		}
+		changes = append(changes, path)
+		w.untrackJob(w.files[path])
+		delete(w.files, path)
+	}
+	return changes
+}
+
+// activeResults 返回所有檔案目前未被抑制的結果（依檔案排序）
+func (w *watcher) activeResults() []*rule.ValidationResult {
+	var active []*rule.ValidationResult
+	for _, path := range sortedKeys(w.results) {
+		for _, result := range w.results[path] {
+			if !result.Suppressed {
+				active = append(active, result)
+			}
+		}
+	}
+	return active
+}
+
+// printChanges 輸出變更前後新增與已解決的問題，以及目前的統計
+// 問題以 baseline 的指紋比對，行號變動不會被視為新問題
+func (w *watcher) printChanges(changes []string, before []*rule.ValidationResult) {
+	after := w.activeResults()
+	added := newResults(before, after)
+	resolved := newResults(after, before)
+
+	fmt.Printf("\n[%s] 🔄 變更: %s\n", time.Now().Format("15:04:05"), strings.Join(changes, ", "))
+	for _, result := range added {
+		fmt.Printf("  🆕 %s %s [%s] %s\n", severityIcon(result.Severity), result.File, result.RuleID, result.RuleName)
+		fmt.Printf("       %s\n", result.Message)
+		if result.Path != "" {
+			fmt.Printf("       路徑: %s\n", result.Path)
+		}
+		if result.Line > 0 {
+			fmt.Printf("       位置: %d:%d\n", result.Line, result.Column)
+		}
+	}
+	for _, result := range resolved {
+		fmt.Printf("  ✅ %s %s [%s] %s\n", severityIcon(result.Severity), result.File, result.RuleID, result.RuleName)
+		if result.Path != "" {
+			fmt.Printf("       路徑: %s\n", result.Path)
+		}
+	}
+
+	errors, warnings := 0, 0
+	for _, result := range after {
+		switch result.Severity {
+		case rule.SeverityError:
+			errors++
+		case rule.SeverityWarning:
+			warnings++
+		}
+	}
+	fmt.Printf("📊 新增 %d 個、解決 %d 個｜目前 ❌ %d 個錯誤、⚠️  %d 個警告\n", len(added), len(resolved), errors, warnings)
+}
+
+// newResults 返回 after 中不在 before 裡的結果
+func newResults(before, after []*rule.ValidationResult) []*rule.ValidationResult {
+	matcher := baseline.FromResults(before, "").NewMatcher("")
+	var added []*rule.ValidationResult
+	for _, result := range after {
+		if !matcher.Match(result) {
+			added = append(added, result)
+		}
+	}
+	return added
+}
+
+// severityIcon 嚴重程度圖示
+func severityIcon(severity rule.Severity) string {
+	switch severity {
+	case rule.SeverityError:
+		return "❌"
+	case rule.SeverityWarning:
+		return "⚠️ "
+	default:
+		return "ℹ️ "
+	}
+}
+
+// fileStamp 以修改時間與大小作為檔案的簽章，檔案不存在時返回空字串
+func fileStamp(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d:%d", info.ModTime().UnixNano(), info.Size())
+}
+
+// dirStamp 規則目錄中所有規則檔案的簽章（不含規則測試的 fixture 目錄）
+func dirStamp(dir string) string {
+	var sb strings.Builder
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if path != dir && strings.HasSuffix(info.Name(), rule.TestDirSuffix) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml") {
+			fmt.Fprintf(&sb, "%s=%d:%d\n", path, info.ModTime().UnixNano(), info.Size())
+		}
+		return nil
+	})
+	return sb.String()
+}
+
+// sortedKeys 返回 map 排序後的 key
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// containsAny 檢查兩個字串列表是否有共同的項目
+func containsAny(list, items []string) bool {
+	for _, a := range list {
+		for _, b := range items {
+			if a == b {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFile 寫入測試檔案，必要時建立目錄
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// chdir 切換工作目錄，測試結束時切換回來（產品配置中的規則目錄相對於工作目錄）
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+// ruleIDs 返回結果的規則 ID
+func ruleIDs(w *watcher, path string) []string {
+	var list []string
+	for _, result := range w.results[path] {
+		list = append(list, result.RuleID)
+	}
+	return list
+}
+
+func TestWatchParseError(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	writeFile(t, "products.yaml", `products:
+  - name: app
+    rules_dir: rules/app
+    path_patterns: ["**/app*.yaml"]
+`)
+	writeFile(t, "rules/app/app-001.yaml", `id: app-001
+name: "name 必填"
+enabled: true
+severity: error
+targets:
+  file_patterns: ["**/app*.yaml"]
+rule:
+  type: required_field
+  path: name
+  message: "缺少 name"
+`)
+	configPath := filepath.Join("configs", "app.yaml")
+	writeFile(t, configPath, "port: 8080\n")
+
+	w := &watcher{paths: []string{"configs"}, numJobs: 1, productsPath: "products.yaml"}
+	if err := w.load(); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := ruleIDs(w, configPath); len(got) != 1 || got[0] != "app-001" {
+		t.Fatalf("初始結果 = %v, want [app-001]", got)
+	}
+
+	// 解析失敗時以 parse-error 取代原本的結果，顯示為新增的問題
+	before := w.activeResults()
+	writeFile(t, configPath, "port: [8080\n")
+	w.poll()
+	if got := ruleIDs(w, configPath); len(got) != 1 || got[0] != "parse-error" {
+		t.Fatalf("解析失敗後的結果 = %v, want [parse-error]", got)
+	}
+	if added := newResults(before, w.activeResults()); len(added) != 1 || added[0].RuleID != "parse-error" {
+		t.Errorf("新增的問題 = %v, want [parse-error]", added)
+	}
+	if file := w.files[configPath]; file.job == nil {
+		t.Error("解析失敗時應保留上一次成功解析的內容")
+	}
+
+	// 修正後 parse-error 顯示為已解決
+	before = w.activeResults()
+	writeFile(t, configPath, "name: app\nport: 8080\n")
+	w.poll()
+	if got := ruleIDs(w, configPath); len(got) != 0 {
+		t.Fatalf("修正後的結果 = %v, want []", got)
+	}
+	if resolved := newResults(w.activeResults(), before); len(resolved) != 1 || resolved[0].RuleID != "parse-error" {
+		t.Errorf("已解決的問題 = %v, want [parse-error]", resolved)
+	}
+}
+
+func TestParseErrorResult(t *testing.T) {
+	tests := []struct {
+		message string
+		line    int
+	}{
+		{"yaml: line 15: did not find expected key", 15},
+		{"第 3 行: 缺少值", 3},
+		{"unexpected EOF", 1},
+	}
+	for _, tt := range tests {
+		result := parseErrorResult("a.yaml", errors.New(tt.message))
+		if result.Line != tt.line || result.RuleID != "parse-error" {
+			t.Errorf("parseErrorResult(%q) = line %d, %s, want line %d", tt.message, result.Line, result.RuleID, tt.line)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package baseline
+
+import (
+	"config-validator/internal/rule"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// currentVersion baseline 檔案格式版本
+const currentVersion = 1
+
+// Baseline 已知問題的記錄
+// 以檔案、規則 ID、路徑與值的指紋識別問題，不包含行號，因此不受無關的行數變動影響
+// 檔案路徑相對於根目錄（通常為 git 儲存庫的根目錄）記錄，從不同目錄執行或指定不同的掃描路徑都能對應
+type Baseline struct {
+	Version int      `json:"version"`
+	Entries []*Entry `json:"entries"`
+}
+
+// Entry 單一已知問題
+type Entry struct {
+	File        string `json:"file"`               // 相對於根目錄的路徑
+	Document    int    `json:"document,omitempty"` // 多文件檔案中的文件序號
+	RuleID      string `json:"rule_id"`
+	Path        string `json:"path"` // 文件內的路徑，不含 [doc N] 前綴
+	Fingerprint string `json:"fingerprint"`
+}
+
+// FromResults 由驗證結果建立 baseline（不含被抑制的結果）
+// root 為記錄檔案路徑時的根目錄，空字串表示直接使用結果中的路徑
+func FromResults(results []*rule.ValidationResult, root string) *Baseline {
+	b := &Baseline{Version: currentVersion, Entries: make([]*Entry, 0, len(results))}
+	for _, result := range results {
+		if result.Suppressed {
+			continue
+		}
+		b.Entries = append(b.Entries, entryOf(result, root))
+	}
+
+	// 排序讓檔案內容穩定，方便 code review
+	sort.SliceStable(b.Entries, func(i, j int) bool {
+		a, c := b.Entries[i], b.Entries[j]
+		if a.File != c.File {
+			return a.File < c.File
+		}
+		if a.Document != c.Document {
+			return a.Document < c.Document
+		}
+		if a.RuleID != c.RuleID {
+			return a.RuleID < c.RuleID
+		}
+		if a.Path != c.Path {
+			return a.Path < c.Path
+		}
+		return a.Fingerprint < c.Fingerprint
+	})
+	return b
+}
+
+// Load 載入 baseline 檔案
+func Load(path string) (*Baseline, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("讀取 baseline 失敗: %w", err)
+	}
+
+	var b Baseline
+	if err := json.Unmarshal(content, &b); err != nil {
+		return nil, fmt.Errorf("解析 baseline 失敗: %w", err)
+	}
+	if b.Version != currentVersion {
+		return nil, fmt.Errorf("不支援的 baseline 版本: %d", b.Version)
+	}
+	return &b, nil
+}
+
+// Write 寫入 baseline 檔案
+func (b *Baseline) Write(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("生成 baseline 失敗: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("寫入 baseline 失敗: %w", err)
+	}
+	return nil
+}
+
+// Matcher 比對結果是否已記錄在 baseline 中
+// 同一個問題出現多次時，baseline 中記錄幾次就只會忽略幾次
+type Matcher struct {
+	root      string
+	remaining map[Entry]int
+}
+
+// NewMatcher 建立比對器，root 需與建立 baseline 時相同
+func (b *Baseline) NewMatcher(root string) *Matcher {
+	m := &Matcher{root: root, remaining: make(map[Entry]int, len(b.Entries))}
+	for _, entry := range b.Entries {
+		m.remaining[*entry]++
+	}
+	return m
+}
+
+// Match 檢查結果是否在 baseline 中，符合時會消耗一筆記錄
+func (m *Matcher) Match(result *rule.ValidationResult) bool {
+	key := *entryOf(result, m.root)
+	if m.remaining[key] == 0 {
+		return false
+	}
+	m.remaining[key]--
+	return true
+}
+
+// entryOf 建立結果的 baseline 記錄
+func entryOf(result *rule.ValidationResult, root string) *Entry {
+	path := result.Path
+	if result.Document > 0 {
+		path = strings.TrimSpace(strings.TrimPrefix(path, fmt.Sprintf("[doc %d]", result.Document)))
+	}
+	return &Entry{
+		File:        relativePath(root, result.File),
+		Document:    result.Document,
+		RuleID:      result.RuleID,
+		Path:        path,
+		Fingerprint: fingerprint(result),
+	}
+}
+
+// relativePath 計算檔案相對於 root 的路徑，不在 root 之下時使用原本的路徑
+func relativePath(root, file string) string {
+	if root == "" {
+		return filepath.ToSlash(file)
+	}
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return filepath.ToSlash(file)
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(file)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// fingerprint 計算結果的值指紋（實際值與訊息）
+func fingerprint(result *rule.ValidationResult) string {
+	sum := sha256.Sum256([]byte(result.ActualValue + "\x00" + result.Message))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,123 @@
+package baseline
+
+import (
+	"config-validator/internal/rule"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFromResults(t *testing.T) {
+	results := []*rule.ValidationResult{
+		{File: "b.yaml", RuleID: "r1", Path: "port", Message: "m"},
+		{File: "a.yaml", RuleID: "r2", Path: "[doc 3] host", Document: 3, Message: "m"},
+		{File: "a.yaml", RuleID: "r1", Path: "[doc 3]", Document: 3, Message: "m"},
+		{File: "a.yaml", RuleID: "r1", Path: "name", Document: 1, Message: "m"},
+		{File: "a.yaml", RuleID: "r1", Path: "x", Message: "m", Suppressed: true},
+	}
+
+	b := FromResults(results, "")
+	want := []Entry{
+		{File: "a.yaml", Document: 1, RuleID: "r1", Path: "name"},
+		{File: "a.yaml", Document: 3, RuleID: "r1", Path: ""},
+		{File: "a.yaml", Document: 3, RuleID: "r2", Path: "host"},
+		{File: "b.yaml", RuleID: "r1", Path: "port"},
+	}
+	if len(b.Entries) != len(want) {
+		t.Fatalf("entries = %d, want %d", len(b.Entries), len(want))
+	}
+	for i, entry := range b.Entries {
+		got := *entry
+		got.Fingerprint = ""
+		if got != want[i] {
+			t.Errorf("entries[%d] = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	root := t.TempDir()
+	result := func(file, message string) *rule.ValidationResult {
+		return &rule.ValidationResult{File: file, RuleID: "r1", Path: "[doc 2] port", Document: 2, Message: message, ActualValue: "80"}
+	}
+
+	// 以絕對路徑記錄，以不同形式的路徑比對
+	b := FromResults([]*rule.ValidationResult{
+		result(filepath.Join(root, "configs", "api.yaml"), "m"),
+		result(filepath.Join(root, "configs", "api.yaml"), "m"),
+	}, root)
+	if got := b.Entries[0].File; got != "configs/api.yaml" {
+		t.Fatalf("File = %q, want configs/api.yaml", got)
+	}
+
+	m := b.NewMatcher(root)
+	if !m.Match(result(filepath.Join(root, "configs", "..", "configs", "api.yaml"), "m")) {
+		t.Error("相同的問題應符合")
+	}
+	if m.Match(result(filepath.Join(root, "configs", "api.yaml"), "訊息不同")) {
+		t.Error("訊息不同時不應符合")
+	}
+	if !m.Match(result(filepath.Join(root, "configs", "api.yaml"), "m")) {
+		t.Error("記錄兩次的問題應符合兩次")
+	}
+	if m.Match(result(filepath.Join(root, "configs", "api.yaml"), "m")) {
+		t.Error("記錄兩次的問題不應符合第三次")
+	}
+
+	// 文件序號不同時不符合
+	other := result(filepath.Join(root, "configs", "api.yaml"), "m")
+	other.Document, other.Path = 3, "[doc 3] port"
+	if b.NewMatcher(root).Match(other) {
+		t.Error("不同文件的問題不應符合")
+	}
+}
+
+func TestRelativePath(t *testing.T) {
+	root := t.TempDir()
+	outside := filepath.Join(filepath.Dir(root), "other", "a.yaml")
+	tests := []struct {
+		root, file, want string
+	}{
+		{"", "configs/a.yaml", "configs/a.yaml"},
+		{root, filepath.Join(root, "a.yaml"), "a.yaml"},
+		{root, filepath.Join(root, "sub", "a.yaml"), "sub/a.yaml"},
+		{root, outside, filepath.ToSlash(outside)}, // 不在根目錄之下
+	}
+	for _, tt := range tests {
+		if got := relativePath(tt.root, tt.file); got != tt.want {
+			t.Errorf("relativePath(%q, %q) = %q, want %q", tt.root, tt.file, got, tt.want)
+		}
+	}
+
+	// 相對路徑以目前目錄解析
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := relativePath(filepath.Dir(cwd), "a.yaml"); got != filepath.Base(cwd)+"/a.yaml" {
+		t.Errorf("relativePath(相對路徑) = %q", got)
+	}
+}
+
+func TestLoadWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	b := FromResults([]*rule.ValidationResult{{File: "a.yaml", RuleID: "r1", Path: "port", Message: "m"}}, "")
+	if err := b.Write(path); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(loaded.Entries) != 1 || *loaded.Entries[0] != *b.Entries[0] {
+		t.Errorf("Load() entries = %+v", loaded.Entries)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"version": 99, "entries": []}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("不支援的版本應返回錯誤")
+	}
+}
@@ -0,0 +1,261 @@
+package expr
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Type 表達式的靜態型別
+// 文件中的路徑在載入時無法得知型別，視為 TypeAny，於求值時才檢查
+type Type int
+
+const (
+	TypeAny Type = iota
+	TypeBool
+	TypeNumber
+	TypeString
+	TypeList
+	TypeNull
+)
+
+func (t Type) String() string {
+	switch t {
+	case TypeBool:
+		return "boolean"
+	case TypeNumber:
+		return "number"
+	case TypeString:
+		return "string"
+	case TypeList:
+		return "array"
+	case TypeNull:
+		return "null"
+	default:
+		return "any"
+	}
+}
+
+// checker 型別檢查
+type checker struct {
+	current bool // 是否可使用 @（位於 all()/any() 或 for_each 中）
+}
+
+// is 檢查型別是否符合（TypeAny 符合任何型別）
+func is(t Type, allowed ...Type) bool {
+	if t == TypeAny {
+		return true
+	}
+	for _, a := range allowed {
+		if t == a {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *checker) check(n node) (Type, error) {
+	switch n := n.(type) {
+	case *literalNode:
+		switch n.value.(type) {
+		case bool:
+			return TypeBool, nil
+		case float64:
+			return TypeNumber, nil
+		case string:
+			return TypeString, nil
+		default:
+			return TypeNull, nil
+		}
+
+	case *listNode:
+		for _, item := range n.items {
+			t, err := c.check(item)
+			if err != nil {
+				return 0, err
+			}
+			if t == TypeList {
+				return 0, fmt.Errorf("陣列常數中不可包含陣列")
+			}
+		}
+		return TypeList, nil
+
+	case *pathNode:
+		if n.relative && !c.current {
+			return 0, fmt.Errorf("%s: @ 只能用於 all()、any() 的條件中，或設定了 for_each 的規則", n.text)
+		}
+		if n.wildcard() {
+			return TypeList, nil
+		}
+		return TypeAny, nil
+
+	case *unaryNode:
+		t, err := c.check(n.x)
+		if err != nil {
+			return 0, err
+		}
+		if n.op == "!" {
+			if !is(t, TypeBool) {
+				return 0, fmt.Errorf("! 的運算元必須是 boolean，但為 %s", t)
+			}
+			return TypeBool, nil
+		}
+		if !is(t, TypeNumber) {
+			return 0, fmt.Errorf("- 的運算元必須是 number，但為 %s", t)
+		}
+		return TypeNumber, nil
+
+	case *binaryNode:
+		return c.checkBinary(n)
+
+	case *callNode:
+		return c.checkCall(n)
+	}
+
+	return 0, fmt.Errorf("未知的語法節點")
+}
+
+func (c *checker) checkBinary(n *binaryNode) (Type, error) {
+	l, err := c.check(n.l)
+	if err != nil {
+		return 0, err
+	}
+	r, err := c.check(n.r)
+	if err != nil {
+		return 0, err
+	}
+
+	listErr := func() error {
+		return fmt.Errorf("%s 的運算元不可為陣列（含 [*] 的路徑請搭配 all()、any()、len() 或 in 使用）", n.op)
+	}
+
+	switch n.op {
+	case "&&", "||":
+		if !is(l, TypeBool) || !is(r, TypeBool) {
+			return 0, fmt.Errorf("%s 的運算元必須是 boolean，但為 %s 與 %s", n.op, l, r)
+		}
+		return TypeBool, nil
+
+	case "==", "!=":
+		if l == TypeList || r == TypeList {
+			return 0, listErr()
+		}
+		return TypeBool, nil
+
+	case "<", "<=", ">", ">=":
+		if l == TypeList || r == TypeList {
+			return 0, listErr()
+		}
+		if !is(l, TypeNumber, TypeString) || !is(r, TypeNumber, TypeString) {
+			return 0, fmt.Errorf("%s 只能比較 number 或 string，但為 %s 與 %s", n.op, l, r)
+		}
+		if l != TypeAny && r != TypeAny && l != r {
+			return 0, fmt.Errorf("%s 不能比較 %s 與 %s", n.op, l, r)
+		}
+		return TypeBool, nil
+
+	case "in":
+		if l == TypeList {
+			return 0, fmt.Errorf("in 的左邊不可為陣列")
+		}
+		if !is(r, TypeList, TypeString) {
+			return 0, fmt.Errorf("in 的右邊必須是陣列或 string，但為 %s", r)
+		}
+		return TypeBool, nil
+
+	case "+":
+		if l == TypeList || r == TypeList {
+			return 0, listErr()
+		}
+		if l == TypeString || r == TypeString {
+			if !is(l, TypeString) || !is(r, TypeString) {
+				return 0, fmt.Errorf("+ 不能連接 %s 與 %s", l, r)
+			}
+			return TypeString, nil
+		}
+		if !is(l, TypeNumber) || !is(r, TypeNumber) {
+			return 0, fmt.Errorf("+ 的運算元必須是 number 或 string，但為 %s 與 %s", l, r)
+		}
+		if l == TypeAny && r == TypeAny {
+			return TypeAny, nil
+		}
+		return TypeNumber, nil
+
+	default: // - * / %
+		if l == TypeList || r == TypeList {
+			return 0, listErr()
+		}
+		if !is(l, TypeNumber) || !is(r, TypeNumber) {
+			return 0, fmt.Errorf("%s 的運算元必須是 number，但為 %s 與 %s", n.op, l, r)
+		}
+		return TypeNumber, nil
+	}
+}
+
+// function 內建函式的簽章
+type function struct {
+	args   []Type // 參數型別，TypeAny 表示任何型別
+	result Type
+}
+
+var functions = map[string]function{
+	"len":        {args: []Type{TypeAny}, result: TypeNumber},
+	"exists":     {args: []Type{TypeAny}, result: TypeBool},
+	"all":        {args: []Type{TypeList, TypeBool}, result: TypeBool},
+	"any":        {args: []Type{TypeList, TypeBool}, result: TypeBool},
+	"lower":      {args: []Type{TypeString}, result: TypeString},
+	"upper":      {args: []Type{TypeString}, result: TypeString},
+	"trim":       {args: []Type{TypeString}, result: TypeString},
+	"contains":   {args: []Type{TypeAny, TypeAny}, result: TypeBool},
+	"startsWith": {args: []Type{TypeString, TypeString}, result: TypeBool},
+	"endsWith":   {args: []Type{TypeString, TypeString}, result: TypeBool},
+	"matches":    {args: []Type{TypeString, TypeString}, result: TypeBool},
+}
+
+func (c *checker) checkCall(n *callNode) (Type, error) {
+	fn, exists := functions[n.name]
+	if !exists {
+		return 0, fmt.Errorf("第 %d 個字元: 未知的函式 %s()", n.pos, n.name)
+	}
+	if len(n.args) != len(fn.args) {
+		return 0, fmt.Errorf("%s() 需要 %d 個參數，但有 %d 個", n.name, len(fn.args), len(n.args))
+	}
+
+	for i, arg := range n.args {
+		// all()/any() 的條件中可以使用 @ 代表目前項目
+		argChecker := c
+		if (n.name == "all" || n.name == "any") && i == 1 {
+			argChecker = &checker{current: true}
+		}
+
+		t, err := argChecker.check(arg)
+		if err != nil {
+			return 0, err
+		}
+		if fn.args[i] != TypeAny && !is(t, fn.args[i]) {
+			return 0, fmt.Errorf("%s() 的第 %d 個參數必須是 %s，但為 %s", n.name, i+1, fn.args[i], t)
+		}
+		if n.name == "len" && !is(t, TypeString, TypeList) {
+			return 0, fmt.Errorf("len() 的參數必須是 string 或陣列，但為 %s", t)
+		}
+	}
+
+	switch n.name {
+	case "exists":
+		if _, ok := n.args[0].(*pathNode); !ok {
+			return 0, fmt.Errorf("exists() 的參數必須是路徑")
+		}
+	case "all", "any":
+		if _, ok := n.args[0].(*literalNode); ok {
+			return 0, fmt.Errorf("%s() 的第 1 個參數必須是含 [*] 的路徑或陣列", n.name)
+		}
+	case "matches":
+		// 常數的正則表達式在載入時就編譯，避免每個檔案才發現錯誤
+		if lit, ok := n.args[1].(*literalNode); ok {
+			if _, err := regexp.Compile(lit.value.(string)); err != nil {
+				return 0, fmt.Errorf("matches() 的正則表達式錯誤: %v", err)
+			}
+		}
+	}
+
+	return fn.result, nil
+}
@@ -0,0 +1,66 @@
+package expr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompileTypeErrors(t *testing.T) {
+	tests := []struct {
+		source  string
+		wantErr string
+	}{
+		{"", "表達式不能為空"},
+		{"port + 1", "結果必須是 boolean，但為 number"},
+		{"'a' + 'b'", "結果必須是 boolean，但為 string"},
+		{"port + \"x\" > 1", "> 不能比較 string 與 number"},
+		{"1 + 'x' == 2", "+ 不能連接 number 與 string"},
+		{"routes[*] == 1", "== 的運算元不可為陣列"},
+		{"[1] == [1]", "== 的運算元不可為陣列"},
+		{"1 && true", "&& 的運算元必須是 boolean，但為 number 與 boolean"},
+		{"!'x'", "! 的運算元必須是 boolean"},
+		{"-true == 1", "- 的運算元必須是 number"},
+		{"true < 1", "< 只能比較 number 或 string"},
+		{"[1] in [1]", "in 的左邊不可為陣列"},
+		{"1 in 2", "in 的右邊必須是陣列或 string"},
+		{"[[1]] == 1", "陣列常數中不可包含陣列"},
+		{"unknown(a)", "第 1 個字元: 未知的函式 unknown()"},
+		{"len(a, b) > 0", "len() 需要 1 個參數，但有 2 個"},
+		{"len(1) > 0", "len() 的參數必須是 string 或陣列"},
+		{"lower(1) == 'a'", "lower() 的第 1 個參數必須是 string，但為 number"},
+		{"exists(1)", "exists() 的參數必須是路徑"},
+		{"all(1, true)", "all() 的第 1 個參數必須是 array，但為 number"},
+		{"any(routes[*], 1)", "any() 的第 2 個參數必須是 boolean"},
+		{"matches(name, '[')", "matches() 的正則表達式錯誤"},
+		{"@.timeout > 0", "@ 只能用於 all()、any() 的條件中"},
+	}
+	for _, tt := range tests {
+		_, err := Compile(tt.source, false)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Compile(%q) = %v, want 包含 %q", tt.source, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCompileValid(t *testing.T) {
+	tests := []struct {
+		source       string
+		allowCurrent bool
+	}{
+		{"port > 0 && port < 65536", false},
+		{"name + '-svc' == 'api-svc'", false},
+		{"env in ['dev', 'prod'] or exists(override)", false},
+		{"all(routes[*], startsWith(@.path, '/'))", false},
+		{"any(routes[*].methods[*], @ == 'GET')", false},
+		{"contains(tags[*], 'public')", false},
+		{"@.timeout <= 30", true},              // for_each 規則可以直接使用 @
+		{"matches(name, pattern)", false},      // 非常數的正則表達式在求值時才編譯
+		{"a.b < c.d && len(name) >= 3", false}, // 路徑的型別未知，求值時才檢查
+		{"not (tls.enabled == false)", false},
+	}
+	for _, tt := range tests {
+		if _, err := Compile(tt.source, tt.allowCurrent); err != nil {
+			t.Errorf("Compile(%q, %v) error: %v", tt.source, tt.allowCurrent, err)
+		}
+	}
+}
@@ -0,0 +1,477 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// evaluator 對單一文件求值
+type evaluator struct {
+	expr    *Expression
+	root    interface{}
+	current interface{}
+}
+
+func (e *evaluator) eval(n node) (interface{}, error) {
+	switch n := n.(type) {
+	case *literalNode:
+		return n.value, nil
+
+	case *listNode:
+		items := make([]interface{}, 0, len(n.items))
+		for _, item := range n.items {
+			v, err := e.eval(item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+		}
+		return items, nil
+
+	case *pathNode:
+		return e.lookup(n)
+
+	case *unaryNode:
+		v, err := e.eval(n.x)
+		if err != nil {
+			return nil, err
+		}
+		if n.op == "!" {
+			b, err := toBool(v, "!")
+			if err != nil {
+				return nil, err
+			}
+			return !b, nil
+		}
+		f, err := toNumber(v, "-")
+		if err != nil {
+			return nil, err
+		}
+		return -f, nil
+
+	case *binaryNode:
+		return e.evalBinary(n)
+
+	case *callNode:
+		return e.evalCall(n)
+	}
+
+	return nil, fmt.Errorf("未知的語法節點")
+}
+
+// lookup 取得路徑的值，含 [*] 的路徑返回所有符合的值
+func (e *evaluator) lookup(p *pathNode) (interface{}, error) {
+	start := e.root
+	if p.relative {
+		start = e.current
+	}
+
+	values := []interface{}{normalize(start)}
+	for _, seg := range p.segments {
+		var next []interface{}
+		for _, v := range values {
+			switch {
+			case seg.wildcard:
+				if arr, ok := v.([]interface{}); ok {
+					for _, item := range arr {
+						next = append(next, normalize(item))
+					}
+				}
+			case seg.field != "":
+				if child, ok := field(v, seg.field); ok {
+					next = append(next, normalize(child))
+				}
+			default:
+				if arr, ok := v.([]interface{}); ok && seg.index >= 0 && seg.index < len(arr) {
+					next = append(next, normalize(arr[seg.index]))
+				}
+			}
+		}
+		values = next
+	}
+
+	if p.wildcard() {
+		if values == nil {
+			values = []interface{}{}
+		}
+		return values, nil
+	}
+	if len(values) == 0 {
+		return nil, &MissingError{Path: p.text}
+	}
+	return values[0], nil
+}
+
+func field(v interface{}, name string) (interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		child, ok := m[name]
+		return child, ok
+	case map[interface{}]interface{}:
+		child, ok := m[name]
+		return child, ok
+	}
+	return nil, false
+}
+
+// normalize 將各種整數型別統一為 float64，方便比較與運算
+func normalize(v interface{}) interface{} {
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case uint64:
+		return float64(n)
+	case float32:
+		return float64(n)
+	}
+	return v
+}
+
+func (e *evaluator) evalBinary(n *binaryNode) (interface{}, error) {
+	l, err := e.eval(n.l)
+	if err != nil {
+		return nil, err
+	}
+
+	// 邏輯運算採短路求值，右邊可以安全地依賴左邊的結果
+	switch n.op {
+	case "&&", "||":
+		lb, err := toBool(l, n.op)
+		if err != nil {
+			return nil, err
+		}
+		if (n.op == "&&" && !lb) || (n.op == "||" && lb) {
+			return lb, nil
+		}
+		r, err := e.eval(n.r)
+		if err != nil {
+			return nil, err
+		}
+		return toBool(r, n.op)
+	}
+
+	r, err := e.eval(n.r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return equal(l, r)
+	case "!=":
+		eq, err := equal(l, r)
+		if err != nil {
+			return nil, err
+		}
+		return !eq, nil
+	case "<", "<=", ">", ">=":
+		return compare(n.op, l, r)
+	case "in":
+		return contains(r, l)
+	case "+":
+		ls, lok := l.(string)
+		rs, rok := r.(string)
+		if lok && rok {
+			return ls + rs, nil
+		}
+		// 只有一邊是字串時，內容是數字的字串（如 INI 的值）與 number 相加
+		_, lnum := numberValue(l)
+		_, rnum := numberValue(r)
+		if (lok || rok) && !(lnum && rnum) {
+			return nil, fmt.Errorf("+ 不能連接 %s 與 %s", describeValue(l), describeValue(r))
+		}
+	}
+
+	lf, err := toNumber(l, n.op)
+	if err != nil {
+		return nil, err
+	}
+	rf, err := toNumber(r, n.op)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "+":
+		return lf + rf, nil
+	case "-":
+		return lf - rf, nil
+	case "*":
+		return lf * rf, nil
+	case "/":
+		if rf == 0 {
+			return nil, fmt.Errorf("除以零")
+		}
+		return lf / rf, nil
+	default:
+		if rf == 0 {
+			return nil, fmt.Errorf("除以零")
+		}
+		return math.Mod(lf, rf), nil
+	}
+}
+
+func (e *evaluator) evalCall(n *callNode) (interface{}, error) {
+	switch n.name {
+	case "exists":
+		_, err := e.eval(n.args[0])
+		if _, missing := err.(*MissingError); missing {
+			return false, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return true, nil
+
+	case "all", "any":
+		return e.evalQuantifier(n)
+	}
+
+	args := make([]interface{}, 0, len(n.args))
+	for _, arg := range n.args {
+		v, err := e.eval(arg)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+	}
+
+	switch n.name {
+	case "len":
+		switch v := args[0].(type) {
+		case string:
+			return float64(len([]rune(v))), nil
+		case []interface{}:
+			return float64(len(v)), nil
+		case map[string]interface{}:
+			return float64(len(v)), nil
+		case map[interface{}]interface{}:
+			return float64(len(v)), nil
+		}
+		return nil, fmt.Errorf("len() 不支援 %s", describeValue(args[0]))
+
+	case "contains":
+		return contains(args[0], args[1])
+	}
+
+	// 其餘函式的參數都是字串
+	strs := make([]string, len(args))
+	for i, arg := range args {
+		s, ok := arg.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s() 的第 %d 個參數必須是 string，但為 %s", n.name, i+1, describeValue(arg))
+		}
+		strs[i] = s
+	}
+
+	switch n.name {
+	case "lower":
+		return strings.ToLower(strs[0]), nil
+	case "upper":
+		return strings.ToUpper(strs[0]), nil
+	case "trim":
+		return strings.TrimSpace(strs[0]), nil
+	case "startsWith":
+		return strings.HasPrefix(strs[0], strs[1]), nil
+	case "endsWith":
+		return strings.HasSuffix(strs[0], strs[1]), nil
+	case "matches":
+		re, err := e.expr.regexp(strs[1])
+		if err != nil {
+			return nil, fmt.Errorf("matches() 的正則表達式錯誤: %v", err)
+		}
+		return re.MatchString(strs[0]), nil
+	}
+
+	return nil, fmt.Errorf("未知的函式 %s()", n.name)
+}
+
+// evalQuantifier 對陣列中的每個項目求值條件
+// 項目缺少條件中的欄位時視為不適用：all() 略過該項目，any() 視為不符合
+func (e *evaluator) evalQuantifier(n *callNode) (interface{}, error) {
+	v, err := e.eval(n.args[0])
+	if err != nil {
+		return nil, err
+	}
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s() 的第 1 個參數必須是陣列，但為 %s", n.name, describeValue(v))
+	}
+
+	for _, item := range items {
+		inner := &evaluator{expr: e.expr, root: e.root, current: normalize(item)}
+		result, err := inner.eval(n.args[1])
+		if _, missing := err.(*MissingError); missing {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		b, err := toBool(result, n.name+"()")
+		if err != nil {
+			return nil, err
+		}
+		if n.name == "all" && !b {
+			return false, nil
+		}
+		if n.name == "any" && b {
+			return true, nil
+		}
+	}
+	return n.name == "all", nil
+}
+
+func toBool(v interface{}, op string) (bool, error) {
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("%s 需要 boolean，但為 %s", op, describeValue(v))
+	}
+	return b, nil
+}
+
+func toNumber(v interface{}, op string) (float64, error) {
+	f, ok := numberValue(v)
+	if !ok {
+		return 0, fmt.Errorf("%s 需要 number，但為 %s", op, describeValue(v))
+	}
+	return f, nil
+}
+
+// numberValue 返回 number 的值；字串的內容是數字時也會轉換（.env、.properties、INI 的值一律為字串），與 value_range 相同
+func numberValue(v interface{}) (float64, bool) {
+	switch val := v.(type) {
+	case float64:
+		return val, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// equal 比較兩個純量值，不同型別的值視為不相等
+func equal(l, r interface{}) (bool, error) {
+	if !isScalar(l) || !isScalar(r) {
+		return false, fmt.Errorf("== 只能比較純量值，但為 %s 與 %s", describeValue(l), describeValue(r))
+	}
+	return l == r, nil
+}
+
+// compare 比較兩個 number 或 string
+// 兩邊都能轉換為 number（含內容是數字的字串）時以數值比較，避免字串形式的數字以文字比較（"10" < "5"）
+func compare(op string, l, r interface{}) (bool, error) {
+	switch l.(type) {
+	case float64, string:
+	default:
+		return false, fmt.Errorf("%s 只能比較 number 或 string，但為 %s", op, describeValue(l))
+	}
+
+	var c int
+	lf, lnum := numberValue(l)
+	rf, rnum := numberValue(r)
+	ls, lstr := l.(string)
+	rs, rstr := r.(string)
+	switch {
+	case lnum && rnum:
+		switch {
+		case lf < rf:
+			c = -1
+		case lf > rf:
+			c = 1
+		}
+	case lstr && rstr:
+		c = strings.Compare(ls, rs)
+	default:
+		return false, fmt.Errorf("%s 不能比較 %s 與 %s", op, describeValue(l), describeValue(r))
+	}
+
+	switch op {
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	default:
+		return c >= 0, nil
+	}
+}
+
+// contains 檢查陣列是否包含某個值，或字串是否包含子字串
+func contains(container, item interface{}) (bool, error) {
+	switch c := container.(type) {
+	case []interface{}:
+		if !isScalar(item) {
+			return false, fmt.Errorf("陣列中只能查找純量值，但為 %s", describeValue(item))
+		}
+		for _, v := range c {
+			if isScalar(v) && normalize(v) == item {
+				return true, nil
+			}
+		}
+		return false, nil
+	case string:
+		s, ok := item.(string)
+		if !ok {
+			return false, fmt.Errorf("字串只能包含 string，但為 %s", describeValue(item))
+		}
+		return strings.Contains(c, s), nil
+	}
+	return false, fmt.Errorf("只能在陣列或 string 中查找，但為 %s", describeValue(container))
+}
+
+func isScalar(v interface{}) bool {
+	switch v.(type) {
+	case nil, bool, float64, string:
+		return true
+	}
+	return false
+}
+
+// describeValue 以表達式的寫法描述值，用於錯誤訊息與實際值
+func describeValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return strconv.Quote(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	case []interface{}:
+		return fmt.Sprintf("array(%d)", len(val))
+	case map[string]interface{}, map[interface{}]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// compileRegexps 預先編譯表達式中的常數正則表達式
+func compileRegexps(n node, out map[string]*regexp.Regexp) {
+	switch n := n.(type) {
+	case *listNode:
+		for _, item := range n.items {
+			compileRegexps(item, out)
+		}
+	case *unaryNode:
+		compileRegexps(n.x, out)
+	case *binaryNode:
+		compileRegexps(n.l, out)
+		compileRegexps(n.r, out)
+	case *callNode:
+		for _, arg := range n.args {
+			compileRegexps(arg, out)
+		}
+		if n.name == "matches" {
+			if lit, ok := n.args[1].(*literalNode); ok {
+				pattern := lit.value.(string)
+				out[pattern] = regexp.MustCompile(pattern)
+			}
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// Package expr 實作 expression 規則使用的表達式語言
+//
+// 表達式在規則載入時解析與型別檢查，錯誤會在載入時回報，而不是驗證每個檔案時才發現。
+// 支援的語法：
+//
+//	database.pool.min <= database.pool.max        比較：== != < <= > >=
+//	server.timeout * 2 < upstream.timeout          算術：+ - * / %（+ 也可連接字串）
+//	tls.enabled && (port == 443 || port == 8443)   邏輯：&& || !（或 and or not）
+//	env in ["dev", "staging", "prod"]              陣列常數與 in
+//	len(routes[*]) > 0                             len() 可用於字串、陣列與物件
+//	all(routes[*], startsWith(@.path, "/"))        all()/any() 以 @ 代表目前的陣列項目
+//	exists(tls.cert) || !tls.enabled               exists() 檢查路徑是否存在
+//	matches(lower(name), '^[a-z-]+$')              lower upper trim contains startsWith endsWith matches
+package expr
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Expression 已編譯的表達式，可以同時用於多個文件
+type Expression struct {
+	source  string
+	root    node
+	paths   []*pathNode // 表達式引用的路徑（不含 all()/any() 條件中的 @）
+	regexps map[string]*regexp.Regexp
+}
+
+// MissingError 表達式引用的路徑不存在
+// 規則通常應將此視為不適用，而不是驗證失敗
+type MissingError struct {
+	Path string
+}
+
+func (e *MissingError) Error() string {
+	return fmt.Sprintf("路徑 %s 不存在", e.Path)
+}
+
+// Compile 解析並檢查表達式
+// allowCurrent 為 true 時表達式最外層也可以使用 @（規則設定了 for_each）
+func Compile(source string, allowCurrent bool) (*Expression, error) {
+	if strings.TrimSpace(source) == "" {
+		return nil, fmt.Errorf("表達式不能為空")
+	}
+
+	root, err := parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &checker{current: allowCurrent}
+	t, err := c.check(root)
+	if err != nil {
+		return nil, err
+	}
+	if !is(t, TypeBool) {
+		return nil, fmt.Errorf("表達式的結果必須是 boolean，但為 %s", t)
+	}
+
+	e := &Expression{source: source, root: root, regexps: make(map[string]*regexp.Regexp)}
+	compileRegexps(root, e.regexps)
+	e.paths = collectPaths(root)
+	return e, nil
+}
+
+// String 返回表達式原文
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Paths 返回表達式引用的文件路徑（不含 @ 開頭的路徑），依出現順序
+// 含 [*] 的路徑返回萬用字元之前的陣列路徑
+func (e *Expression) Paths() []string {
+	var paths []string
+	for _, p := range e.paths {
+		if !p.relative {
+			paths = append(paths, p.text)
+		}
+	}
+	return paths
+}
+
+// Eval 對文件求值
+// root 為文件的根節點，current 為 for_each 目前的陣列項目（沒有時為 nil）
+// 引用的路徑不存在時返回 *MissingError
+func (e *Expression) Eval(root, current interface{}) (bool, error) {
+	ev := &evaluator{expr: e, root: root, current: normalize(current)}
+	v, err := ev.eval(e.root)
+	if err != nil {
+		return false, err
+	}
+	return toBool(v, "表達式")
+}
+
+// Describe 描述表達式引用的路徑在文件中的值，如 "database.pool.min=10, database.pool.max=5"
+func (e *Expression) Describe(root, current interface{}) string {
+	ev := &evaluator{expr: e, root: root, current: normalize(current)}
+	var parts []string
+	for _, p := range e.paths {
+		v, err := ev.lookup(p)
+		if err != nil {
+			parts = append(parts, p.text+" 不存在")
+			continue
+		}
+		parts = append(parts, p.text+"="+describeValue(v))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (e *Expression) regexp(pattern string) (*regexp.Regexp, error) {
+	if re, ok := e.regexps[pattern]; ok {
+		return re, nil
+	}
+	return regexp.Compile(pattern)
+}
+
+// collectPaths 收集表達式中可以描述值的路徑
+// all()/any() 條件中的 @ 指的是各個陣列項目，無法以單一值描述，不列入
+func collectPaths(root node) []*pathNode {
+	seen := make(map[string]bool)
+	var paths []*pathNode
+
+	var walk func(n node, quantified bool)
+	walk = func(n node, quantified bool) {
+		switch n := n.(type) {
+		case *pathNode:
+			if n.relative && quantified {
+				return
+			}
+			// 含 [*] 的路徑以萬用字元之前的陣列描述，如 routes[*].path -> routes
+			if n.wildcard() {
+				n = arrayPrefix(n)
+			}
+			if n != nil && !seen[n.text] {
+				seen[n.text] = true
+				paths = append(paths, n)
+			}
+		case *listNode:
+			for _, item := range n.items {
+				walk(item, quantified)
+			}
+		case *unaryNode:
+			walk(n.x, quantified)
+		case *binaryNode:
+			walk(n.l, quantified)
+			walk(n.r, quantified)
+		case *callNode:
+			for i, arg := range n.args {
+				walk(arg, quantified || ((n.name == "all" || n.name == "any") && i == 1))
+			}
+		}
+	}
+	walk(root, false)
+
+	return paths
+}
+
+// arrayPrefix 返回路徑中第一個 [*] 之前的部分，沒有前綴時返回 nil
+func arrayPrefix(p *pathNode) *pathNode {
+	for i, seg := range p.segments {
+		if seg.wildcard {
+			if i == 0 {
+				return nil
+			}
+			return &pathNode{
+				text:     p.text[:strings.Index(p.text, "[*]")],
+				relative: p.relative,
+				segments: p.segments[:i],
+			}
+		}
+	}
+	return p
+}
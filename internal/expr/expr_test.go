@@ -0,0 +1,251 @@
+package expr
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testDocument 求值測試使用的文件，結構與 YAML 解碼的結果相同
+var testDocument = map[string]interface{}{
+	"name":  "api",
+	"port":  8080,
+	"ratio": 0.5,
+	"empty": nil,
+	"tls":   map[string]interface{}{"enabled": true},
+	"pool":  map[string]interface{}{"min": 10, "max": 5},
+	"tags":  []interface{}{"public", "v2"},
+	"routes": []interface{}{
+		map[string]interface{}{"path": "/api", "timeout": 30},
+		map[string]interface{}{"path": "/health"},
+		map[string]interface{}{"path": "internal", "timeout": 5},
+	},
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		source string
+		want   bool
+	}{
+		{"port == 8080 && name == 'api'", true},
+		{"port + 1 == 8081 && port % 7 == 8080 % 7", true},
+		{"ratio * 2 == 1", true},
+		{"pool.min <= pool.max", false},
+		{"name + '-svc' == 'api-svc'", true},
+		{"name < 'b'", true},
+		{"tls.enabled", true},
+		{"port == '8080'", false}, // 不同型別的值視為不相等
+		{"empty == null", true},   // 值為 null 的欄位存在，可以與 null 比較
+		{"name != null", true},
+		{"'public' in tags[*]", true},
+		{"8080 in [80, 443]", false},
+		{"'pi' in name", true},
+		{"contains(tags[*], 'v2')", true},
+		{"len(routes[*]) == 3 && len(name) == 3", true},
+		{"all(routes[*], startsWith(@.path, '/'))", false},
+		{"any(routes[*], @.path == '/health')", true},
+		{"all(routes[*], @.timeout >= 5)", true},   // 缺少欄位的項目不適用，略過
+		{"any(routes[*], @.timeout > 100)", false}, // 缺少欄位的項目視為不符合
+		{"all(missing[*], false)", true},           // 空陣列
+		{"exists(tls.enabled) && !exists(tls.cert)", true},
+		{"exists(empty)", true},
+		{"exists(tls.cert) || port > 0", true},
+		{"upper(lower(name)) == 'API' && trim(' a ') == 'a'", true},
+		{"endsWith(name, 'pi') && matches(name, '^[a-z]+$')", true},
+		{"port < 0 && missing > 0", false}, // 短路求值，不會讀取右邊的路徑
+	}
+	for _, tt := range tests {
+		e, err := Compile(tt.source, false)
+		if err != nil {
+			t.Errorf("Compile(%q) error: %v", tt.source, err)
+			continue
+		}
+		got, err := e.Eval(testDocument, nil)
+		if err != nil {
+			t.Errorf("Eval(%q) error: %v", tt.source, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Eval(%q) = %v, want %v", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestEvalNumericStrings(t *testing.T) {
+	// .env、.properties、INI 的值一律為字串，內容是數字時以數值比較與運算，結果與 JSON、TOML 相同
+	document := map[string]interface{}{
+		"pool":     map[string]interface{}{"min": "10", "max": "5"},
+		"timeout":  "30",
+		"upstream": map[string]interface{}{"timeout": " 50 "},
+		"version":  "v10",
+		"mode":     "prod",
+	}
+	tests := []struct {
+		source string
+		want   bool
+	}{
+		{"pool.min <= pool.max", false}, // 不是 "10" <= "5" 的文字比較
+		{"pool.min > pool.max", true},
+		{"timeout < upstream.timeout * 0.8", true},
+		{"timeout + 1 > 30 && -timeout == -30", true},
+		{"timeout >= 30 && 9 < pool.min", true},
+		{"version < 'v9'", true}, // 不是數字的字串仍以文字比較
+		{"mode + '-1' == 'prod-1'", true},
+	}
+	for _, tt := range tests {
+		e, err := Compile(tt.source, false)
+		if err != nil {
+			t.Errorf("Compile(%q) error: %v", tt.source, err)
+			continue
+		}
+		got, err := e.Eval(document, nil)
+		if err != nil {
+			t.Errorf("Eval(%q) error: %v", tt.source, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Eval(%q) = %v, want %v", tt.source, got, tt.want)
+		}
+	}
+
+	// 不是數字的字串不會轉換
+	for source, wantErr := range map[string]string{
+		"mode > 1":     `> 不能比較 "prod" 與 1`,
+		"mode * 2 > 1": `* 需要 number，但為 "prod"`,
+		"mode + 1 > 1": `+ 不能連接 "prod" 與 1`,
+	} {
+		e, err := Compile(source, false)
+		if err != nil {
+			t.Errorf("Compile(%q) error: %v", source, err)
+			continue
+		}
+		if _, err := e.Eval(document, nil); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("Eval(%q) = %v, want 包含 %q", source, err, wantErr)
+		}
+	}
+}
+
+func TestEvalMissingPath(t *testing.T) {
+	// 引用的路徑不存在時返回 MissingError，由規則決定視為不適用
+	tests := []struct {
+		source string
+		path   string
+	}{
+		{"missing > 0", "missing"},
+		{"tls.cert == 'x'", "tls.cert"},
+		{"routes[5].path == '/'", "routes[5].path"},
+		{"name.first == 'a'", "name.first"}, // 純量沒有欄位
+		{"port > 0 && missing > 0", "missing"},
+		{"missing == null", "missing"}, // 不存在與 null 不同
+	}
+	for _, tt := range tests {
+		e, err := Compile(tt.source, false)
+		if err != nil {
+			t.Errorf("Compile(%q) error: %v", tt.source, err)
+			continue
+		}
+		_, err = e.Eval(testDocument, nil)
+		missing, ok := err.(*MissingError)
+		if !ok {
+			t.Errorf("Eval(%q) error = %v, want MissingError", tt.source, err)
+			continue
+		}
+		if missing.Path != tt.path {
+			t.Errorf("Eval(%q) MissingError.Path = %q, want %q", tt.source, missing.Path, tt.path)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	// 路徑的型別在求值時才知道，型別不符時返回錯誤
+	tests := []struct {
+		source  string
+		wantErr string
+	}{
+		{"name > 1", `> 不能比較 "api" 與 1`},
+		{"empty < 1", "< 只能比較 number 或 string，但為 null"},
+		{"tls == 1", "== 只能比較純量值，但為 object 與 1"},
+		{"port && true", "&& 需要 boolean，但為 8080"},
+		{"name + 1 == 2", `+ 不能連接 "api" 與 1`},
+		{"port / 0 > 1", "除以零"},
+		{"len(port) > 0", "len() 不支援 8080"},
+		{"lower(port) == 'a'", "lower() 的第 1 個參數必須是 string，但為 8080"},
+		{"all(name, true)", `all() 的第 1 個參數必須是陣列，但為 "api"`},
+		{"matches(name, name + '[')", "matches() 的正則表達式錯誤"},
+		{"port", "表達式 需要 boolean，但為 8080"},
+	}
+	for _, tt := range tests {
+		e, err := Compile(tt.source, false)
+		if err != nil {
+			t.Errorf("Compile(%q) error: %v", tt.source, err)
+			continue
+		}
+		_, err = e.Eval(testDocument, nil)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Eval(%q) = %v, want 包含 %q", tt.source, err, tt.wantErr)
+		}
+	}
+}
+
+func TestEvalCurrent(t *testing.T) {
+	e, err := Compile("@.timeout <= port / 1000 && startsWith(@.path, '/')", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	routes := testDocument["routes"].([]interface{})
+	want := []bool{false, false, false}
+	for i, route := range routes {
+		got, err := e.Eval(testDocument, route)
+		if i == 1 {
+			if _, missing := err.(*MissingError); !missing {
+				t.Errorf("routes[1]: error = %v, want MissingError", err)
+			}
+			continue
+		}
+		if err != nil || got != want[i] {
+			t.Errorf("routes[%d]: Eval() = %v, %v, want %v", i, got, err, want[i])
+		}
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		source  string
+		current interface{}
+		want    string
+	}{
+		{"pool.min <= pool.max", nil, "pool.min=10, pool.max=5"},
+		{"name == 'api' && name != ''", nil, `name="api"`}, // 重複的路徑只描述一次
+		{"exists(tls.cert) || empty == null", nil, "tls.cert 不存在, empty=null"},
+		{"len(routes[*].path) > 0 && tls.enabled", nil, "routes=array(3), tls.enabled=true"},
+		{"all(routes[*], @.timeout > 0)", nil, "routes=array(3)"}, // all() 條件中的 @ 不描述
+		{"tls != null", nil, "tls=object"},
+		{"@.timeout > ratio", map[string]interface{}{"timeout": 30}, "@.timeout=30, ratio=0.5"},
+		{"1 == 1", nil, ""},
+	}
+	for _, tt := range tests {
+		e, err := Compile(tt.source, tt.current != nil)
+		if err != nil {
+			t.Errorf("Compile(%q) error: %v", tt.source, err)
+			continue
+		}
+		if got := e.Describe(testDocument, tt.current); got != tt.want {
+			t.Errorf("Describe(%q) = %q, want %q", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestPaths(t *testing.T) {
+	e, err := Compile("all(routes[*], @.timeout < max) && @.x > 0 && pool.min <= pool.max && exists(pool.min)", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// @ 開頭的路徑不列入，含 [*] 的路徑返回陣列本身
+	want := []string{"routes", "max", "pool.min", "pool.max"}
+	if got := e.Paths(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Paths() = %v, want %v", got, want)
+	}
+	if e.String() != "all(routes[*], @.timeout < max) && @.x > 0 && pool.min <= pool.max && exists(pool.min)" {
+		t.Errorf("String() = %q", e.String())
+	}
+}
@@ -0,0 +1,109 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tokenKind 詞彙類型
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenOperator
+)
+
+// token 詞彙
+type token struct {
+	kind tokenKind
+	text string // 原始文字，字串為去除引號並處理跳脫後的內容
+	pos  int    // 在表達式中的位置（從 1 開始，以字元計算）
+}
+
+// operators 運算子，較長的放前面以優先匹配
+var operators = []string{
+	"==", "!=", "<=", ">=", "&&", "||",
+	"<", ">", "+", "-", "*", "/", "%", "!", "(", ")", "[", "]", ",", ".", "@",
+}
+
+// tokenize 將表達式切分為詞彙
+func tokenize(source string) ([]token, error) {
+	src := []rune(source)
+	var tokens []token
+
+	for i := 0; i < len(src); {
+		r := src[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(src) && (unicode.IsDigit(src[i]) || src[i] == '.' || src[i] == '_') {
+				// 避免把 a[0].b 的 . 當成小數點
+				if src[i] == '.' && (i+1 >= len(src) || !unicode.IsDigit(src[i+1])) {
+					break
+				}
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(src[start:i]), pos: start + 1})
+
+		case r == '"' || r == '\'':
+			start := i
+			var sb strings.Builder
+			i++
+			for {
+				if i >= len(src) {
+					return nil, fmt.Errorf("第 %d 個字元: 字串沒有結尾", start+1)
+				}
+				if src[i] == r {
+					i++
+					break
+				}
+				if src[i] == '\\' && i+1 < len(src) {
+					i++
+					switch src[i] {
+					case 'n':
+						sb.WriteRune('\n')
+					case 't':
+						sb.WriteRune('\t')
+					default:
+						sb.WriteRune(src[i])
+					}
+					i++
+					continue
+				}
+				sb.WriteRune(src[i])
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenString, text: sb.String(), pos: start + 1})
+
+		case unicode.IsLetter(r) || r == '_' || r == '$':
+			start := i
+			for i < len(src) && (unicode.IsLetter(src[i]) || unicode.IsDigit(src[i]) || src[i] == '_' || src[i] == '$') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(src[start:i]), pos: start + 1})
+
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(string(src[i:]), op) {
+					tokens = append(tokens, token{kind: tokenOperator, text: op, pos: i + 1})
+					i += len([]rune(op))
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("第 %d 個字元: 無法識別的字元 %q", i+1, r)
+			}
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(src) + 1}), nil
+}
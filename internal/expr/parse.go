@@ -0,0 +1,377 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// node 語法樹節點
+type node interface{}
+
+// literalNode 常數，值為 float64、string、bool 或 nil
+type literalNode struct {
+	value interface{}
+}
+
+// listNode 陣列常數，如 [1, 2, 3]
+type listNode struct {
+	items []node
+}
+
+// segment 路徑的一段
+type segment struct {
+	field    string // 欄位名稱，索引與萬用字元時為空
+	index    int
+	wildcard bool
+}
+
+// pathNode 文件中的路徑，如 database.pool.max、routes[*].path、@.timeout
+type pathNode struct {
+	text     string
+	relative bool // 以 @ 開頭，相對於目前的陣列項目
+	segments []segment
+}
+
+// wildcard 路徑是否包含 [*]
+func (p *pathNode) wildcard() bool {
+	for _, s := range p.segments {
+		if s.wildcard {
+			return true
+		}
+	}
+	return false
+}
+
+type unaryNode struct {
+	op string
+	x  node
+}
+
+type binaryNode struct {
+	op   string
+	l, r node
+}
+
+type callNode struct {
+	name string
+	args []node
+	pos  int
+}
+
+// parser 遞迴下降解析器
+// 優先順序（低到高）：|| / or、&& / and、比較與 in、+ -、* / %、! not -（一元）、基本項
+type exprParser struct {
+	tokens []token
+	pos    int
+}
+
+func parse(source string) (node, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.errorf(t, "多餘的 %q", t.text)
+	}
+	return n, nil
+}
+
+func (p *exprParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept 下一個詞彙為指定的運算子或關鍵字時讀取並返回 true
+func (p *exprParser) accept(texts ...string) (string, bool) {
+	t := p.peek()
+	if t.kind != tokenOperator && t.kind != tokenIdent {
+		return "", false
+	}
+	for _, text := range texts {
+		if t.text == text {
+			p.next()
+			return text, true
+		}
+	}
+	return "", false
+}
+
+func (p *exprParser) expect(text string) error {
+	if _, ok := p.accept(text); !ok {
+		t := p.peek()
+		if t.kind == tokenEOF {
+			return p.errorf(t, "預期 %q，但表達式已結束", text)
+		}
+		return p.errorf(t, "預期 %q，但遇到 %q", text, t.text)
+	}
+	return nil
+}
+
+func (p *exprParser) errorf(t token, format string, args ...interface{}) error {
+	return fmt.Errorf("第 %d 個字元: %s", t.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *exprParser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("||", "or"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "||", l: left, r: right}
+	}
+}
+
+func (p *exprParser) parseAnd() (node, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("&&", "and"); !ok {
+			return left, nil
+		}
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "&&", l: left, r: right}
+	}
+}
+
+func (p *exprParser) parseComparison() (node, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept("==", "!=", "<=", ">=", "<", ">", "in")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	return &binaryNode{op: op, l: left, r: right}, nil
+}
+
+func (p *exprParser) parseAdditive() (node, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, l: left, r: right}
+	}
+}
+
+func (p *exprParser) parseMultiplicative() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("*", "/", "%")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, l: left, r: right}
+	}
+}
+
+func (p *exprParser) parseUnary() (node, error) {
+	if op, ok := p.accept("!", "not", "-"); ok {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if op == "not" {
+			op = "!"
+		}
+		return &unaryNode{op: op, x: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (node, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokenNumber:
+		p.next()
+		value, err := strconv.ParseFloat(strings.ReplaceAll(t.text, "_", ""), 64)
+		if err != nil {
+			return nil, p.errorf(t, "無效的數字 %q", t.text)
+		}
+		return &literalNode{value: value}, nil
+
+	case tokenString:
+		p.next()
+		return &literalNode{value: t.text}, nil
+
+	case tokenIdent:
+		switch t.text {
+		case "true":
+			p.next()
+			return &literalNode{value: true}, nil
+		case "false":
+			p.next()
+			return &literalNode{value: false}, nil
+		case "null":
+			p.next()
+			return &literalNode{value: nil}, nil
+		}
+		if p.tokens[p.pos+1].text == "(" && p.tokens[p.pos+1].kind == tokenOperator {
+			return p.parseCall()
+		}
+		return p.parsePath()
+
+	case tokenOperator:
+		switch t.text {
+		case "(":
+			p.next()
+			n, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			return n, p.expect(")")
+		case "[":
+			return p.parseList()
+		case "@":
+			return p.parsePath()
+		}
+
+	case tokenEOF:
+		return nil, p.errorf(t, "表達式不完整")
+	}
+
+	return nil, p.errorf(t, "非預期的 %q", t.text)
+}
+
+// parseCall 解析函式呼叫，如 len(routes[*])
+func (p *exprParser) parseCall() (node, error) {
+	name := p.next()
+	p.next() // (
+
+	call := &callNode{name: name.text, pos: name.pos}
+	if _, ok := p.accept(")"); ok {
+		return call, nil
+	}
+	for {
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+		if _, ok := p.accept(","); !ok {
+			return call, p.expect(")")
+		}
+	}
+}
+
+// parseList 解析陣列常數
+func (p *exprParser) parseList() (node, error) {
+	p.next() // [
+	list := &listNode{}
+	if _, ok := p.accept("]"); ok {
+		return list, nil
+	}
+	for {
+		item, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		list.items = append(list.items, item)
+		if _, ok := p.accept(","); !ok {
+			return list, p.expect("]")
+		}
+	}
+}
+
+// parsePath 解析路徑，如 a.b[0].c、routes[*].path、@、@.timeout
+func (p *exprParser) parsePath() (node, error) {
+	path := &pathNode{}
+	var text strings.Builder
+
+	first := p.next()
+	if first.text == "@" {
+		path.relative = true
+		text.WriteString("@")
+	} else {
+		path.segments = append(path.segments, segment{field: first.text})
+		text.WriteString(first.text)
+	}
+
+	for {
+		t := p.peek()
+		if t.kind != tokenOperator {
+			break
+		}
+		switch t.text {
+		case ".":
+			p.next()
+			field := p.next()
+			if field.kind != tokenIdent {
+				return nil, p.errorf(field, "路徑 %s 的 . 之後缺少欄位名稱", text.String())
+			}
+			path.segments = append(path.segments, segment{field: field.text})
+			text.WriteString("." + field.text)
+			continue
+		case "[":
+			p.next()
+			inner := p.next()
+			switch {
+			case inner.kind == tokenOperator && inner.text == "*":
+				path.segments = append(path.segments, segment{wildcard: true})
+				text.WriteString("[*]")
+			case inner.kind == tokenNumber:
+				index, err := strconv.Atoi(inner.text)
+				if err != nil {
+					return nil, p.errorf(inner, "無效的陣列索引 %q", inner.text)
+				}
+				path.segments = append(path.segments, segment{index: index})
+				text.WriteString(fmt.Sprintf("[%d]", index))
+			default:
+				return nil, p.errorf(inner, "陣列索引必須是數字或 *")
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		break
+	}
+
+	path.text = text.String()
+	return path, nil
+}
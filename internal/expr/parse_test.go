@@ -0,0 +1,91 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// sexpr 以加上括號的形式輸出語法樹，用來檢查優先順序與結合方向
+func sexpr(n node) string {
+	switch n := n.(type) {
+	case *literalNode:
+		return describeValue(n.value)
+	case *listNode:
+		items := make([]string, len(n.items))
+		for i, item := range n.items {
+			items[i] = sexpr(item)
+		}
+		return "[" + strings.Join(items, " ") + "]"
+	case *pathNode:
+		return n.text
+	case *unaryNode:
+		return fmt.Sprintf("(%s %s)", n.op, sexpr(n.x))
+	case *binaryNode:
+		return fmt.Sprintf("(%s %s %s)", sexpr(n.l), n.op, sexpr(n.r))
+	case *callNode:
+		args := make([]string, len(n.args))
+		for i, arg := range n.args {
+			args[i] = sexpr(arg)
+		}
+		return fmt.Sprintf("%s(%s)", n.name, strings.Join(args, " "))
+	}
+	return fmt.Sprintf("%T", n)
+}
+
+func TestParsePrecedence(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{"a || b && c", "(a || (b && c))"},
+		{"a && b || c", "((a && b) || c)"},
+		{"a or b and not c", "(a || (b && (! c)))"},
+		{"a == 1 && b != 2", "((a == 1) && (b != 2))"},
+		{"a + b * c - d", "((a + (b * c)) - d)"},
+		{"a - b - c", "((a - b) - c)"},
+		{"a / b % c", "((a / b) % c)"},
+		{"(a + b) * c", "((a + b) * c)"},
+		{"-a * b", "((- a) * b)"},
+		{"!!a", "(! (! a))"},
+		{"a + 1 < b * 2", "((a + 1) < (b * 2))"},
+		{"env in ['dev', \"prod\"]", `(env in ["dev" "prod"])`},
+		{"len(routes[*]) >= 1_000", "(len(routes[*]) >= 1000)"},
+		{"all(routes[*], @.timeout > 0)", "all(routes[*] (@.timeout > 0))"},
+		{"a.b[0].c == null", "(a.b[0].c == null)"},
+		{"matches(name, 'a\\'b')", `matches(name "a'b")`},
+	}
+	for _, tt := range tests {
+		n, err := parse(tt.source)
+		if err != nil {
+			t.Errorf("parse(%q) error: %v", tt.source, err)
+			continue
+		}
+		if got := sexpr(n); got != tt.want {
+			t.Errorf("parse(%q) = %s, want %s", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		source  string
+		wantErr string
+	}{
+		{"a < b < c", `第 7 個字元: 多餘的 "<"`}, // 比較運算不能連續使用
+		{"(a", "預期 \")\"，但表達式已結束"},
+		{"a &&", "表達式不完整"},
+		{"a.", "之後缺少欄位名稱"},
+		{"a[x]", "陣列索引必須是數字或 *"},
+		{"a b", `多餘的 "b"`},
+		{"f(a,", "表達式不完整"},
+		{"[1, 2", "預期 \"]\""},
+		{"'abc", "字串"},
+	}
+	for _, tt := range tests {
+		_, err := parse(tt.source)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("parse(%q) = %v, want 包含 %q", tt.source, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package fix
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// numbered 產生 n 行 "line 1" ... "line n"
+func numbered(n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+	return lines
+}
+
+func join(lines []string) string {
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	long := numbered(20)
+	farApart := numbered(20)
+	farApart[1] = "changed 2"
+	farApart[18] = "changed 19"
+	nearby := numbered(20)
+	nearby[4] = "changed 5"
+	nearby[10] = "changed 11"
+	inserted := append(append(numbered(10), "new"), long[10:]...)
+
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   string
+	}{
+		{name: "內容相同", before: "a\n", after: "a\n", want: ""},
+		{
+			name:   "替換一行",
+			before: "a\nb\nc\n",
+			after:  "a\nB\nc\n",
+			want:   "--- a/f.yaml\n+++ b/f.yaml\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			// 純插入時舊的範圍為空，起始行為插入位置的前一行
+			name:   "純插入",
+			before: join(long),
+			after:  join(inserted),
+			want:   "--- a/f.yaml\n+++ b/f.yaml\n@@ -8,6 +8,7 @@\n line 8\n line 9\n line 10\n+new\n line 11\n line 12\n line 13\n",
+		},
+		{
+			name:   "從空檔案新增",
+			before: "",
+			after:  "a\n",
+			want:   "--- a/f.yaml\n+++ b/f.yaml\n@@ -0,0 +1 @@\n+a\n",
+		},
+		{
+			name:   "結尾沒有換行",
+			before: "a\nb",
+			after:  "a\nc",
+			want:   "--- a/f.yaml\n+++ b/f.yaml\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n",
+		},
+		{
+			// 間隔不超過 2*diffContext 行的變更合併為同一個 hunk
+			name:   "相近的變更合併",
+			before: join(long),
+			after:  join(nearby),
+			want: "--- a/f.yaml\n+++ b/f.yaml\n@@ -2,13 +2,13 @@\n line 2\n line 3\n line 4\n-line 5\n+changed 5\n" +
+				" line 6\n line 7\n line 8\n line 9\n line 10\n-line 11\n+changed 11\n line 12\n line 13\n line 14\n",
+		},
+		{
+			name:   "相距較遠的變更分開",
+			before: join(long),
+			after:  join(farApart),
+			want: "--- a/f.yaml\n+++ b/f.yaml\n@@ -1,5 +1,5 @@\n line 1\n-line 2\n+changed 2\n line 3\n line 4\n line 5\n" +
+				"@@ -16,5 +16,5 @@\n line 16\n line 17\n line 18\n-line 19\n+changed 19\n line 20\n",
+		},
+	}
+	for _, tt := range tests {
+		if got := UnifiedDiff("f.yaml", []byte(tt.before), []byte(tt.after)); got != tt.want {
+			t.Errorf("%s: UnifiedDiff() =\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+	}
+}
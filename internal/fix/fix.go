@@ -0,0 +1,476 @@
+// Package fix 將規則描述的自動修正套用到 YAML 配置檔
+//
+// 修正透過 yaml.Node 樹定位要修改的節點，只替換該節點在原始內容中的文字，
+// 節點以外的內容（註解、key 順序、縮排與引號風格）都保持不變。
+package fix
+
+import (
+	"config-validator/internal/parser"
+	"config-validator/internal/rule"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Skipped 無法套用的修正
+type Skipped struct {
+	Result *rule.ValidationResult
+	Reason string
+}
+
+// Outcome 套用修正的結果
+type Outcome struct {
+	Content  []byte                   // 修正後的內容
+	Applied  []*rule.ValidationResult // 已修正的問題
+	Deferred []*rule.ValidationResult // 與其他修正重疊，需在套用後重新驗證才能修正
+	Skipped  []*Skipped               // 無法修正的問題
+}
+
+// edit 對原始內容 [start, end) 的文字替換
+type edit struct {
+	start, end int
+	text       string
+	results    []*rule.ValidationResult
+}
+
+// sequenceEdit 同一個陣列上的所有修正，合併成一次替換
+type sequenceEdit struct {
+	node    *yaml.Node
+	order   []int
+	removed map[int]bool
+	results []*rule.ValidationResult
+}
+
+// Apply 將結果中描述的修正套用到 YAML 內容
+// 被抑制的結果不會修正；互相重疊的修正只套用第一個，其餘列入 Deferred
+func Apply(content []byte, results []*rule.ValidationResult) (*Outcome, error) {
+	roots, err := parser.DecodeYAMLDocuments(content)
+	if err != nil {
+		return nil, err
+	}
+
+	src := newSource(content)
+	outcome := &Outcome{}
+	skip := func(result *rule.ValidationResult, format string, args ...interface{}) {
+		outcome.Skipped = append(outcome.Skipped, &Skipped{Result: result, Reason: fmt.Sprintf(format, args...)})
+	}
+
+	var edits []*edit
+	sequences := make(map[*yaml.Node]*sequenceEdit)
+	var sequenceOrder []*sequenceEdit
+
+	for _, result := range results {
+		if result.Fix == nil || result.Suppressed {
+			continue
+		}
+
+		root, ok := documentRoot(roots, result.Document)
+		if !ok {
+			skip(result, "找不到第 %d 個文件", result.Document)
+			continue
+		}
+		node, ok := parser.LookupNode(root, result.Fix.Path)
+		if !ok {
+			skip(result, "找不到路徑 %s", result.Fix.Path)
+			continue
+		}
+
+		switch result.Fix.Kind {
+		case rule.FixReplaceValue:
+			e, err := src.replaceScalar(node, result.Fix.Value)
+			if err != nil {
+				skip(result, "%v", err)
+				continue
+			}
+			e.results = []*rule.ValidationResult{result}
+			edits = append(edits, e)
+
+		case rule.FixRemoveItem, rule.FixReorderItems:
+			if node.Kind != yaml.SequenceNode {
+				skip(result, "%s 不是陣列", result.Fix.Path)
+				continue
+			}
+			seq, exists := sequences[node]
+			if !exists {
+				seq = &sequenceEdit{node: node, order: identity(len(node.Content)), removed: make(map[int]bool)}
+				sequences[node] = seq
+				sequenceOrder = append(sequenceOrder, seq)
+			}
+			if result.Fix.Kind == rule.FixRemoveItem {
+				if result.Fix.Index < 0 || result.Fix.Index >= len(node.Content) {
+					skip(result, "陣列索引 %d 超出範圍", result.Fix.Index)
+					continue
+				}
+				seq.removed[result.Fix.Index] = true
+			} else {
+				if !isPermutation(result.Fix.Order, len(node.Content)) {
+					skip(result, "排序結果與陣列長度不符")
+					continue
+				}
+				seq.order = result.Fix.Order
+			}
+			seq.results = append(seq.results, result)
+
+		default:
+			skip(result, "不支援的修正類型: %s", result.Fix.Kind)
+		}
+	}
+
+	for _, seq := range sequenceOrder {
+		var order []int
+		for _, idx := range seq.order {
+			if !seq.removed[idx] {
+				order = append(order, idx)
+			}
+		}
+		e, err := src.rewriteSequence(seq.node, order)
+		if err != nil {
+			for _, result := range seq.results {
+				skip(result, "%v", err)
+			}
+			continue
+		}
+		e.results = seq.results
+		edits = append(edits, e)
+	}
+
+	// 依位置排序，重疊的修正只保留第一個（完全相同的修正視為同一個）
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+	var accepted []*edit
+	for _, e := range edits {
+		if n := len(accepted); n > 0 && e.start < accepted[n-1].end {
+			last := accepted[n-1]
+			if e.start == last.start && e.end == last.end && e.text == last.text {
+				last.results = append(last.results, e.results...)
+				continue
+			}
+			outcome.Deferred = append(outcome.Deferred, e.results...)
+			continue
+		}
+		accepted = append(accepted, e)
+	}
+
+	// 從後往前替換，前面的位置不受影響
+	fixed := string(content)
+	for i := len(accepted) - 1; i >= 0; i-- {
+		e := accepted[i]
+		fixed = fixed[:e.start] + e.text + fixed[e.end:]
+	}
+	for _, e := range accepted {
+		outcome.Applied = append(outcome.Applied, e.results...)
+	}
+
+	// 修正後的內容必須仍是合法的 YAML
+	if _, err := parser.DecodeYAMLDocuments([]byte(fixed)); err != nil {
+		return nil, fmt.Errorf("修正後的內容無法解析: %w", err)
+	}
+	outcome.Content = []byte(fixed)
+	return outcome, nil
+}
+
+// documentRoot 依結果的文件序號取得根節點
+// 序號為 0 表示檔案只有一個非空文件，取第一個非空文件
+func documentRoot(roots []*yaml.Node, doc int) (*yaml.Node, bool) {
+	if doc == 0 {
+		for _, root := range roots {
+			if root != nil {
+				return root, true
+			}
+		}
+		return nil, false
+	}
+	if doc > len(roots) || roots[doc-1] == nil {
+		return nil, false
+	}
+	return roots[doc-1], true
+}
+
+// source 原始內容與每行的起始位置
+type source struct {
+	content string
+	lines   []int // 每行第一個位元組的位置
+}
+
+func newSource(content []byte) *source {
+	s := &source{content: string(content), lines: []int{0}}
+	for i, c := range s.content {
+		if c == '\n' {
+			s.lines = append(s.lines, i+1)
+		}
+	}
+	return s
+}
+
+// offset 將行與欄（皆從 1 開始，欄以字元計算）轉換為位元組位置
+func (s *source) offset(line, column int) (int, error) {
+	if line < 1 || line > len(s.lines) {
+		return 0, fmt.Errorf("第 %d 行超出範圍", line)
+	}
+	pos := s.lines[line-1]
+	for col := 1; col < column; col++ {
+		if pos >= len(s.content) || s.content[pos] == '\n' {
+			return 0, fmt.Errorf("第 %d 行第 %d 欄超出範圍", line, column)
+		}
+		_, size := utf8.DecodeRuneInString(s.content[pos:])
+		pos += size
+	}
+	return pos, nil
+}
+
+// lineStart 返回某行的起始位置，超過最後一行時返回內容結尾
+func (s *source) lineStart(line int) int {
+	if line > len(s.lines) {
+		return len(s.content)
+	}
+	return s.lines[line-1]
+}
+
+// lineText 返回某行的內容（不含換行）
+func (s *source) lineText(line int) string {
+	text := s.content[s.lineStart(line):s.lineStart(line+1)]
+	return strings.TrimSuffix(text, "\n")
+}
+
+// replaceScalar 建立替換純量值的修正，保留原本的引號風格
+func (s *source) replaceScalar(node *yaml.Node, value string) (*edit, error) {
+	if node.Kind != yaml.ScalarNode {
+		return nil, fmt.Errorf("不是純量值")
+	}
+	if node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		return nil, fmt.Errorf("不支援修正區塊字串（| 或 >）")
+	}
+
+	start, err := s.offset(node.Line, node.Column)
+	if err != nil {
+		return nil, err
+	}
+	end, err := s.scalarEnd(node, start)
+	if err != nil {
+		return nil, err
+	}
+
+	style := node.Style &^ yaml.TaggedStyle
+	// flow 集合中的字串含有 , [ ] { } 時必須加上引號
+	if style == 0 && strings.ContainsAny(value, ",[]{}") {
+		style = yaml.DoubleQuotedStyle
+	}
+	// yaml.v3 會把 BMP 以外的字元（如 emoji）跳脫為 \U，雙引號字串改用 Go 的跳脫規則（與 YAML 相容）
+	// plain 字串無法表示新的值時 yaml.v3 會改用雙引號，同樣以 Go 的規則輸出
+	var text string
+	if style == yaml.DoubleQuotedStyle {
+		text = strconv.Quote(value)
+	} else {
+		text, err = renderNode(&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value, Style: style})
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(text, `"`) {
+			text = strconv.Quote(value)
+		}
+	}
+	if strings.Contains(text, "\n") {
+		return nil, fmt.Errorf("新的值無法以單行表示")
+	}
+	return &edit{start: start, end: end, text: text}, nil
+}
+
+// scalarEnd 找出純量值在原始內容中的結束位置
+func (s *source) scalarEnd(node *yaml.Node, start int) (int, error) {
+	text := s.content[start:]
+	switch {
+	case node.Style&yaml.DoubleQuotedStyle != 0:
+		if !strings.HasPrefix(text, `"`) {
+			return 0, fmt.Errorf("無法定位字串")
+		}
+		for i := 1; i < len(text); i++ {
+			switch text[i] {
+			case '\\':
+				i++
+			case '"':
+				return start + i + 1, nil
+			}
+		}
+	case node.Style&yaml.SingleQuotedStyle != 0:
+		if !strings.HasPrefix(text, `'`) {
+			return 0, fmt.Errorf("無法定位字串")
+		}
+		for i := 1; i < len(text); i++ {
+			if text[i] == '\'' {
+				if i+1 < len(text) && text[i+1] == '\'' {
+					i++
+					continue
+				}
+				return start + i + 1, nil
+			}
+		}
+	default:
+		// 多行的 plain 字串在原始內容中與值不同，無法直接定位
+		if strings.HasPrefix(text, node.Value) && !strings.Contains(node.Value, "\n") {
+			return start + len(node.Value), nil
+		}
+	}
+	return 0, fmt.Errorf("無法定位字串")
+}
+
+// rewriteSequence 建立依 order（原索引）重新排列陣列項目的修正，不在 order 中的項目會被移除
+func (s *source) rewriteSequence(seq *yaml.Node, order []int) (*edit, error) {
+	if seq.Style&yaml.FlowStyle != 0 {
+		return s.rewriteFlowSequence(seq, order)
+	}
+	return s.rewriteBlockSequence(seq, order)
+}
+
+// rewriteFlowSequence 重新輸出 flow 風格的陣列，如 [a, b, c]
+func (s *source) rewriteFlowSequence(seq *yaml.Node, order []int) (*edit, error) {
+	start, err := s.offset(seq.Line, seq.Column)
+	if err != nil {
+		return nil, err
+	}
+	end, err := s.flowEnd(start)
+	if err != nil {
+		return nil, err
+	}
+
+	rewritten := *seq
+	rewritten.Content = make([]*yaml.Node, len(order))
+	for i, idx := range order {
+		rewritten.Content[i] = seq.Content[idx]
+	}
+	text, err := renderNode(&rewritten)
+	if err != nil {
+		return nil, err
+	}
+	return &edit{start: start, end: end, text: text}, nil
+}
+
+// flowEnd 找出從 start 開始的 flow 集合的結束位置（含結尾括號）
+func (s *source) flowEnd(start int) (int, error) {
+	depth := 0
+	var quote byte
+	for i := start; i < len(s.content); i++ {
+		c := s.content[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+			if depth == 0 {
+				return i + 1, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("找不到陣列的結尾")
+}
+
+// rewriteBlockSequence 以行為單位重新排列 block 風格的陣列項目
+// 每個項目包含從 "- " 所在行（以及緊接在上方的註解行）到下一個項目之前的所有行
+func (s *source) rewriteBlockSequence(seq *yaml.Node, order []int) (*edit, error) {
+	items := seq.Content
+	if len(items) == 0 {
+		return nil, fmt.Errorf("陣列是空的")
+	}
+
+	starts := make([]int, len(items))
+	for i, item := range items {
+		prefix := []rune(s.lineText(item.Line))
+		if item.Column-1 > len(prefix) || !isItemPrefix(string(prefix[:item.Column-1])) {
+			return nil, fmt.Errorf("不支援的陣列格式（項目必須以 - 開頭並與內容同一行）")
+		}
+		if i > 0 && item.Line <= items[i-1].Line {
+			return nil, fmt.Errorf("不支援的陣列格式（項目必須各自一行）")
+		}
+
+		// 項目上方緊接的註解行屬於該項目
+		start := item.Line
+		floor := 1
+		if i > 0 {
+			floor = items[i-1].Line + 1
+		}
+		for start-1 >= floor && strings.HasPrefix(strings.TrimSpace(s.lineText(start-1)), "#") {
+			start--
+		}
+		starts[i] = start
+	}
+
+	last := parser.NodePosition(items[len(items)-1])
+	regionStart := s.lineStart(starts[0])
+	regionEnd := s.lineStart(last.EndLine + 1)
+	region := s.content[regionStart:regionEnd]
+
+	chunks := make([]string, len(items))
+	for i := range items {
+		end := regionEnd
+		if i+1 < len(items) {
+			end = s.lineStart(starts[i+1])
+		}
+		chunk := s.content[s.lineStart(starts[i]):end]
+		if !strings.HasSuffix(chunk, "\n") {
+			chunk += "\n"
+		}
+		chunks[i] = chunk
+	}
+
+	var sb strings.Builder
+	for _, idx := range order {
+		sb.WriteString(chunks[idx])
+	}
+	text := sb.String()
+	if !strings.HasSuffix(region, "\n") {
+		text = strings.TrimSuffix(text, "\n")
+	}
+	return &edit{start: regionStart, end: regionEnd, text: text}, nil
+}
+
+// isItemPrefix 檢查項目之前的文字是否只有縮排與 "- "
+func isItemPrefix(prefix string) bool {
+	trimmed := strings.TrimLeft(prefix, " \t")
+	if !strings.HasPrefix(trimmed, "-") {
+		return false
+	}
+	rest := trimmed[1:]
+	return rest != "" && strings.TrimLeft(rest, " \t") == ""
+}
+
+// renderNode 將單一節點輸出為 YAML 文字（不含結尾換行）
+func renderNode(node *yaml.Node) (string, error) {
+	out, err := yaml.Marshal(node)
+	if err != nil {
+		return "", fmt.Errorf("輸出 YAML 失敗: %w", err)
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+func identity(n int) []int {
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	return order
+}
+
+// isPermutation 檢查 order 是否為 0 ~ n-1 的排列
+func isPermutation(order []int, n int) bool {
+	if len(order) != n {
+		return false
+	}
+	seen := make([]bool, n)
+	for _, idx := range order {
+		if idx < 0 || idx >= n || seen[idx] {
+			return false
+		}
+		seen[idx] = true
+	}
+	return true
+}
@@ -0,0 +1,212 @@
+package fix
+
+import (
+	"config-validator/internal/rule"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// update 為 true 時以目前的輸出覆寫 testdata 中的預期結果：go test ./internal/fix -update
+var update = flag.Bool("update", false, "覆寫 testdata 中的預期結果")
+
+// fixResult 建立帶有修正的驗證結果
+func fixResult(id string, doc int, fix rule.Fix) *rule.ValidationResult {
+	return &rule.ValidationResult{RuleID: id, Document: doc, Fix: &fix}
+}
+
+func replaceValue(path, value string) rule.Fix {
+	return rule.Fix{Kind: rule.FixReplaceValue, Path: path, Value: value}
+}
+
+func removeItem(path string, index int) rule.Fix {
+	return rule.Fix{Kind: rule.FixRemoveItem, Path: path, Index: index}
+}
+
+func reorderItems(path string, order ...int) rule.Fix {
+	return rule.Fix{Kind: rule.FixReorderItems, Path: path, Order: order}
+}
+
+// ids 返回結果的規則 ID，以逗號分隔
+func ids(results []*rule.ValidationResult) string {
+	var list []string
+	for _, r := range results {
+		list = append(list, r.RuleID)
+	}
+	return strings.Join(list, ",")
+}
+
+// TestApplyGolden 將修正套用到 testdata/<name>.yaml，與 <name>.golden（修正後的內容）及 <name>.diff 比對
+func TestApplyGolden(t *testing.T) {
+	tests := []struct {
+		name     string
+		results  []*rule.ValidationResult
+		applied  string
+		deferred string
+		skipped  string
+	}{
+		{
+			// 只替換值本身，註解、空行與 key 順序都保留
+			name: "comments",
+			results: []*rule.ValidationResult{
+				fixResult("host", 0, replaceValue("server.host", "127.0.0.1")),
+				fixResult("mode", 0, replaceValue("server.mode", "release")),
+				fixResult("method", 0, replaceValue("routes[0].method", "GET")),
+				fixResult("missing", 0, replaceValue("server.tls", "on")),
+			},
+			applied: "host,mode,method",
+			skipped: "missing",
+		},
+		{
+			// 保留原本的引號風格；plain 字串含有 flow 的特殊字元或會被解讀為其他型別時改用雙引號
+			// 有 tag 的值與區塊字串無法只替換值本身，不修正
+			name: "scalars",
+			results: []*rule.ValidationResult{
+				fixResult("plain", 0, replaceValue("plain", "new value")),
+				fixResult("double", 0, replaceValue("double", `new "value"`)),
+				fixResult("single", 0, replaceValue("single", "it's new")),
+				fixResult("flow", 0, replaceValue("flow[1]", "x, y")),
+				fixResult("unicode", 0, replaceValue("unicode", "新的值 😀")),
+				fixResult("number-like", 0, replaceValue("version", "2.0")),
+				fixResult("tagged", 0, replaceValue("tagged", "456")),
+				fixResult("block", 0, replaceValue("block", "single")),
+				fixResult("not-scalar", 0, replaceValue("flow", "x")),
+			},
+			applied: "plain,double,single,flow,unicode,number-like",
+			skipped: "tagged,block,not-scalar",
+		},
+		{
+			// flow 陣列重新輸出，block 陣列以行為單位搬移（項目上方的註解跟著項目）
+			name: "sequences",
+			results: []*rule.ValidationResult{
+				fixResult("flow-sort", 0, reorderItems("flow", 1, 2, 0, 3)),
+				fixResult("block-sort", 0, reorderItems("block", 1, 2, 0)),
+				fixResult("nested-sort", 0, reorderItems("nested", 1, 0)),
+				fixResult("dup-1", 0, removeItem("removals", 1)),
+				fixResult("dup-2", 0, removeItem("removals", 3)),
+				fixResult("out-of-range", 0, removeItem("removals", 9)),
+				fixResult("bad-order", 0, reorderItems("flow", 0, 0, 1, 2)),
+				fixResult("not-array", 0, removeItem("nested[0].name", 0)),
+			},
+			applied: "flow-sort,block-sort,nested-sort,dup-1,dup-2",
+			skipped: "out-of-range,bad-order,not-array",
+		},
+		{
+			// 文件序號為原始的序號（含空文件），只修改對應的文件
+			name: "multidoc",
+			results: []*rule.ValidationResult{
+				fixResult("mode-1", 1, replaceValue("mode", "release")),
+				fixResult("mode-3", 3, replaceValue("mode", "release")),
+				fixResult("tags-3", 3, reorderItems("tags", 1, 0)),
+				fixResult("empty-doc", 2, replaceValue("mode", "release")),
+				fixResult("no-doc", 4, replaceValue("mode", "release")),
+			},
+			applied: "mode-1,mode-3,tags-3",
+			skipped: "empty-doc,no-doc",
+		},
+		{
+			// 重疊的修正只套用第一個，其餘延後；完全相同的修正合併
+			name: "overlap",
+			results: []*rule.ValidationResult{
+				fixResult("sort-routes", 0, reorderItems("routes", 1, 0)),
+				fixResult("method", 0, replaceValue("routes[0].method", "GET")),
+				fixResult("names-a", 0, replaceValue("names[0]", "c")),
+				fixResult("names-b", 0, replaceValue("names[0]", "c")),
+				fixResult("suppressed", 0, replaceValue("names[1]", "z")),
+				{RuleID: "no-fix"},
+			},
+			applied:  "sort-routes,names-a,names-b",
+			deferred: "method",
+		},
+	}
+	tests[4].results[4].Suppressed = true
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := os.ReadFile(filepath.Join("testdata", tt.name+".yaml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			outcome, err := Apply(input, tt.results)
+			if err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+
+			if got := ids(outcome.Applied); got != tt.applied {
+				t.Errorf("Applied = %s, want %s", got, tt.applied)
+			}
+			if got := ids(outcome.Deferred); got != tt.deferred {
+				t.Errorf("Deferred = %s, want %s", got, tt.deferred)
+			}
+			var skipped []*rule.ValidationResult
+			for _, s := range outcome.Skipped {
+				if s.Reason == "" {
+					t.Errorf("%s: Skipped 缺少原因", s.Result.RuleID)
+				}
+				skipped = append(skipped, s.Result)
+			}
+			if got := ids(skipped); got != tt.skipped {
+				t.Errorf("Skipped = %s, want %s", got, tt.skipped)
+			}
+
+			checkGolden(t, tt.name+".golden", outcome.Content)
+			checkGolden(t, tt.name+".diff", []byte(UnifiedDiff(tt.name+".yaml", input, outcome.Content)))
+		})
+	}
+}
+
+// checkGolden 比對 testdata 中的預期結果，-update 時覆寫
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("讀取預期結果失敗（使用 -update 產生）: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("%s 不符:\n--- got ---\n%s\n--- want ---\n%s", name, got, want)
+	}
+}
+
+func TestApplyPlainNeedsQuotes(t *testing.T) {
+	// plain 字串無法原樣表示新的值時加上引號，修正後的值仍為相同的字串
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"a: b", "key: 'a: b'\n"},
+		{"- x", "key: '- x'\n"},
+		{"x #y", "key: 'x #y'\n"},
+		{"null", "key: \"null\"\n"},
+		{"", "key: \"\"\n"},
+	}
+	for _, tt := range tests {
+		results := []*rule.ValidationResult{fixResult("quote", 0, replaceValue("key", tt.value))}
+		outcome, err := Apply([]byte("key: value\n"), results)
+		if err != nil {
+			t.Errorf("Apply(%q) error: %v", tt.value, err)
+			continue
+		}
+		if string(outcome.Content) != tt.want {
+			t.Errorf("Apply(%q) = %q, want %q", tt.value, outcome.Content, tt.want)
+		}
+	}
+
+	// 多行的值無法放在 plain 字串的位置，不修正
+	results := []*rule.ValidationResult{fixResult("multiline", 0, replaceValue("key", "a\nb"))}
+	outcome, err := Apply([]byte("key: value\n"), results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outcome.Applied) != 0 || len(outcome.Skipped) != 1 || string(outcome.Content) != "key: value\n" {
+		t.Errorf("Apply(多行) = %q, applied %d, skipped %d", outcome.Content, len(outcome.Applied), len(outcome.Skipped))
+	}
+}
@@ -0,0 +1,226 @@
+// Package glob 提供產品檢測與規則目標共用的路徑匹配
+//
+// 支援的語法：
+//
+//	a/*.yaml      * 匹配單一路徑層級中的任意字元（不含 /）
+//	a/?.yaml      ? 匹配單一字元（不含 /）
+//	**/api/**     ** 匹配零或多個路徑層級，可出現在任何位置
+//	[a-c]*.yaml   字元類別，支援範圍與排除 [!a-c]、[^a-c]
+//	*.{yaml,yml}  多選一，可巢狀
+//	!**/test/**   排除（僅在 MatchAny 的 pattern 列表中有效）
+//
+// 不含 / 的 pattern 只比對檔名，例如 "*.yaml" 可匹配任何層級的 YAML 檔案
+// pattern 中的 \ 為跳脫字元（如 \* 匹配字面上的 *）；路徑中的 \ 一律視為 Windows 的路徑分隔符
+package glob
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Match 檢查路徑是否符合 pattern
+// name 應為相對於目前目錄的路徑
+func Match(pattern, name string) bool {
+	name = normalize(name)
+	for _, p := range expandBraces(pattern) {
+		if matchExpanded(p, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchAny 檢查路徑是否符合 pattern 列表
+// 以 ! 開頭的 pattern 表示排除，依序比對，最後一個符合的 pattern 決定結果
+func MatchAny(patterns []string, name string) bool {
+	_, matched := MatchingPattern(patterns, name)
+	return matched
+}
+
+// MatchingPattern 與 MatchAny 相同，另外返回使路徑被納入的 pattern
+func MatchingPattern(patterns []string, name string) (string, bool) {
+	matchedBy := ""
+	for _, p := range patterns {
+		if strings.HasPrefix(p, "!") {
+			if matchedBy != "" && Match(p[1:], name) {
+				matchedBy = ""
+			}
+			continue
+		}
+		if matchedBy == "" && Match(p, name) {
+			matchedBy = p
+		}
+	}
+	return matchedBy, matchedBy != ""
+}
+
+// Validate 檢查 pattern 語法是否正確
+func Validate(pattern string) error {
+	p := strings.TrimPrefix(pattern, "!")
+	if p == "" {
+		return fmt.Errorf("pattern 不可為空")
+	}
+	if !balancedBraces(p) {
+		return fmt.Errorf("pattern %q 的大括號不成對", pattern)
+	}
+	for _, expanded := range expandBraces(p) {
+		for _, segment := range strings.Split(expanded, "/") {
+			if _, err := path.Match(toSegmentPattern(segment), ""); err != nil {
+				return fmt.Errorf("pattern %q 語法錯誤: %w", pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// matchExpanded 比對已展開大括號的 pattern
+func matchExpanded(pattern, name string) bool {
+	// 不含 / 的 pattern 只比對檔名
+	if !strings.Contains(pattern, "/") {
+		return matchSegment(pattern, path.Base(name))
+	}
+
+	// 開頭的 / 或 ./ 表示從根目錄開始
+	pattern = strings.TrimPrefix(strings.TrimPrefix(pattern, "./"), "/")
+	return matchSegments(strings.Split(pattern, "/"), splitPath(name))
+}
+
+// matchSegments 逐層比對路徑，** 可匹配零或多個層級
+func matchSegments(patterns, names []string) bool {
+	for len(patterns) > 0 {
+		if patterns[0] == "**" {
+			// 合併連續的 **
+			for len(patterns) > 1 && patterns[1] == "**" {
+				patterns = patterns[1:]
+			}
+			if len(patterns) == 1 {
+				return true
+			}
+			for i := 0; i <= len(names); i++ {
+				if matchSegments(patterns[1:], names[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(names) == 0 || !matchSegment(patterns[0], names[0]) {
+			return false
+		}
+		patterns, names = patterns[1:], names[1:]
+	}
+	return len(names) == 0
+}
+
+// matchSegment 比對單一路徑層級
+func matchSegment(pattern, name string) bool {
+	matched, err := path.Match(toSegmentPattern(pattern), name)
+	return err == nil && matched
+}
+
+// toSegmentPattern 轉換為 path.Match 可接受的語法
+// 層級內的 ** 等同 *，[!...] 等同 [^...]
+func toSegmentPattern(pattern string) string {
+	for strings.Contains(pattern, "**") {
+		pattern = strings.ReplaceAll(pattern, "**", "*")
+	}
+	return strings.ReplaceAll(pattern, "[!", "[^")
+}
+
+// expandBraces 展開大括號，如 "*.{yaml,yml}" -> ["*.yaml", "*.yml"]
+func expandBraces(pattern string) []string {
+	start, end := findBraces(pattern)
+	if start == -1 {
+		return []string{pattern}
+	}
+
+	prefix, body, suffix := pattern[:start], pattern[start+1:end], pattern[end+1:]
+	var expanded []string
+	for _, alternative := range splitAlternatives(body) {
+		expanded = append(expanded, expandBraces(prefix+alternative+suffix)...)
+	}
+	return expanded
+}
+
+// findBraces 找出第一組最外層的大括號位置
+func findBraces(pattern string) (int, int) {
+	depth, start := 0, -1
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case '{':
+			if depth == 0 {
+				start = i
+			}
+			depth++
+		case '}':
+			if depth == 0 {
+				continue
+			}
+			depth--
+			if depth == 0 {
+				return start, i
+			}
+		}
+	}
+	return -1, -1
+}
+
+// splitAlternatives 以最外層的逗號分割大括號內容
+func splitAlternatives(body string) []string {
+	var alternatives []string
+	depth, last := 0, 0
+	for i := 0; i < len(body); i++ {
+		switch body[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				alternatives = append(alternatives, body[last:i])
+				last = i + 1
+			}
+		}
+	}
+	return append(alternatives, body[last:])
+}
+
+// balancedBraces 檢查大括號是否成對（不計跳脫的大括號）
+func balancedBraces(pattern string) bool {
+	depth := 0
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				return false
+			}
+			depth--
+		}
+	}
+	return depth == 0
+}
+
+// normalize 標準化路徑：統一使用 /（包含非 Windows 平台上的 Windows 路徑），移除開頭的 ./
+func normalize(name string) string {
+	name = path.Clean(strings.ReplaceAll(filepath.ToSlash(name), "\\", "/"))
+	return strings.TrimPrefix(name, "./")
+}
+
+// splitPath 分割路徑層級
+func splitPath(name string) []string {
+	name = strings.TrimPrefix(name, "/")
+	if name == "" || name == "." {
+		return nil
+	}
+	return strings.Split(name, "/")
+}
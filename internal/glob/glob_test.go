@@ -0,0 +1,169 @@
+package glob
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		// 不含 / 的 pattern 只比對檔名
+		{"*.yaml", "api.yaml", true},
+		{"*.yaml", "configs/api/api.yaml", true},
+		{"*.yaml", "api.yml", false},
+		{"api-?.yaml", "a/api-1.yaml", true},
+		{"api-?.yaml", "a/api-10.yaml", false},
+
+		// 含 / 的 pattern 從根目錄比對
+		{"configs/*.yaml", "configs/api.yaml", true},
+		{"configs/*.yaml", "configs/api/api.yaml", false},
+		{"configs/*.yaml", "other/configs/api.yaml", false},
+		{"./configs/*.yaml", "configs/api.yaml", true},
+		{"/configs/*.yaml", "configs/api.yaml", true},
+		{"configs/*.yaml", "./configs/api.yaml", true},
+
+		// ** 在開頭
+		{"**/api.yaml", "api.yaml", true},
+		{"**/api.yaml", "a/b/c/api.yaml", true},
+		{"**/api/*.yaml", "services/api/prod.yaml", true},
+		{"**/api/*.yaml", "services/web/prod.yaml", false},
+
+		// ** 在中間
+		{"configs/**/api.yaml", "configs/api.yaml", true},
+		{"configs/**/api.yaml", "configs/a/b/api.yaml", true},
+		{"configs/**/api.yaml", "other/a/api.yaml", false},
+		{"a/**/**/b.yaml", "a/x/b.yaml", true},
+		{"a/**/x/**/b.yaml", "a/1/x/2/3/b.yaml", true},
+		{"a/**/x/**/b.yaml", "a/1/y/2/b.yaml", false},
+
+		// ** 在結尾
+		{"configs/**", "configs/api.yaml", true},
+		{"configs/**", "configs/a/b/c.json", true},
+		{"configs/**", "other/api.yaml", false},
+
+		// 層級內的 ** 等同 *
+		{"configs/api-**.yaml", "configs/api-prod.yaml", true},
+		{"configs/api-**.yaml", "configs/x/api-prod.yaml", false},
+
+		// 大括號
+		{"*.{yaml,yml}", "a.yml", true},
+		{"*.{yaml,yml}", "a.json", false},
+		{"{configs,deploy}/*.yaml", "deploy/a.yaml", true},
+		{"*.{y{a,}ml,json}", "a.yaml", true}, // 巢狀
+		{"*.{y{a,}ml,json}", "a.yml", true},
+		{"*.{y{a,}ml,json}", "a.json", true},
+		{"*.{y{a,}ml,json}", "a.xml", false},
+		{"api{,-prod}.yaml", "api.yaml", true}, // 空的選項
+		{"api{,-prod}.yaml", "api-prod.yaml", true},
+		{"api{}.yaml", "api.yaml", true}, // 空的大括號
+		{"**/{api,web}/**/*.{yaml,json}", "svc/web/x/y.json", true},
+
+		// 字元類別
+		{"api-[0-9].yaml", "api-7.yaml", true},
+		{"api-[0-9].yaml", "api-x.yaml", false},
+		{"api-[!0-9].yaml", "api-x.yaml", true},
+		{"api-[^0-9].yaml", "api-7.yaml", false},
+		{"[ab]*.yaml", "beta.yaml", true},
+		{"[ab]*.yaml", "core.yaml", false},
+
+		// 跳脫字元
+		{`api-\*.yaml`, "api-*.yaml", true},
+		{`api-\*.yaml`, "api-prod.yaml", false},
+		{`api\[1\].yaml`, "api[1].yaml", true},
+		{`\{a,b\}.yaml`, "{a,b}.yaml", true},
+		{`\{a,b\}.yaml`, "a.yaml", false},
+
+		// Windows 路徑分隔符
+		{"configs/*.yaml", `configs\api.yaml`, true},
+		{"configs/**/api.yaml", `configs\a\b\api.yaml`, true},
+		{"*.yaml", `configs\api\api.yaml`, true},
+		{"configs/*.yaml", `.\configs\api.yaml`, true},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMatchAny(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		name     string
+		want     bool
+		by       string
+	}{
+		{[]string{"*.yaml", "*.json"}, "a.json", true, "*.json"},
+		{[]string{"*.yaml"}, "a.json", false, ""},
+		{nil, "a.yaml", false, ""},
+		{[]string{"**/*.yaml", "!**/test/**"}, "configs/test/a.yaml", false, ""},
+		{[]string{"**/*.yaml", "!**/test/**"}, "configs/a.yaml", true, "**/*.yaml"},
+		// 後面的 pattern 可以重新納入被排除的路徑
+		{[]string{"**/*.yaml", "!**/test/**", "**/test/keep.yaml"}, "x/test/keep.yaml", true, "**/test/keep.yaml"},
+		// 排除只作用於前面已納入的路徑
+		{[]string{"!**/test/**", "**/*.yaml"}, "test/a.yaml", true, "**/*.yaml"},
+		{[]string{"!*.yaml"}, "a.yaml", false, ""},
+	}
+	for _, tt := range tests {
+		if got := MatchAny(tt.patterns, tt.name); got != tt.want {
+			t.Errorf("MatchAny(%q, %q) = %v, want %v", tt.patterns, tt.name, got, tt.want)
+		}
+		if by, _ := MatchingPattern(tt.patterns, tt.name); by != tt.by {
+			t.Errorf("MatchingPattern(%q, %q) = %q, want %q", tt.patterns, tt.name, by, tt.by)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		pattern string
+		valid   bool
+	}{
+		{"**/*.yaml", true},
+		{"!**/test/**", true},
+		{"*.{yaml,{yml,json}}", true},
+		{"api-[0-9].yaml", true},
+		{`api-\{x\}.yaml`, true},
+		{`\{`, true},
+		{"", false},
+		{"!", false},
+		{"*.{yaml,yml", false},
+		{"*.yaml}", false},
+		{"api-[0-9.yaml", false},
+		{"{a,[b}.yaml", false},
+		{`api\`, false},
+	}
+	for _, tt := range tests {
+		err := Validate(tt.pattern)
+		if (err == nil) != tt.valid {
+			t.Errorf("Validate(%q) = %v, want valid = %v", tt.pattern, err, tt.valid)
+		}
+	}
+}
+
+func TestExpandBraces(t *testing.T) {
+	tests := map[string][]string{
+		"a":               {"a"},
+		"*.{yaml,yml}":    {"*.yaml", "*.yml"},
+		"{a,b}/{c,d}":     {"a/c", "a/d", "b/c", "b/d"},
+		"x{a,{b,c}}":      {"xa", "xb", "xc"},
+		"x{}":             {"x"},
+		`x\{a,b\}`:        {`x\{a,b\}`},
+		`{a\,b,c}`:        {`a\,b`, "c"},
+		"*.{yaml,yml}.gz": {"*.yaml.gz", "*.yml.gz"},
+	}
+	for pattern, want := range tests {
+		got := expandBraces(pattern)
+		if len(got) != len(want) {
+			t.Errorf("expandBraces(%q) = %q, want %q", pattern, got, want)
+			continue
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("expandBraces(%q) = %q, want %q", pattern, got, want)
+				break
+			}
+		}
+	}
+}
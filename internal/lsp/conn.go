@@ -0,0 +1,139 @@
+// Package lsp 實作 Language Server Protocol 使用的 JSON-RPC 連線與協定型別
+// 只包含驗證器用到的部分：文件同步、診斷、hover 與 code action
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"sync"
+)
+
+// JSON-RPC 錯誤代碼
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+
+	// CodeServerNotInitialized 在 initialize 之前收到其他請求
+	CodeServerNotInitialized = -32002
+)
+
+// Request 收到的請求或通知（通知沒有 ID）
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// IsNotification 是否為不需要回應的通知
+func (r *Request) IsNotification() bool {
+	return len(r.ID) == 0
+}
+
+// ResponseError JSON-RPC 錯誤
+type ResponseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result"`
+}
+
+type errorResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Error   *ResponseError  `json:"error"`
+}
+
+type notification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+// Conn 以 Content-Length 標頭分隔訊息的 JSON-RPC 連線（LSP 的 base protocol）
+// Read 只能在一個 goroutine 中呼叫；Reply 與 Notify 可以同時呼叫
+type Conn struct {
+	r  *textproto.Reader
+	w  io.Writer
+	mu sync.Mutex
+}
+
+// NewConn 建立連線
+func NewConn(r io.Reader, w io.Writer) *Conn {
+	return &Conn{r: textproto.NewReader(bufio.NewReader(r)), w: w}
+}
+
+// Read 讀取下一個訊息，連線關閉時返回 io.EOF
+func (c *Conn) Read() (*Request, error) {
+	header, err := c.r.ReadMIMEHeader()
+	if err != nil {
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("讀取訊息標頭失敗: %w", err)
+	}
+
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("Content-Length 無效: %q", header.Get("Content-Length"))
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.r.R, body); err != nil {
+		return nil, fmt.Errorf("讀取訊息內容失敗: %w", err)
+	}
+
+	var req Request
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, &ResponseError{Code: CodeParseError, Message: err.Error()}
+	}
+	return &req, nil
+}
+
+// Reply 回應請求
+func (c *Conn) Reply(id json.RawMessage, result interface{}) error {
+	return c.write(&response{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+// ReplyError 以錯誤回應請求
+func (c *Conn) ReplyError(id json.RawMessage, code int, message string) error {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return c.write(&errorResponse{JSONRPC: "2.0", ID: id, Error: &ResponseError{Code: code, Message: message}})
+}
+
+// Notify 發送通知
+func (c *Conn) Notify(method string, params interface{}) error {
+	return c.write(&notification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func (c *Conn) write(msg interface{}) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("生成訊息失敗: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n", len(data)); err != nil {
+		return err
+	}
+	_, err = c.w.Write(data)
+	return err
+}
+
+func (e *ResponseError) Error() string {
+	return e.Message
+}
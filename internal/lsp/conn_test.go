@@ -0,0 +1,118 @@
+package lsp
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// frame 以 Content-Length 標頭包裝訊息
+func frame(body string) string {
+	return "Content-Length: " + strconv.Itoa(len(body)) + "\r\n\r\n" + body
+}
+
+func TestConnRead(t *testing.T) {
+	// 連續的訊息、其他標頭與含有多位元組字元的內容（Content-Length 以位元組計算）
+	input := frame(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`) +
+		"Content-Type: application/vscode-jsonrpc; charset=utf-8\r\n" +
+		frame(`{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"text":"名稱: 😀"}}`)
+	conn := NewConn(strings.NewReader(input), io.Discard)
+
+	req, err := conn.Read()
+	if err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	if req.Method != "initialize" || string(req.ID) != "1" || req.IsNotification() {
+		t.Errorf("Read() = %s id=%s, want initialize id=1", req.Method, req.ID)
+	}
+
+	req, err = conn.Read()
+	if err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	if req.Method != "textDocument/didOpen" || !req.IsNotification() {
+		t.Errorf("Read() = %s id=%s, want textDocument/didOpen 通知", req.Method, req.ID)
+	}
+	var params struct{ Text string }
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.Text != "名稱: 😀" {
+		t.Errorf("Params = %s, %v", req.Params, err)
+	}
+
+	if _, err := conn.Read(); err != io.EOF {
+		t.Errorf("Read() at end = %v, want io.EOF", err)
+	}
+}
+
+func TestConnReadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"缺少 Content-Length", "Content-Type: x\r\n\r\n{}", "Content-Length 無效"},
+		{"Content-Length 不是數字", "Content-Length: abc\r\n\r\n{}", "Content-Length 無效"},
+		{"內容不完整", "Content-Length: 10\r\n\r\n{}", "讀取訊息內容失敗"},
+	}
+	for _, tt := range tests {
+		_, err := NewConn(strings.NewReader(tt.input), io.Discard).Read()
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: Read() = %v, want 包含 %q", tt.name, err, tt.wantErr)
+		}
+	}
+
+	// 內容不是 JSON 時返回 ParseError，之後的訊息仍可讀取
+	conn := NewConn(strings.NewReader(frame("{bad")+frame(`{"method":"exit"}`)), io.Discard)
+	_, err := conn.Read()
+	if respErr, ok := err.(*ResponseError); !ok || respErr.Code != CodeParseError {
+		t.Errorf("Read() = %v, want ResponseError %d", err, CodeParseError)
+	}
+	if req, err := conn.Read(); err != nil || req.Method != "exit" {
+		t.Errorf("Read() after parse error = %v, %v", req, err)
+	}
+}
+
+func TestConnWrite(t *testing.T) {
+	var out bytes.Buffer
+	conn := NewConn(strings.NewReader(""), &out)
+	conn.Reply(json.RawMessage("7"), map[string]string{"name": "測試"})
+	conn.ReplyError(nil, CodeInvalidRequest, "bad")
+	conn.Notify("window/logMessage", nil)
+
+	// 寫出的訊息可以由 Read 依相同的格式讀回
+	want := frame(`{"jsonrpc":"2.0","id":7,"result":{"name":"測試"}}`) +
+		frame(`{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"bad"}}`) +
+		frame(`{"jsonrpc":"2.0","method":"window/logMessage","params":null}`)
+	if out.String() != want {
+		t.Errorf("輸出 =\n%q\nwant\n%q", out.String(), want)
+	}
+}
+
+func TestConnConcurrentWrite(t *testing.T) {
+	// 同時發送的訊息不會交錯
+	var out bytes.Buffer
+	conn := NewConn(strings.NewReader(""), &out)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			conn.Notify("test", map[string]int{"n": i})
+		}(i)
+	}
+	wg.Wait()
+
+	reader := NewConn(&out, io.Discard)
+	for i := 0; i < 50; i++ {
+		req, err := reader.Read()
+		if err != nil || req.Method != "test" {
+			t.Fatalf("第 %d 個訊息: %v, %v", i, req, err)
+		}
+	}
+	if _, err := reader.Read(); err != io.EOF {
+		t.Errorf("Read() at end = %v, want io.EOF", err)
+	}
+}
@@ -0,0 +1,85 @@
+package lsp
+
+// Text 編輯器中的文件內容
+// 驗證結果的行列從 1 開始且以 rune 計算，LSP 的位置從 0 開始且以 UTF-16 code unit 計算，由 Text 負責轉換
+type Text struct {
+	content    string
+	lineStarts []int // 每一行開頭的位元組位移
+}
+
+// NewText 建立文件內容
+func NewText(content string) *Text {
+	t := &Text{content: content, lineStarts: []int{0}}
+	for i := 0; i < len(content); i++ {
+		if content[i] == '\n' {
+			t.lineStarts = append(t.lineStarts, i+1)
+		}
+	}
+	return t
+}
+
+// String 返回完整內容
+func (t *Text) String() string {
+	return t.content
+}
+
+// line 返回第 line 行（從 0 開始）的內容，不含換行
+func (t *Text) line(line int) string {
+	if line < 0 || line >= len(t.lineStarts) {
+		return ""
+	}
+	end := len(t.content)
+	if line+1 < len(t.lineStarts) {
+		end = t.lineStarts[line+1] - 1
+	}
+	text := t.content[t.lineStarts[line]:end]
+	if len(text) > 0 && text[len(text)-1] == '\r' {
+		text = text[:len(text)-1]
+	}
+	return text
+}
+
+// Position 將驗證結果的行列（從 1 開始，欄以 rune 計算）轉換為 LSP 位置
+// 超出該行長度的欄位會停在行尾
+func (t *Text) Position(line, column int) Position {
+	text := t.line(line - 1)
+	character, runes := 0, 0
+	for _, r := range text {
+		if runes >= column-1 {
+			break
+		}
+		character += utf16Len(r)
+		runes++
+	}
+	return Position{Line: line - 1, Character: character}
+}
+
+// LineEnd 返回第 line 行（從 1 開始）行尾的 LSP 位置
+func (t *Text) LineEnd(line int) Position {
+	character := 0
+	for _, r := range t.line(line - 1) {
+		character += utf16Len(r)
+	}
+	return Position{Line: line - 1, Character: character}
+}
+
+// OffsetPosition 將位元組位移轉換為 LSP 位置
+func (t *Text) OffsetPosition(offset int) Position {
+	line := 0
+	for line+1 < len(t.lineStarts) && t.lineStarts[line+1] <= offset {
+		line++
+	}
+	character := 0
+	for _, r := range t.content[t.lineStarts[line]:offset] {
+		character += utf16Len(r)
+	}
+	return Position{Line: line, Character: character}
+}
+
+// utf16Len rune 以 UTF-16 編碼的長度，BMP 以外的字元（如 emoji）為兩個 code unit
+func utf16Len(r rune) int {
+	if r >= 0x10000 {
+		return 2
+	}
+	return 1
+}
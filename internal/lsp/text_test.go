@@ -0,0 +1,69 @@
+package lsp
+
+import "testing"
+
+func TestTextPosition(t *testing.T) {
+	// 第 2 行含有 BMP 以外的字元（UTF-16 佔兩個 code unit），第 3 行以 \r\n 結尾
+	text := NewText("a: 1\n😀é: x\r\nname: 測試\n")
+	tests := []struct {
+		line, column int
+		want         Position
+	}{
+		{1, 1, Position{Line: 0, Character: 0}},
+		{1, 4, Position{Line: 0, Character: 3}},
+		{2, 2, Position{Line: 1, Character: 2}}, // 😀 之後
+		{2, 3, Position{Line: 1, Character: 3}}, // é 之後
+		{2, 5, Position{Line: 1, Character: 5}},
+		{2, 99, Position{Line: 1, Character: 6}}, // 超出行尾，停在 \r 之前
+		{3, 8, Position{Line: 2, Character: 7}},
+		{4, 1, Position{Line: 3, Character: 0}}, // 結尾換行之後的空行
+		{9, 1, Position{Line: 8, Character: 0}}, // 超出文件範圍
+	}
+	for _, tt := range tests {
+		if got := text.Position(tt.line, tt.column); got != tt.want {
+			t.Errorf("Position(%d, %d) = %+v, want %+v", tt.line, tt.column, got, tt.want)
+		}
+	}
+}
+
+func TestTextLineEnd(t *testing.T) {
+	text := NewText("a: 1\n😀é: x\r\n名稱")
+	tests := []struct {
+		line int
+		want Position
+	}{
+		{1, Position{Line: 0, Character: 4}},
+		{2, Position{Line: 1, Character: 6}}, // 不含 \r
+		{3, Position{Line: 2, Character: 2}}, // 最後一行沒有換行
+		{4, Position{Line: 3, Character: 0}},
+	}
+	for _, tt := range tests {
+		if got := text.LineEnd(tt.line); got != tt.want {
+			t.Errorf("LineEnd(%d) = %+v, want %+v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestTextOffsetPosition(t *testing.T) {
+	content := "a: 1\n😀é: x\n"
+	text := NewText(content)
+	tests := []struct {
+		offset int
+		want   Position
+	}{
+		{0, Position{Line: 0, Character: 0}},
+		{4, Position{Line: 0, Character: 4}}, // 換行字元本身屬於前一行
+		{5, Position{Line: 1, Character: 0}},
+		{9, Position{Line: 1, Character: 2}},  // 😀 佔 4 個位元組
+		{11, Position{Line: 1, Character: 3}}, // é 佔 2 個位元組
+		{len(content), Position{Line: 2, Character: 0}},
+	}
+	for _, tt := range tests {
+		if got := text.OffsetPosition(tt.offset); got != tt.want {
+			t.Errorf("OffsetPosition(%d) = %+v, want %+v", tt.offset, got, tt.want)
+		}
+	}
+	if text.String() != content {
+		t.Errorf("String() = %q, want %q", text.String(), content)
+	}
+}
@@ -0,0 +1,101 @@
+package parser
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Document 已解析的配置檔
+// 不論原始格式為何，都提供相同的路徑 API，規則不需要知道檔案格式
+type Document interface {
+	GetValue(path string) (interface{}, bool)
+	HasField(path string) bool
+	GetArray(path string) ([]interface{}, bool)
+	GetString(path string) (string, bool)
+	GetNumber(path string) (float64, bool)
+	GetBool(path string) (bool, bool)
+	GetType(path string) string
+	GetMap(path string) (map[string]interface{}, bool)
+	ExpandWildcardPath(path string) []*PathInfo
+	CheckArrayDuplicates(arrayPath string, field string) ([]*DuplicateInfo, error)
+	CheckArrayMultiFieldDuplicates(arrayPath string, fields []string) ([]*DuplicateInfo, error)
+
+	// GetPosition 與 LocatePath 返回路徑在原始檔案中的位置
+	GetPosition(path string) (Position, bool)
+	LocatePath(path string) Position
+
+	// Documents 返回檔案中的每個文件，只有 YAML 支援多文件
+	Documents() []Document
+	// DocumentIndex 返回文件在檔案中的序號（從 1 開始，空文件也計入）
+	DocumentIndex() int
+
+	// Suppressions 返回檔案中的抑制註解
+	Suppressions() []*Suppression
+}
+
+// Parser 配置檔解析器
+type Parser interface {
+	Document
+	ParseFile(filePath string) error
+	// ParseBytes 解析記憶體中的內容（如編輯器中尚未儲存的文件）
+	ParseBytes(content []byte) error
+}
+
+// 支援的檔案格式
+const (
+	FormatYAML       = "yaml"
+	FormatJSON       = "json"
+	FormatTOML       = "toml"
+	FormatEnv        = "env"
+	FormatProperties = "properties"
+	FormatINI        = "ini"
+)
+
+// formatByExtension 副檔名對應的格式
+var formatByExtension = map[string]string{
+	".yaml":       FormatYAML,
+	".yml":        FormatYAML,
+	".json":       FormatJSON,
+	".toml":       FormatTOML,
+	".env":        FormatEnv,
+	".properties": FormatProperties,
+	".ini":        FormatINI,
+	".cfg":        FormatINI,
+}
+
+// FormatOf 依副檔名判斷檔案格式，不支援時返回空字串
+// .env 也可以作為前綴，如 .env.production
+func FormatOf(filePath string) string {
+	name := filepath.Base(filePath)
+	if name == ".env" || strings.HasPrefix(name, ".env.") {
+		return FormatEnv
+	}
+	return formatByExtension[strings.ToLower(filepath.Ext(name))]
+}
+
+// NewParser 建立指定格式的解析器
+func NewParser(format string) (Parser, error) {
+	switch format {
+	case FormatYAML:
+		return NewYAMLParser(), nil
+	case FormatJSON:
+		return NewJSONParser(), nil
+	case FormatTOML:
+		return NewTOMLParser(), nil
+	case FormatEnv:
+		return NewEnvParser(), nil
+	case FormatProperties:
+		return NewPropertiesParser(), nil
+	case FormatINI:
+		return NewINIParser(), nil
+	default:
+		return nil, fmt.Errorf("不支援的檔案格式: %s", format)
+	}
+}
+
+// IsFormat 檢查是否為支援的格式名稱
+func IsFormat(format string) bool {
+	_, err := NewParser(format)
+	return err == nil
+}
@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// parseCase 解析器的測試案例：content 解析後，values 中的路徑應有對應的值，positions 中的路徑應在對應的行列
+// wantErr 不為空時預期解析失敗，錯誤訊息應包含 wantErr
+type parseCase struct {
+	name      string
+	content   string
+	values    map[string]interface{}
+	positions map[string][2]int // 路徑 -> {行, 欄}
+	wantErr   string
+}
+
+// runParseCases 以 format 的解析器執行測試案例
+func runParseCases(t *testing.T, format string, tests []parseCase) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewParser(format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = p.ParseBytes([]byte(tt.content))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseBytes() = %v, want 包含 %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseBytes: %v", err)
+			}
+
+			for path, want := range tt.values {
+				got, ok := p.GetValue(path)
+				if !ok {
+					t.Errorf("GetValue(%q) 找不到路徑", path)
+					continue
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("GetValue(%q) = %#v, want %#v", path, got, want)
+				}
+			}
+			for path, want := range tt.positions {
+				pos, ok := p.GetPosition(path)
+				if !ok {
+					t.Errorf("GetPosition(%q) 找不到路徑", path)
+					continue
+				}
+				if got := [2]int{pos.Line, pos.Column}; got != want {
+					t.Errorf("GetPosition(%q) = %d:%d, want %d:%d", path, got[0], got[1], want[0], want[1])
+				}
+			}
+		})
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	tests := map[string]string{
+		"a/api.yaml":          FormatYAML,
+		"a/api.YML":           FormatYAML,
+		"api.json":            FormatJSON,
+		"Cargo.toml":          FormatTOML,
+		".env":                FormatEnv,
+		"svc/.env.production": FormatEnv,
+		"app.properties":      FormatProperties,
+		"setup.cfg":           FormatINI,
+		"php.ini":             FormatINI,
+		"README.md":           "",
+		"env":                 "",
+	}
+	for path, want := range tests {
+		if got := FormatOf(path); got != want {
+			t.Errorf("FormatOf(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
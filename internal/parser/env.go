@@ -0,0 +1,195 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvParser 處理 .env 檔案解析
+// 每行一個 KEY=value，支援 export 前綴、引號與行尾註解；key 不會拆分為巢狀路徑
+type EnvParser struct {
+	*YAMLParser
+}
+
+// NewEnvParser 建立新的 .env 解析器
+func NewEnvParser() *EnvParser {
+	return &EnvParser{YAMLParser: NewYAMLParser()}
+}
+
+// ParseFile 解析 .env 檔案
+func (p *EnvParser) ParseFile(filePath string) error {
+	return parseKeyValueFile(p.YAMLParser, filePath, "env", decodeEnv)
+}
+
+// ParseBytes 解析 .env 內容
+func (p *EnvParser) ParseBytes(content []byte) error {
+	return parseKeyValue(p.YAMLParser, content, "env", decodeEnv)
+}
+
+// PropertiesParser 處理 Java .properties 檔案解析
+// key 以 . 拆分為巢狀路徑，如 server.port=8080 可用路徑 server.port 取得
+type PropertiesParser struct {
+	*YAMLParser
+}
+
+// NewPropertiesParser 建立新的 .properties 解析器
+func NewPropertiesParser() *PropertiesParser {
+	return &PropertiesParser{YAMLParser: NewYAMLParser()}
+}
+
+// ParseFile 解析 .properties 檔案
+func (p *PropertiesParser) ParseFile(filePath string) error {
+	return parseKeyValueFile(p.YAMLParser, filePath, "properties", decodeProperties)
+}
+
+// ParseBytes 解析 .properties 內容
+func (p *PropertiesParser) ParseBytes(content []byte) error {
+	return parseKeyValue(p.YAMLParser, content, "properties", decodeProperties)
+}
+
+// parseKeyValueFile 讀取檔案並以 decode 轉換為節點樹
+func parseKeyValueFile(p *YAMLParser, filePath, format string, decode func(string) (*yaml.Node, error)) error {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("讀取檔案失敗: %w", err)
+	}
+	return parseKeyValue(p, content, format, decode)
+}
+
+// parseKeyValue 以 decode 將內容轉換為節點樹
+func parseKeyValue(p *YAMLParser, content []byte, format string, decode func(string) (*yaml.Node, error)) error {
+	root, err := decode(string(content))
+	if err != nil {
+		return fmt.Errorf("解析 %s 失敗: %w", format, err)
+	}
+
+	roots := []*yaml.Node{root}
+	if err := p.load(roots); err != nil {
+		return err
+	}
+	p.suppressions = parseSuppressions(content, roots)
+	return nil
+}
+
+// decodeEnv 解析 .env 內容
+func decodeEnv(content string) (*yaml.Node, error) {
+	root := newMappingNode(1, 1)
+	for i, line := range strings.Split(content, "\n") {
+		lineNo := i + 1
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		indent := leadingSpace(line)
+		rest := line[indent:]
+		if strings.HasPrefix(rest, "export ") {
+			indent += len("export ")
+			rest = strings.TrimLeft(line[indent:], " \t")
+			indent = len(line) - len(rest)
+		}
+
+		eq := strings.Index(rest, "=")
+		if eq <= 0 {
+			return nil, fmt.Errorf("第 %d 行: 預期 KEY=value", lineNo)
+		}
+		key := strings.TrimSpace(rest[:eq])
+		valueStart := indent + eq + 1
+		valueStart += leadingSpace(line[valueStart:])
+
+		value, err := decodeLineValue(line[valueStart:], lineNo, column(line, valueStart))
+		if err != nil {
+			return nil, err
+		}
+		setKey(root, newStringNode(key, false, lineNo, column(line, indent)), value)
+	}
+	return root, nil
+}
+
+// decodeProperties 解析 .properties 內容
+// 支援 = 或 : 分隔、# 與 ! 註解，以及行尾反斜線續行
+func decodeProperties(content string) (*yaml.Node, error) {
+	root := newMappingNode(1, 1)
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimRight(lines[i], "\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "!") {
+			continue
+		}
+
+		// 行尾的反斜線表示下一行是同一個值的延續
+		for strings.HasSuffix(line, "\\") && i+1 < len(lines) {
+			i++
+			line = strings.TrimSuffix(line, "\\") + strings.TrimLeft(strings.TrimRight(lines[i], "\r"), " \t")
+		}
+
+		indent := leadingSpace(line)
+		sep := strings.IndexAny(line[indent:], "=:")
+		if sep <= 0 {
+			return nil, fmt.Errorf("第 %d 行: 預期 key=value", lineNo)
+		}
+		key := strings.TrimSpace(line[indent : indent+sep])
+		valueStart := indent + sep + 1
+		valueStart += leadingSpace(line[valueStart:])
+		value := strings.TrimSpace(line[valueStart:])
+
+		parts := strings.Split(key, ".")
+		parent, err := ensureMapping(root, parts[:len(parts)-1], lineNo, column(line, indent))
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 行: %w", lineNo, err)
+		}
+		last := parts[len(parts)-1]
+		if existing := lookupKey(parent, last); existing != nil && existing.Kind == yaml.MappingNode {
+			return nil, fmt.Errorf("第 %d 行: %s 已定義為區段，不能再作為值", lineNo, key)
+		}
+		setKey(parent, newStringNode(last, false, lineNo, column(line, indent)),
+			newStringNode(value, false, lineNo, column(line, valueStart)))
+	}
+	return root, nil
+}
+
+// decodeLineValue 解析 .env 與 INI 的值，一律為字串
+// 這些格式沒有型別，不依 YAML 的規則推斷（避免 01234 變成 668、1.10 變成 1.1）；需要數字的規則自行轉換
+// 有引號時去除引號（雙引號支援跳脫字元）；沒有引號時去除行尾以空白開頭的註解
+func decodeLineValue(raw string, line, col int) (*yaml.Node, error) {
+	raw = strings.TrimRight(raw, "\r")
+	if raw != "" && (raw[0] == '"' || raw[0] == '\'') {
+		quote := raw[0]
+		end := strings.LastIndexByte(raw, quote)
+		if end == 0 {
+			return nil, fmt.Errorf("第 %d 行: 引號沒有結尾", line)
+		}
+		value := raw[1:end]
+		if quote == '"' {
+			unquoted, err := strconv.Unquote(`"` + strings.ReplaceAll(value, "\n", `\n`) + `"`)
+			if err == nil {
+				value = unquoted
+			}
+		}
+		return newStringNode(value, true, line, col), nil
+	}
+
+	for _, marker := range []string{" #", "\t#", " ;", "\t;"} {
+		if idx := strings.Index(raw, marker); idx != -1 {
+			raw = raw[:idx]
+		}
+	}
+	return newStringNode(strings.TrimSpace(raw), false, line, col), nil
+}
+
+// leadingSpace 返回開頭空白的位元組數
+func leadingSpace(s string) int {
+	return len(s) - len(strings.TrimLeft(s, " \t"))
+}
+
+// column 將位元組位置轉換為欄號（從 1 開始，以字元計算）
+func column(line string, offset int) int {
+	return utf8.RuneCountInString(line[:offset]) + 1
+}
@@ -0,0 +1,96 @@
+package parser
+
+import "testing"
+
+func TestEnvParser(t *testing.T) {
+	runParseCases(t, FormatEnv, []parseCase{
+		{
+			name:    "值一律為字串",
+			content: "ZIP=01234\nVERSION=1.10\nDATE=2024-01-01\nNOTHING=null\nDEBUG=true\nEMPTY=\n",
+			values: map[string]interface{}{
+				"ZIP":     "01234",
+				"VERSION": "1.10",
+				"DATE":    "2024-01-01",
+				"NOTHING": "null",
+				"DEBUG":   "true",
+				"EMPTY":   "",
+			},
+		},
+		{
+			name: "引號與跳脫字元",
+			content: `DOUBLE="a\tb \"c\""
+SINGLE='raw\n # 不是註解'
+SPACED = "  padded  "
+HASH="a # b"
+`,
+			values: map[string]interface{}{
+				"DOUBLE": "a\tb \"c\"",
+				"SINGLE": `raw\n # 不是註解`,
+				"SPACED": "  padded  ",
+				"HASH":   "a # b",
+			},
+		},
+		{
+			name:    "export 與行尾註解",
+			content: "# 註解\nexport  HOST=localhost # 主機\nPORT=8080\t# 連接埠\nURL=http://x/#frag\n",
+			values: map[string]interface{}{
+				"HOST": "localhost",
+				"PORT": "8080",
+				"URL":  "http://x/#frag",
+			},
+			positions: map[string][2]int{"HOST": {2, 14}, "PORT": {3, 6}},
+		},
+		{
+			name:    "key 不拆分為巢狀路徑",
+			content: "a.b=1\n",
+			values:  map[string]interface{}{"": map[string]interface{}{"a.b": "1"}},
+		},
+		{
+			name:      "重複的 key 以最後一個為準",
+			content:   "A=1\nA=2\n",
+			values:    map[string]interface{}{"A": "2"},
+			positions: map[string][2]int{"A": {2, 3}},
+		},
+		{
+			name:      "CRLF 與多位元組字元",
+			content:   "名稱=服務\r\nPORT=80\r\n",
+			values:    map[string]interface{}{"名稱": "服務", "PORT": "80"},
+			positions: map[string][2]int{"名稱": {1, 4}},
+		},
+		{name: "缺少等號", content: "A=1\nINVALID\n", wantErr: "第 2 行"},
+		{name: "引號沒有結尾", content: "A=\"abc\n", wantErr: "引號沒有結尾"},
+	})
+}
+
+func TestPropertiesParser(t *testing.T) {
+	runParseCases(t, FormatProperties, []parseCase{
+		{
+			name:    "key 拆分為巢狀路徑",
+			content: "# 註解\n! 也是註解\nserver.port=8080\nserver.host : localhost\nname=app\n",
+			values: map[string]interface{}{
+				"server.port": "8080",
+				"server.host": "localhost",
+				"name":        "app",
+			},
+			positions: map[string][2]int{"server.port": {3, 13}, "server.host": {4, 15}},
+		},
+		{
+			name:    "值一律為字串",
+			content: "zip=01234\nversion=1.10\nflag=false\n",
+			values:  map[string]interface{}{"zip": "01234", "version": "1.10", "flag": "false"},
+		},
+		{
+			name:    "續行",
+			content: "list=a,\\\n     b,\\\n     c\nnext=1\n",
+			values:  map[string]interface{}{"list": "a,b,c", "next": "1"},
+		},
+		{
+			name:    "重複的 key 以最後一個為準",
+			content: "a.b=1\na.b=2\n",
+			values:  map[string]interface{}{"a.b": "2"},
+		},
+		{name: "值已定義為區段", content: "a.b=1\na=2\n", wantErr: "a 已定義為區段"},
+		{name: "區段已定義為值", content: "a=1\na.b=2\n", wantErr: "a 已定義為值"},
+		{name: "缺少分隔符號", content: "a\n", wantErr: "第 1 行"},
+	})
+}
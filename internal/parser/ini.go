@@ -0,0 +1,95 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// INIParser 處理 INI 檔案解析
+// [section] 之後的 key 放在 section 之下，如 [database] 的 host 可用路徑 database.host 取得；
+// section 名稱以 . 拆分為巢狀路徑，第一個 section 之前的 key 位於最上層
+type INIParser struct {
+	*YAMLParser
+}
+
+// NewINIParser 建立新的 INI 解析器
+func NewINIParser() *INIParser {
+	return &INIParser{YAMLParser: NewYAMLParser()}
+}
+
+// ParseFile 解析 INI 檔案
+func (p *INIParser) ParseFile(filePath string) error {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("讀取檔案失敗: %w", err)
+	}
+	return p.ParseBytes(content)
+}
+
+// ParseBytes 解析 INI 內容
+func (p *INIParser) ParseBytes(content []byte) error {
+	root, err := decodeINI(string(content))
+	if err != nil {
+		return fmt.Errorf("解析 INI 失敗: %w", err)
+	}
+
+	roots := []*yaml.Node{root}
+	if err := p.load(roots); err != nil {
+		return err
+	}
+	p.suppressions = parseSuppressions(content, roots)
+	return nil
+}
+
+// decodeINI 解析 INI 內容
+func decodeINI(content string) (*yaml.Node, error) {
+	root := newMappingNode(1, 1)
+	section := root
+	for i, line := range strings.Split(content, "\n") {
+		lineNo := i + 1
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";") {
+			continue
+		}
+
+		indent := leadingSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			end := strings.Index(trimmed, "]")
+			if end == -1 {
+				return nil, fmt.Errorf("第 %d 行: section 缺少 ]", lineNo)
+			}
+			name := strings.TrimSpace(trimmed[1:end])
+			if name == "" {
+				return nil, fmt.Errorf("第 %d 行: section 名稱不可為空", lineNo)
+			}
+			var err error
+			section, err = ensureMapping(root, strings.Split(name, "."), lineNo, column(line, indent+1))
+			if err != nil {
+				return nil, fmt.Errorf("第 %d 行: %w", lineNo, err)
+			}
+			continue
+		}
+
+		sep := strings.IndexAny(line[indent:], "=:")
+		if sep <= 0 {
+			return nil, fmt.Errorf("第 %d 行: 預期 key=value", lineNo)
+		}
+		key := strings.TrimSpace(line[indent : indent+sep])
+		valueStart := indent + sep + 1
+		valueStart += leadingSpace(line[valueStart:])
+
+		value, err := decodeLineValue(line[valueStart:], lineNo, column(line, valueStart))
+		if err != nil {
+			return nil, err
+		}
+		if existing := lookupKey(section, key); existing != nil && existing.Kind == yaml.MappingNode {
+			return nil, fmt.Errorf("第 %d 行: %s 已定義為 section，不能再作為值", lineNo, key)
+		}
+		setKey(section, newStringNode(key, false, lineNo, column(line, indent)), value)
+	}
+	return root, nil
+}
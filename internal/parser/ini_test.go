@@ -0,0 +1,53 @@
+package parser
+
+import "testing"
+
+func TestINIParser(t *testing.T) {
+	runParseCases(t, FormatINI, []parseCase{
+		{
+			name: "section 與值",
+			content: `; 註解
+name = app
+
+[database]
+host = localhost ; 主機
+port: 5432
+
+[server.http]
+timeout = 30
+`,
+			values: map[string]interface{}{
+				"name":                "app",
+				"database.host":       "localhost",
+				"database.port":       "5432",
+				"server.http.timeout": "30",
+			},
+			positions: map[string][2]int{
+				"database":            {4, 2},
+				"database.host":       {5, 8},
+				"database.port":       {6, 7},
+				"server.http.timeout": {9, 11},
+			},
+		},
+		{
+			name:    "值一律為字串",
+			content: "[a]\nzip = 01234\ndate = 2024-01-01\nenabled = yes\n",
+			values:  map[string]interface{}{"a.zip": "01234", "a.date": "2024-01-01", "a.enabled": "yes"},
+		},
+		{
+			name:    "引號",
+			content: "[a]\nq = \"x ; y\"\ns = 'a\\tb'\n",
+			values:  map[string]interface{}{"a.q": "x ; y", "a.s": `a\tb`},
+		},
+		{
+			name:    "重複的 section 合併，重複的 key 以最後一個為準",
+			content: "[a]\nx = 1\ny = 1\n[b]\nz = 1\n[a]\nx = 2\n",
+			values:  map[string]interface{}{"a.x": "2", "a.y": "1", "b.z": "1"},
+		},
+		{name: "section 缺少 ]", content: "[a\n", wantErr: "section 缺少 ]"},
+		{name: "section 名稱為空", content: "[ ]\n", wantErr: "section 名稱不可為空"},
+		{name: "section 已定義為值", content: "a = 1\n[a]\n", wantErr: "a 已定義為值"},
+		{name: "值已定義為 section", content: "[a.b]\n[a]\nb = 1\n", wantErr: "b 已定義為 section"},
+		{name: "缺少分隔符號", content: "[a]\nkey\n", wantErr: "第 2 行"},
+	})
+}
@@ -0,0 +1,208 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// JSONParser 處理 JSON 檔案解析
+// 以 encoding/json 逐一讀取 token 建立與 YAML 相同的節點樹，共用 YAMLParser 的路徑 API
+// JSON 沒有註解，因此不支援抑制註解
+type JSONParser struct {
+	*YAMLParser
+}
+
+// NewJSONParser 建立新的 JSON 解析器
+func NewJSONParser() *JSONParser {
+	return &JSONParser{YAMLParser: NewYAMLParser()}
+}
+
+// ParseFile 解析 JSON 檔案
+func (p *JSONParser) ParseFile(filePath string) error {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("讀取檔案失敗: %w", err)
+	}
+	return p.ParseBytes(content)
+}
+
+// ParseBytes 解析 JSON 內容
+func (p *JSONParser) ParseBytes(content []byte) error {
+	root, err := decodeJSON(content)
+	if err != nil {
+		return fmt.Errorf("解析 JSON 失敗: %w", err)
+	}
+	return p.load([]*yaml.Node{root})
+}
+
+// jsonDecoder 將 JSON token 轉換為節點樹，並以位元組位置計算每個節點的行列
+type jsonDecoder struct {
+	content    []byte
+	dec        *json.Decoder
+	lineStarts []int // 每行起始的位元組位置
+}
+
+// decodeJSON 解析 JSON 內容為節點樹，最上層必須是物件
+// 重複的 key 與 encoding/json 相同，以最後一個值為準
+func decodeJSON(content []byte) (*yaml.Node, error) {
+	d := &jsonDecoder{
+		content:    content,
+		dec:        json.NewDecoder(bytes.NewReader(content)),
+		lineStarts: []int{0},
+	}
+	d.dec.UseNumber()
+	for i, b := range content {
+		if b == '\n' {
+			d.lineStarts = append(d.lineStarts, i+1)
+		}
+	}
+
+	root, err := d.value()
+	if err != nil {
+		return nil, err
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("最上層必須是物件")
+	}
+	if _, err := d.dec.Token(); !errors.Is(err, io.EOF) {
+		line, _ := d.position(int(d.dec.InputOffset()))
+		return nil, fmt.Errorf("第 %d 行: 物件結尾之後有多餘的內容", line)
+	}
+	return root, nil
+}
+
+// value 讀取下一個值並建立節點
+func (d *jsonDecoder) value() (*yaml.Node, error) {
+	line, col := d.position(d.nextTokenStart())
+	token, err := d.dec.Token()
+	if err != nil {
+		return nil, d.wrapError(err)
+	}
+
+	switch v := token.(type) {
+	case json.Delim:
+		switch v {
+		case '{':
+			return d.object(line, col)
+		case '[':
+			return d.array(line, col)
+		}
+		return nil, fmt.Errorf("第 %d 行: 非預期的 %s", line, v)
+	case string:
+		return newStringNode(v, true, line, col), nil
+	case json.Number:
+		return numberNode(v, line, col)
+	case bool:
+		return newScalarNode(strconv.FormatBool(v), "!!bool", 0, line, col), nil
+	case nil:
+		return newScalarNode("null", "!!null", 0, line, col), nil
+	}
+	return nil, fmt.Errorf("第 %d 行: 無法解析的值", line)
+}
+
+// object 讀取物件的內容（{ 已讀取）
+func (d *jsonDecoder) object(line, col int) (*yaml.Node, error) {
+	mapping := newMappingNode(line, col)
+	mapping.Style = yaml.FlowStyle
+	for d.dec.More() {
+		keyLine, keyCol := d.position(d.nextTokenStart())
+		token, err := d.dec.Token()
+		if err != nil {
+			return nil, d.wrapError(err)
+		}
+		key, ok := token.(string)
+		if !ok {
+			return nil, fmt.Errorf("第 %d 行: 物件的 key 必須是字串", keyLine)
+		}
+		value, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		setKey(mapping, newStringNode(key, true, keyLine, keyCol), value)
+	}
+	if _, err := d.dec.Token(); err != nil {
+		return nil, d.wrapError(err)
+	}
+	return mapping, nil
+}
+
+// array 讀取陣列的內容（[ 已讀取）
+func (d *jsonDecoder) array(line, col int) (*yaml.Node, error) {
+	sequence := newSequenceNode(line, col)
+	sequence.Style = yaml.FlowStyle
+	for d.dec.More() {
+		item, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		sequence.Content = append(sequence.Content, item)
+	}
+	if _, err := d.dec.Token(); err != nil {
+		return nil, d.wrapError(err)
+	}
+	return sequence, nil
+}
+
+// nextTokenStart 返回下一個 token 的起始位置
+// Decoder 讀取 token 時會一併略過前面的空白與 , :，因此從目前位置往後略過這些字元即是 token 的開頭
+func (d *jsonDecoder) nextTokenStart() int {
+	offset := int(d.dec.InputOffset())
+	for offset < len(d.content) {
+		switch d.content[offset] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			offset++
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+// position 將位元組位置轉換為行列（從 1 開始，欄位以字元計算）
+func (d *jsonDecoder) position(offset int) (int, int) {
+	line := 1
+	for line < len(d.lineStarts) && d.lineStarts[line] <= offset {
+		line++
+	}
+	start := d.lineStarts[line-1]
+	if offset > len(d.content) {
+		offset = len(d.content)
+	}
+	return line, utf8.RuneCount(d.content[start:offset]) + 1
+}
+
+// wrapError 在語法錯誤加上行號
+func (d *jsonDecoder) wrapError(err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		line, _ := d.position(int(syntaxErr.Offset))
+		return fmt.Errorf("第 %d 行: %s", line, syntaxErr.Error())
+	}
+	if errors.Is(err, io.EOF) {
+		return fmt.Errorf("內容不完整")
+	}
+	return err
+}
+
+// numberNode 建立數字節點，整數超出 int64 範圍時以浮點數表示
+func numberNode(n json.Number, line, col int) (*yaml.Node, error) {
+	text := n.String()
+	if !strings.ContainsAny(text, ".eE") {
+		if _, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return newScalarNode(text, "!!int", 0, line, col), nil
+		}
+	}
+	if _, err := strconv.ParseFloat(text, 64); err != nil {
+		return nil, fmt.Errorf("第 %d 行: 數字超出範圍: %s", line, text)
+	}
+	return newScalarNode(text, "!!float", 0, line, col), nil
+}
@@ -0,0 +1,70 @@
+package parser
+
+import "testing"
+
+func TestJSONParser(t *testing.T) {
+	runParseCases(t, FormatJSON, []parseCase{
+		{
+			name:    "跳脫的斜線",
+			content: `{"u":"http:\/\/x"}`,
+			values:  map[string]interface{}{"u": "http://x"},
+		},
+		{
+			name:    "代理對",
+			content: `{"emoji": "\ud83d\ude00", "tab": "a\tb", "quote": "\"q\""}`,
+			values:  map[string]interface{}{"emoji": "😀", "tab": "a\tb", "quote": `"q"`},
+		},
+		{
+			name:      "重複的 key 以最後一個為準",
+			content:   `{"a":1,"a":2}`,
+			values:    map[string]interface{}{"a": 2},
+			positions: map[string][2]int{"a": {1, 12}},
+		},
+		{
+			name:    "型別",
+			content: `{"i": -12, "f": 1.5, "e": 1E3, "big": 99999999999999999999, "t": true, "n": null, "s": "01234"}`,
+			values: map[string]interface{}{
+				"i":   -12,
+				"f":   1.5,
+				"e":   1000.0,
+				"big": 1e20,
+				"t":   true,
+				"n":   nil,
+				"s":   "01234",
+			},
+		},
+		{
+			name: "巢狀結構與位置",
+			content: `{
+  "server": {"host": "localhost", "port": 8080},
+  "routes": [
+    {"path": "/api"},
+    {"path": "/健康", "methods": ["GET"]}
+  ]
+}`,
+			values: map[string]interface{}{
+				"server.port":          8080,
+				"routes[1].path":       "/健康",
+				"routes[1].methods[0]": "GET",
+			},
+			positions: map[string][2]int{
+				"server":            {2, 13},
+				"server.port":       {2, 43},
+				"routes":            {3, 13},
+				"routes[0].path":    {4, 14},
+				"routes[1].methods": {5, 32}, // 欄位以字元計算
+			},
+		},
+		{
+			name:    "空物件",
+			content: "{}",
+			values:  map[string]interface{}{"": map[string]interface{}{}},
+		},
+		{name: "最上層不是物件", content: `[1, 2]`, wantErr: "最上層必須是物件"},
+		{name: "多餘的內容", content: "{}\n{}", wantErr: "第 2 行"},
+		{name: "語法錯誤", content: "{\n  \"a\": 1,\n  \"b\" 2\n}", wantErr: "第 3 行"},
+		{name: "未結束的陣列", content: `{"a": [1, 2`, wantErr: "第 1 行"},
+		{name: "空內容", content: "", wantErr: "內容不完整"},
+		{name: "數字超出範圍", content: `{"a": 1e400}`, wantErr: "數字超出範圍"},
+	})
+}
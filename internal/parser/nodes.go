@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 非 YAML 格式的解析器以下列函式建立 yaml.Node 樹，
+// 再交由 YAMLParser.load 解碼，因此能共用路徑 API 與來源位置
+
+// newMappingNode 建立 mapping 節點
+func newMappingNode(line, column int) *yaml.Node {
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: line, Column: column}
+}
+
+// newSequenceNode 建立 sequence 節點
+func newSequenceNode(line, column int) *yaml.Node {
+	return &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Line: line, Column: column}
+}
+
+// newScalarNode 建立純量節點，tag 決定解碼後的型別
+func newScalarNode(value, tag string, style yaml.Style, line, column int) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: value, Tag: tag, Style: style, Line: line, Column: column}
+}
+
+// newStringNode 建立字串節點，quoted 表示原始檔案中有引號
+func newStringNode(value string, quoted bool, line, column int) *yaml.Node {
+	var style yaml.Style
+	if quoted {
+		style = yaml.DoubleQuotedStyle
+	}
+	return newScalarNode(value, "!!str", style, line, column)
+}
+
+// lookupKey 在 mapping 節點中尋找 key 對應的值節點
+func lookupKey(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setKey 設定 mapping 中的 key，已存在時取代原本的值
+func setKey(mapping *yaml.Node, key, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key.Value {
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content, key, value)
+}
+
+// ensureMapping 依序取得（必要時建立）巢狀的 mapping，如 ["server", "http"] -> server.http
+func ensureMapping(mapping *yaml.Node, keys []string, line, column int) (*yaml.Node, error) {
+	current := mapping
+	for i, key := range keys {
+		next := lookupKey(current, key)
+		if next == nil {
+			next = newMappingNode(line, column)
+			setKey(current, newStringNode(key, false, line, column), next)
+		}
+		if next.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("%s 已定義為值，不能再作為區段", strings.Join(keys[:i+1], "."))
+		}
+		current = next
+	}
+	return current, nil
+}
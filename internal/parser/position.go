@@ -0,0 +1,165 @@
+package parser
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// GetNode 根據路徑獲取 yaml.Node（不支援萬用字元）
+func (p *YAMLParser) GetNode(path string) (*yaml.Node, bool) {
+	return LookupNode(p.root, path)
+}
+
+// LookupNode 從文件的根節點依路徑尋找節點（不支援萬用字元）
+func LookupNode(root *yaml.Node, path string) (*yaml.Node, bool) {
+	if root == nil {
+		return nil, false
+	}
+
+	current := resolveAlias(root)
+	if path == "" {
+		return current, true
+	}
+
+	for _, part := range strings.Split(path, ".") {
+		if strings.Contains(part, "[") {
+			fieldName, index, err := parseArrayIndex(part)
+			if err != nil {
+				return nil, false
+			}
+
+			// 欄位名稱為空時表示直接索引目前的陣列（如: "[0]"）
+			if fieldName != "" {
+				next, ok := mappingValue(current, fieldName)
+				if !ok {
+					return nil, false
+				}
+				current = next
+			}
+
+			if current.Kind != yaml.SequenceNode || index < 0 || index >= len(current.Content) {
+				return nil, false
+			}
+			current = resolveAlias(current.Content[index])
+		} else {
+			next, ok := mappingValue(current, part)
+			if !ok {
+				return nil, false
+			}
+			current = next
+		}
+	}
+
+	return current, true
+}
+
+// GetPosition 根據路徑獲取來源位置（不支援萬用字元）
+func (p *YAMLParser) GetPosition(path string) (Position, bool) {
+	node, exists := p.GetNode(path)
+	if !exists {
+		return Position{}, false
+	}
+	return NodePosition(node), true
+}
+
+// LocatePath 獲取路徑的來源位置
+// 路徑不存在時（例如缺少必要欄位），退回到最近一個存在的上層路徑
+func (p *YAMLParser) LocatePath(path string) Position {
+	for {
+		if pos, exists := p.GetPosition(path); exists {
+			return pos
+		}
+		if path == "" {
+			return Position{}
+		}
+		path = parentPath(path)
+	}
+}
+
+// parentPath 取得上一層路徑，如: "routes[0].path" -> "routes[0]" -> "routes" -> ""
+func parentPath(path string) string {
+	if strings.HasSuffix(path, "]") {
+		if idx := strings.LastIndex(path, "["); idx != -1 {
+			return path[:idx]
+		}
+	}
+	if idx := strings.LastIndex(path, "."); idx != -1 {
+		return path[:idx]
+	}
+	return ""
+}
+
+// mappingValue 在 mapping 節點中尋找 key 對應的值節點
+func mappingValue(node *yaml.Node, key string) (*yaml.Node, bool) {
+	if node.Kind != yaml.MappingNode {
+		return nil, false
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return resolveAlias(node.Content[i+1]), true
+		}
+	}
+	return nil, false
+}
+
+// resolveAlias 展開 alias 節點（如: *default）
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+// NodePosition 計算節點的起訖位置
+func NodePosition(node *yaml.Node) Position {
+	endLine, endColumn := nodeEnd(node)
+	return Position{
+		Line:      node.Line,
+		Column:    node.Column,
+		EndLine:   endLine,
+		EndColumn: endColumn,
+	}
+}
+
+// nodeEnd 計算節點的結束位置
+// mapping 與 sequence 以最後一個子節點的結束位置為準
+func nodeEnd(node *yaml.Node) (int, int) {
+	switch node.Kind {
+	case yaml.MappingNode, yaml.SequenceNode:
+		if len(node.Content) == 0 {
+			// 空的 {} 或 []
+			return node.Line, node.Column + 2
+		}
+		endLine, endColumn := nodeEnd(node.Content[len(node.Content)-1])
+		if node.Style&yaml.FlowStyle != 0 {
+			// flow 風格需包含結尾的 } 或 ]
+			endColumn++
+		}
+		return endLine, endColumn
+	case yaml.ScalarNode:
+		return scalarEnd(node)
+	default:
+		return node.Line, node.Column
+	}
+}
+
+// scalarEnd 計算純量節點的結束位置
+func scalarEnd(node *yaml.Node) (int, int) {
+	switch {
+	case node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0:
+		// 區塊字串從指示符的下一行開始
+		lines := strings.Split(strings.TrimRight(node.Value, "\n"), "\n")
+		last := lines[len(lines)-1]
+		return node.Line + len(lines), node.Column + utf8.RuneCountInString(last)
+	case node.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0:
+		return node.Line, node.Column + utf8.RuneCountInString(node.Value) + 2
+	default:
+		length := utf8.RuneCountInString(node.Value)
+		if node.Tag == "!!null" && node.Value == "" {
+			length = 0
+		}
+		return node.Line, node.Column + length
+	}
+}
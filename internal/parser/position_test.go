@@ -0,0 +1,103 @@
+package parser
+
+import "testing"
+
+const positionYAML = `server:
+  host: "localhost"
+  port: 8080
+  name: 服務名稱
+routes:
+  - path: /api
+    methods: [GET, POST]
+  - path: '/health'
+description: |
+  first line
+  second line
+empty: {}
+`
+
+func TestGetPosition(t *testing.T) {
+	p := NewYAMLParser()
+	if err := p.ParseBytes([]byte(positionYAML)); err != nil {
+		t.Fatalf("ParseBytes: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want Position
+	}{
+		{"server.host", Position{2, 9, 2, 20}},
+		{"server.port", Position{3, 9, 3, 13}},
+		{"server.name", Position{4, 9, 4, 13}}, // 欄位以字元計算
+		{"server", Position{2, 3, 4, 13}},
+		{"routes[0].path", Position{6, 11, 6, 15}},
+		{"routes[0].methods", Position{7, 14, 7, 25}}, // flow 序列包含結尾的 ]
+		{"routes[0].methods[1]", Position{7, 20, 7, 24}},
+		{"routes[1].path", Position{8, 11, 8, 20}},
+		{"empty", Position{12, 8, 12, 10}},
+	}
+	for _, tt := range tests {
+		got, ok := p.GetPosition(tt.path)
+		if !ok {
+			t.Errorf("GetPosition(%q) 找不到路徑", tt.path)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("GetPosition(%q) = %+v, want %+v", tt.path, got, tt.want)
+		}
+	}
+
+	// 區塊字串從指示符的下一行開始，結束於最後一行
+	if got, _ := p.GetPosition("description"); got.Line != 9 || got.Column != 14 || got.EndLine != 11 {
+		t.Errorf("GetPosition(%q) = %+v, want 9:14 到第 11 行", "description", got)
+	}
+
+	for _, path := range []string{"server.missing", "routes[2]", "routes[0].path.x", "routes[x]"} {
+		if pos, ok := p.GetPosition(path); ok {
+			t.Errorf("GetPosition(%q) = %+v, want not found", path, pos)
+		}
+	}
+}
+
+func TestLocatePath(t *testing.T) {
+	p := NewYAMLParser()
+	if err := p.ParseBytes([]byte(positionYAML)); err != nil {
+		t.Fatalf("ParseBytes: %v", err)
+	}
+
+	tests := []struct {
+		path     string
+		wantLine int
+	}{
+		{"server.port", 3},
+		{"server.tls.enabled", 2},    // 退回到 server
+		{"routes[1].timeout", 8},     // 退回到 routes[1]
+		{"routes[5].path", 6},        // 退回到 routes
+		{"missing.deeply.nested", 1}, // 退回到根節點
+	}
+	for _, tt := range tests {
+		got := p.LocatePath(tt.path)
+		if got.Line != tt.wantLine {
+			t.Errorf("LocatePath(%q).Line = %d, want %d", tt.path, got.Line, tt.wantLine)
+		}
+	}
+
+	if pos := NewYAMLParser().LocatePath("a.b"); !pos.IsZero() {
+		t.Errorf("未解析的文件 LocatePath = %+v, want zero", pos)
+	}
+}
+
+func TestParentPath(t *testing.T) {
+	tests := map[string]string{
+		"routes[0].path": "routes[0]",
+		"routes[0]":      "routes",
+		"routes":         "",
+		"a.b.c":          "a.b",
+		"[0]":            "",
+	}
+	for path, want := range tests {
+		if got := parentPath(path); got != want {
+			t.Errorf("parentPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// suppressionPattern 匹配抑制註解，如:
+//
+//	# validator:ignore api-005 reason="legacy upstream"
+//	# validator:ignore-file api-012,api-013
+var suppressionPattern = regexp.MustCompile(`#\s*validator:(ignore-file|ignore)\b([^"#]*?)(?:\s+reason="([^"]*)")?\s*$`)
+
+// Suppression 配置檔中的抑制註解
+type Suppression struct {
+	RuleIDs   []string // 抑制的規則 ID，空表示所有規則
+	Reason    string   // 抑制原因
+	Line      int      // 註解所在行
+	StartLine int      // 作用範圍起始行
+	EndLine   int      // 作用範圍結束行
+	FileLevel bool     // 是否作用於整個檔案（validator:ignore-file）
+}
+
+// Matches 檢查抑制註解是否適用於指定規則與行號
+func (s *Suppression) Matches(ruleID string, line int) bool {
+	if !s.AppliesTo(ruleID) {
+		return false
+	}
+	if s.FileLevel {
+		return true
+	}
+	return line >= s.StartLine && line <= s.EndLine
+}
+
+// AppliesTo 檢查抑制註解是否包含指定規則
+func (s *Suppression) AppliesTo(ruleID string) bool {
+	if len(s.RuleIDs) == 0 {
+		return true
+	}
+	for _, id := range s.RuleIDs {
+		if id == ruleID {
+			return true
+		}
+	}
+	return false
+}
+
+// Suppressions 返回檔案中的所有抑制註解
+func (p *YAMLParser) Suppressions() []*Suppression {
+	return p.suppressions
+}
+
+// parseSuppressions 掃描原始內容中的抑制註解
+// 行尾註解作用於同一行的節點；獨立一行的註解作用於下一個非註解行的節點
+// 若該行為父層 key（如 routes:），則作用於整個子樹
+func parseSuppressions(content []byte, roots []*yaml.Node) []*Suppression {
+	lines := strings.Split(string(content), "\n")
+
+	var suppressions []*Suppression
+	for i, line := range lines {
+		matches := suppressionPattern.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+
+		s := &Suppression{
+			RuleIDs:   splitRuleIDs(matches[2]),
+			Reason:    matches[3],
+			Line:      i + 1,
+			FileLevel: matches[1] == "ignore-file",
+		}
+
+		if !s.FileLevel {
+			target := i + 1
+			// 獨立一行的註解，作用於下一個有內容的行
+			if strings.HasPrefix(strings.TrimSpace(line), "#") {
+				target = nextContentLine(lines, i+1)
+			}
+			s.StartLine = target
+			s.EndLine = target
+			for _, root := range roots {
+				if root == nil {
+					continue
+				}
+				if end := subtreeEnd(root, target); end > s.EndLine {
+					s.EndLine = end
+				}
+			}
+		}
+
+		suppressions = append(suppressions, s)
+	}
+
+	return suppressions
+}
+
+// splitRuleIDs 解析以空白或逗號分隔的規則 ID
+func splitRuleIDs(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+}
+
+// nextContentLine 找出 start（索引）之後第一個非空白、非註解行的行號
+func nextContentLine(lines []string, start int) int {
+	for i := start; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			return i + 1
+		}
+	}
+	return len(lines)
+}
+
+// subtreeEnd 找出從指定行開始的節點中，最後一個結束行
+// 用於讓父層 key 上的註解作用於整個子樹
+func subtreeEnd(node *yaml.Node, line int) int {
+	end := 0
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Line == line {
+				if valueEnd, _ := nodeEnd(value); valueEnd > end {
+					end = valueEnd
+				}
+			}
+			if sub := subtreeEnd(value, line); sub > end {
+				end = sub
+			}
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			if item.Line == line {
+				if itemEnd, _ := nodeEnd(item); itemEnd > end {
+					end = itemEnd
+				}
+			}
+			if sub := subtreeEnd(item, line); sub > end {
+				end = sub
+			}
+		}
+	}
+	return end
+}
@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSuppressions(t *testing.T) {
+	content := `# validator:ignore-file api-012,api-013 reason="legacy file"
+server:
+  port: 80 # validator:ignore api-005
+  # validator:ignore reason="whole block"
+  tls:
+    enabled: false
+    cert: ""
+  host: localhost
+routes: # validator:ignore api-001 api-002
+  - path: /a
+  - path: /b
+# 一般註解 validator 不會被當作抑制註解
+name: app
+`
+	p := NewYAMLParser()
+	if err := p.ParseBytes([]byte(content)); err != nil {
+		t.Fatalf("ParseBytes: %v", err)
+	}
+
+	want := []Suppression{
+		{RuleIDs: []string{"api-012", "api-013"}, Reason: "legacy file", Line: 1, FileLevel: true},
+		{RuleIDs: []string{"api-005"}, Line: 3, StartLine: 3, EndLine: 3},
+		{RuleIDs: []string{}, Reason: "whole block", Line: 4, StartLine: 5, EndLine: 7},
+		{RuleIDs: []string{"api-001", "api-002"}, Line: 9, StartLine: 9, EndLine: 11},
+	}
+	got := p.Suppressions()
+	if len(got) != len(want) {
+		t.Fatalf("Suppressions() = %d 個, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(*got[i], want[i]) {
+			t.Errorf("suppressions[%d] = %+v, want %+v", i, *got[i], want[i])
+		}
+	}
+}
+
+func TestSuppressionMatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		s      Suppression
+		ruleID string
+		line   int
+		want   bool
+	}{
+		{"範圍內", Suppression{RuleIDs: []string{"a"}, StartLine: 3, EndLine: 5}, "a", 4, true},
+		{"範圍外", Suppression{RuleIDs: []string{"a"}, StartLine: 3, EndLine: 5}, "a", 6, false},
+		{"其他規則", Suppression{RuleIDs: []string{"a"}, StartLine: 3, EndLine: 5}, "b", 4, false},
+		{"所有規則", Suppression{StartLine: 3, EndLine: 3}, "any", 3, true},
+		{"整個檔案", Suppression{RuleIDs: []string{"a"}, FileLevel: true}, "a", 100, true},
+		{"整個檔案的其他規則", Suppression{RuleIDs: []string{"a"}, FileLevel: true}, "b", 1, false},
+	}
+	for _, tt := range tests {
+		if got := tt.s.Matches(tt.ruleID, tt.line); got != tt.want {
+			t.Errorf("%s: Matches(%q, %d) = %v, want %v", tt.name, tt.ruleID, tt.line, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,555 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TOMLParser 處理 TOML 檔案解析
+// 支援 TOML 1.0 的常用語法：表格 [a.b]、表格陣列 [[a]]、點分 key、行內表格、
+// 多行陣列與各種字串、整數、浮點數、布林值；日期時間以字串保存
+type TOMLParser struct {
+	*YAMLParser
+}
+
+// NewTOMLParser 建立新的 TOML 解析器
+func NewTOMLParser() *TOMLParser {
+	return &TOMLParser{YAMLParser: NewYAMLParser()}
+}
+
+// ParseFile 解析 TOML 檔案
+func (p *TOMLParser) ParseFile(filePath string) error {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("讀取檔案失敗: %w", err)
+	}
+	return p.ParseBytes(content)
+}
+
+// ParseBytes 解析 TOML 內容
+func (p *TOMLParser) ParseBytes(content []byte) error {
+	root, err := decodeTOML(string(content))
+	if err != nil {
+		return fmt.Errorf("解析 TOML 失敗: %w", err)
+	}
+
+	roots := []*yaml.Node{root}
+	if err := p.load(roots); err != nil {
+		return err
+	}
+	p.suppressions = parseSuppressions(content, roots)
+	return nil
+}
+
+// tomlDateTimePattern 匹配 TOML 日期時間的開頭，如 1979-05-27 或 07:32:00
+var tomlDateTimePattern = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}|\d{2}:\d{2}:\d{2})`)
+
+// tomlDecoder 將 TOML 轉換為 yaml.Node 樹
+type tomlDecoder struct {
+	src    []rune
+	pos    int
+	line   int
+	column int
+
+	root    *yaml.Node
+	current *yaml.Node // 目前的表格
+}
+
+// decodeTOML 解析 TOML 內容為 mapping 節點
+func decodeTOML(content string) (*yaml.Node, error) {
+	d := &tomlDecoder{
+		src:    []rune(content),
+		line:   1,
+		column: 1,
+		root:   newMappingNode(1, 1),
+	}
+	d.current = d.root
+
+	for {
+		d.skipSpace(true)
+		if d.eof() {
+			return d.root, nil
+		}
+
+		var err error
+		if d.peek() == '[' {
+			err = d.parseTableHeader()
+		} else {
+			err = d.parseKeyValue(d.current)
+		}
+		if err == nil {
+			err = d.expectLineEnd()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// errorf 建立含行號的錯誤
+func (d *tomlDecoder) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("第 %d 行: %s", d.line, fmt.Sprintf(format, args...))
+}
+
+func (d *tomlDecoder) eof() bool {
+	return d.pos >= len(d.src)
+}
+
+func (d *tomlDecoder) peek() rune {
+	if d.eof() {
+		return 0
+	}
+	return d.src[d.pos]
+}
+
+func (d *tomlDecoder) hasPrefix(prefix string) bool {
+	return strings.HasPrefix(string(d.src[d.pos:min(len(d.src), d.pos+len(prefix))]), prefix)
+}
+
+// next 讀取下一個字元並更新行列
+func (d *tomlDecoder) next() rune {
+	r := d.src[d.pos]
+	d.pos++
+	if r == '\n' {
+		d.line++
+		d.column = 1
+	} else {
+		d.column++
+	}
+	return r
+}
+
+// skipSpace 跳過空白與註解，newlines 為 true 時也跳過換行
+func (d *tomlDecoder) skipSpace(newlines bool) {
+	for !d.eof() {
+		switch r := d.peek(); {
+		case r == ' ' || r == '\t' || r == '\r':
+			d.next()
+		case r == '\n' && newlines:
+			d.next()
+		case r == '#':
+			for !d.eof() && d.peek() != '\n' {
+				d.next()
+			}
+		default:
+			return
+		}
+	}
+}
+
+// expectLineEnd 確認該行之後只剩空白或註解
+func (d *tomlDecoder) expectLineEnd() error {
+	d.skipSpace(false)
+	if d.eof() {
+		return nil
+	}
+	if d.peek() != '\n' {
+		return d.errorf("預期換行，但遇到 %q", d.peek())
+	}
+	d.next()
+	return nil
+}
+
+// tomlKey 點分 key 的其中一段
+type tomlKey struct {
+	name   string
+	line   int
+	column int
+}
+
+// parseKey 解析 key，如 a、"a.b"、a.b.c
+func (d *tomlDecoder) parseKey() ([]tomlKey, error) {
+	var keys []tomlKey
+	for {
+		d.skipSpace(false)
+		key := tomlKey{line: d.line, column: d.column}
+		switch r := d.peek(); {
+		case r == '"' || r == '\'':
+			name, err := d.parseString()
+			if err != nil {
+				return nil, err
+			}
+			key.name = name
+		case isBareKeyRune(r):
+			start := d.pos
+			for !d.eof() && isBareKeyRune(d.peek()) {
+				d.next()
+			}
+			key.name = string(d.src[start:d.pos])
+		default:
+			return nil, d.errorf("無效的 key")
+		}
+		keys = append(keys, key)
+
+		d.skipSpace(false)
+		if d.peek() != '.' {
+			return keys, nil
+		}
+		d.next()
+	}
+}
+
+// isBareKeyRune 檢查字元是否可用於不加引號的 key
+func isBareKeyRune(r rune) bool {
+	return r == '_' || r == '-' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+// parseTableHeader 解析 [table] 或 [[array.of.tables]]
+func (d *tomlDecoder) parseTableHeader() error {
+	line, column := d.line, d.column
+	isArray := d.hasPrefix("[[")
+	d.next()
+	if isArray {
+		d.next()
+	}
+
+	keys, err := d.parseKey()
+	if err != nil {
+		return err
+	}
+	closing := "]"
+	if isArray {
+		closing = "]]"
+	}
+	if !d.hasPrefix(closing) {
+		return d.errorf("表格名稱缺少 %s", closing)
+	}
+	for range closing {
+		d.next()
+	}
+
+	parent, err := d.navigate(d.root, keys[:len(keys)-1], line, column)
+	if err != nil {
+		return err
+	}
+	last := keys[len(keys)-1]
+	existing := lookupKey(parent, last.name)
+
+	if isArray {
+		if existing == nil {
+			existing = newSequenceNode(line, column)
+			setKey(parent, newStringNode(last.name, false, last.line, last.column), existing)
+		} else if existing.Kind != yaml.SequenceNode {
+			return d.errorf("%s 已定義為表格，不能再作為表格陣列", last.name)
+		}
+		table := newMappingNode(line, column)
+		existing.Content = append(existing.Content, table)
+		d.current = table
+		return nil
+	}
+
+	if existing == nil {
+		existing = newMappingNode(line, column)
+		setKey(parent, newStringNode(last.name, false, last.line, last.column), existing)
+	} else if existing.Kind != yaml.MappingNode {
+		return d.errorf("%s 已定義為值，不能再作為表格", last.name)
+	}
+	d.current = existing
+	return nil
+}
+
+// navigate 依序取得（必要時建立）巢狀表格；遇到表格陣列時使用最後一個元素
+func (d *tomlDecoder) navigate(table *yaml.Node, keys []tomlKey, line, column int) (*yaml.Node, error) {
+	current := table
+	for _, key := range keys {
+		next := lookupKey(current, key.name)
+		if next == nil {
+			next = newMappingNode(line, column)
+			setKey(current, newStringNode(key.name, false, key.line, key.column), next)
+		}
+		if next.Kind == yaml.SequenceNode && len(next.Content) > 0 {
+			next = next.Content[len(next.Content)-1]
+		}
+		if next.Kind != yaml.MappingNode {
+			return nil, d.errorf("%s 已定義為值，不能再作為表格", key.name)
+		}
+		current = next
+	}
+	return current, nil
+}
+
+// parseKeyValue 解析 key = value 並加入表格
+func (d *tomlDecoder) parseKeyValue(table *yaml.Node) error {
+	keys, err := d.parseKey()
+	if err != nil {
+		return err
+	}
+	d.skipSpace(false)
+	if d.peek() != '=' {
+		return d.errorf("key %s 後缺少 =", keys[len(keys)-1].name)
+	}
+	d.next()
+	d.skipSpace(false)
+
+	value, err := d.parseValue()
+	if err != nil {
+		return err
+	}
+
+	parent, err := d.navigate(table, keys[:len(keys)-1], keys[0].line, keys[0].column)
+	if err != nil {
+		return err
+	}
+	last := keys[len(keys)-1]
+	if lookupKey(parent, last.name) != nil {
+		return d.errorf("key %s 重複定義", last.name)
+	}
+	setKey(parent, newStringNode(last.name, false, last.line, last.column), value)
+	return nil
+}
+
+// parseValue 解析值
+func (d *tomlDecoder) parseValue() (*yaml.Node, error) {
+	line, column := d.line, d.column
+	switch r := d.peek(); {
+	case r == '"' || r == '\'':
+		value, err := d.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return newStringNode(value, true, line, column), nil
+	case r == '[':
+		return d.parseArray()
+	case r == '{':
+		return d.parseInlineTable()
+	case r == 0:
+		return nil, d.errorf("缺少值")
+	default:
+		return d.parseBareValue()
+	}
+}
+
+// parseString 解析基本字串、字面字串與兩者的多行形式
+func (d *tomlDecoder) parseString() (string, error) {
+	quote := d.peek()
+	literal := quote == '\''
+	delimiter := strings.Repeat(string(quote), 3)
+
+	multiline := d.hasPrefix(delimiter)
+	if multiline {
+		for range delimiter {
+			d.next()
+		}
+		// 緊接在開頭分隔符後的換行會被忽略
+		if d.peek() == '\r' {
+			d.next()
+		}
+		if d.peek() == '\n' {
+			d.next()
+		}
+	} else {
+		d.next()
+	}
+
+	var sb strings.Builder
+	for {
+		if d.eof() {
+			return "", d.errorf("字串沒有結尾")
+		}
+		if multiline && d.hasPrefix(delimiter) {
+			for range delimiter {
+				d.next()
+			}
+			// 結尾可以多出一到兩個引號，屬於字串內容
+			for i := 0; i < 2 && d.peek() == quote; i++ {
+				sb.WriteRune(d.next())
+			}
+			return sb.String(), nil
+		}
+
+		r := d.peek()
+		switch {
+		case !multiline && r == quote:
+			d.next()
+			return sb.String(), nil
+		case !multiline && r == '\n':
+			return "", d.errorf("字串不可跨行")
+		case !literal && r == '\\':
+			d.next()
+			if err := d.parseEscape(&sb, multiline); err != nil {
+				return "", err
+			}
+		default:
+			sb.WriteRune(d.next())
+		}
+	}
+}
+
+// parseEscape 解析跳脫字元（反斜線之後的部分）
+func (d *tomlDecoder) parseEscape(sb *strings.Builder, multiline bool) error {
+	if d.eof() {
+		return d.errorf("字串沒有結尾")
+	}
+	r := d.next()
+	switch r {
+	case 'b':
+		sb.WriteRune('\b')
+	case 't':
+		sb.WriteRune('\t')
+	case 'n':
+		sb.WriteRune('\n')
+	case 'f':
+		sb.WriteRune('\f')
+	case 'r':
+		sb.WriteRune('\r')
+	case '"':
+		sb.WriteRune('"')
+	case '\\':
+		sb.WriteRune('\\')
+	case 'u', 'U':
+		size := 4
+		if r == 'U' {
+			size = 8
+		}
+		if d.pos+size > len(d.src) {
+			return d.errorf("無效的 unicode 跳脫字元")
+		}
+		code, err := strconv.ParseUint(string(d.src[d.pos:d.pos+size]), 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return d.errorf("無效的 unicode 跳脫字元")
+		}
+		for i := 0; i < size; i++ {
+			d.next()
+		}
+		sb.WriteRune(rune(code))
+	case ' ', '\t', '\r', '\n':
+		// 多行字串中行尾的反斜線會移除換行與之後的空白
+		if !multiline {
+			return d.errorf("無效的跳脫字元 \\%c", r)
+		}
+		for !d.eof() && strings.ContainsRune(" \t\r\n", d.peek()) {
+			d.next()
+		}
+	default:
+		return d.errorf("無效的跳脫字元 \\%c", r)
+	}
+	return nil
+}
+
+// parseArray 解析陣列，可跨行並包含註解與結尾逗號
+func (d *tomlDecoder) parseArray() (*yaml.Node, error) {
+	array := newSequenceNode(d.line, d.column)
+	array.Style = yaml.FlowStyle
+	d.next()
+
+	for {
+		d.skipSpace(true)
+		if d.peek() == ']' {
+			d.next()
+			return array, nil
+		}
+
+		value, err := d.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		array.Content = append(array.Content, value)
+
+		d.skipSpace(true)
+		switch d.peek() {
+		case ',':
+			d.next()
+		case ']':
+		default:
+			return nil, d.errorf("陣列元素之間缺少逗號")
+		}
+	}
+}
+
+// parseInlineTable 解析行內表格，如 { host = "db", port = 5432 }
+func (d *tomlDecoder) parseInlineTable() (*yaml.Node, error) {
+	table := newMappingNode(d.line, d.column)
+	table.Style = yaml.FlowStyle
+	d.next()
+
+	d.skipSpace(false)
+	if d.peek() == '}' {
+		d.next()
+		return table, nil
+	}
+
+	for {
+		if err := d.parseKeyValue(table); err != nil {
+			return nil, err
+		}
+		d.skipSpace(false)
+		switch d.peek() {
+		case ',':
+			d.next()
+			d.skipSpace(false)
+		case '}':
+			d.next()
+			return table, nil
+		default:
+			return nil, d.errorf("行內表格缺少逗號或 }")
+		}
+	}
+}
+
+// parseBareValue 解析整數、浮點數、布林值與日期時間
+func (d *tomlDecoder) parseBareValue() (*yaml.Node, error) {
+	line, column := d.line, d.column
+	start := d.pos
+	for !d.eof() && !strings.ContainsRune(" \t\r\n,]}#", d.peek()) {
+		d.next()
+	}
+	// 日期與時間之間可以用空白分隔，如 1979-05-27 07:32:00
+	if d.peek() == ' ' && d.pos+3 <= len(d.src) && tomlDateTimePattern.MatchString(string(d.src[start:d.pos])) &&
+		isDigit(d.src[d.pos+1]) && isDigit(d.src[d.pos+2]) {
+		d.next()
+		for !d.eof() && !strings.ContainsRune(" \t\r\n,]}#", d.peek()) {
+			d.next()
+		}
+	}
+	token := string(d.src[start:d.pos])
+
+	switch token {
+	case "true", "false":
+		return newScalarNode(token, "!!bool", 0, line, column), nil
+	case "inf", "+inf":
+		return newScalarNode(".inf", "!!float", 0, line, column), nil
+	case "-inf":
+		return newScalarNode("-.inf", "!!float", 0, line, column), nil
+	case "nan", "+nan", "-nan":
+		return newScalarNode(".nan", "!!float", 0, line, column), nil
+	}
+
+	if tomlDateTimePattern.MatchString(token) {
+		return newScalarNode(token, "!!str", 0, line, column), nil
+	}
+
+	number := strings.ReplaceAll(token, "_", "")
+	if hasLeadingZero(number) {
+		return nil, d.errorf("數字 %q 不可有前導零", token)
+	}
+	if i, err := strconv.ParseInt(number, 0, 64); err == nil {
+		return newScalarNode(strconv.FormatInt(i, 10), "!!int", 0, line, column), nil
+	}
+	if !strings.HasPrefix(strings.TrimLeft(number, "+-"), "0x") {
+		if f, err := strconv.ParseFloat(number, 64); err == nil {
+			value := strconv.FormatFloat(f, 'g', -1, 64)
+			if !strings.ContainsAny(value, ".e") {
+				value += ".0"
+			}
+			return newScalarNode(value, "!!float", 0, line, column), nil
+		}
+	}
+
+	return nil, d.errorf("無效的值 %q", token)
+}
+
+// hasLeadingZero 檢查十進位整數是否有不允許的前導零（如 0123）
+func hasLeadingZero(number string) bool {
+	digits := strings.TrimLeft(number, "+-")
+	return len(digits) > 1 && digits[0] == '0' && isDigit(rune(digits[1]))
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
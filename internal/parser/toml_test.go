@@ -0,0 +1,163 @@
+package parser
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTOMLParser(t *testing.T) {
+	runParseCases(t, FormatTOML, []parseCase{
+		{
+			name: "字串的引號與跳脫字元",
+			content: `basic = "tab\there \"q\" \u00e9 \U0001F600"
+literal = 'C:\Users\app'
+"quoted" = 1
+"a.b" = "key 含點號"
+`,
+			// 有引號的 key 含點號時不拆分，以根節點檢查
+			values: map[string]interface{}{
+				"": map[string]interface{}{
+					"basic":   "tab\there \"q\" é 😀",
+					"literal": `C:\Users\app`,
+					"quoted":  1,
+					"a.b":     "key 含點號",
+				},
+			},
+		},
+		{
+			name: "多行字串",
+			content: `basic = """
+first
+second"""
+trimmed = """\
+    one \
+    two"""
+literal = '''
+raw \n
+'''
+quotes = """a "quoted" word"""""
+`,
+			values: map[string]interface{}{
+				"basic":   "first\nsecond",
+				"trimmed": "one two",
+				"literal": "raw \\n\n",
+				"quotes":  `a "quoted" word""`,
+			},
+		},
+		{
+			name: "數字、布林值與日期時間",
+			content: `int = 1_000
+hex = 0xff
+oct = 0o755
+float = 6.5e-1
+whole = 3e2
+flag = true
+date = 1979-05-27
+datetime = 1979-05-27 07:32:00
+`,
+			values: map[string]interface{}{
+				"int":      1000,
+				"hex":      255,
+				"oct":      493,
+				"float":    0.65,
+				"whole":    300.0,
+				"flag":     true,
+				"date":     "1979-05-27",
+				"datetime": "1979-05-27 07:32:00",
+			},
+		},
+		{
+			name: "行內表格與陣列",
+			content: `db = { host = "localhost", port = 5432, pool = { max = 10 } }
+ports = [
+  80,  # http
+  443,
+]
+nested = [[1, 2], ["a"]]
+empty = {}
+`,
+			values: map[string]interface{}{
+				"db.host":     "localhost",
+				"db.pool.max": 10,
+				"ports":       []interface{}{80, 443},
+				"nested[1]":   []interface{}{"a"},
+				"empty":       map[string]interface{}{},
+			},
+			positions: map[string][2]int{
+				"db":          {1, 6},
+				"db.port":     {1, 35},
+				"db.pool.max": {1, 56},
+				"ports[1]":    {4, 3},
+			},
+		},
+		{
+			name: "表格與表格陣列",
+			content: `title = "app"
+
+[server]
+host = "0.0.0.0"
+
+[server.tls]
+enabled = true
+
+[[routes]]
+path = "/api"
+
+[[routes]]
+path = "/health"
+[routes.options]
+timeout = 5
+
+[[routes.middlewares]]
+name = "auth"
+`,
+			values: map[string]interface{}{
+				"title":                         "app",
+				"server.host":                   "0.0.0.0",
+				"server.tls.enabled":            true,
+				"routes[0].path":                "/api",
+				"routes[1].path":                "/health",
+				"routes[1].options.timeout":     5,
+				"routes[1].middlewares[0].name": "auth",
+			},
+			positions: map[string][2]int{
+				"server.host":        {4, 8},
+				"server.tls.enabled": {7, 11},
+				"routes[0].path":     {10, 8},
+				"routes[1].path":     {13, 8},
+			},
+		},
+		{
+			name:    "點分 key",
+			content: "server.port = 8080\nserver.host = \"x\"\n",
+			values:  map[string]interface{}{"server.port": 8080, "server.host": "x"},
+		},
+		{name: "重複的 key", content: "a = 1\na = 2\n", wantErr: "第 2 行: key a 重複定義"},
+		{name: "行內表格中重複的 key", content: "t = { a = 1, a = 2 }\n", wantErr: "key a 重複定義"},
+		{name: "表格陣列與表格衝突", content: "[a]\n[[a]]\n", wantErr: "a 已定義為表格"},
+		{name: "值與表格衝突", content: "a = 1\n[a]\n", wantErr: "a 已定義為值"},
+		{name: "字串沒有結尾", content: "a = \"abc\n", wantErr: "字串不可跨行"},
+		{name: "多行字串沒有結尾", content: "a = \"\"\"abc\n", wantErr: "字串沒有結尾"},
+		{name: "無效的跳脫字元", content: `a = "\q"`, wantErr: `無效的跳脫字元 \q`},
+		{name: "前導零", content: "a = 0123\n", wantErr: "不可有前導零"},
+		{name: "陣列缺少逗號", content: "a = [1 2]\n", wantErr: "陣列元素之間缺少逗號"},
+		{name: "同一行兩個 key", content: "a = 1 b = 2\n", wantErr: "預期換行"},
+		{name: "缺少值", content: "a =", wantErr: "缺少值"},
+	})
+}
+
+func TestTOMLSpecialFloats(t *testing.T) {
+	p := NewTOMLParser()
+	if err := p.ParseBytes([]byte("pos = inf\nneg = -inf\nnan = nan\n")); err != nil {
+		t.Fatalf("ParseBytes: %v", err)
+	}
+	if v, _ := p.GetNumber("pos"); !math.IsInf(v, 1) {
+		t.Errorf("GetNumber(pos) = %v, want +Inf", v)
+	}
+	if v, _ := p.GetNumber("neg"); !math.IsInf(v, -1) {
+		t.Errorf("GetNumber(neg) = %v, want -Inf", v)
+	}
+	if v, _ := p.GetNumber("nan"); !math.IsNaN(v) {
+		t.Errorf("GetNumber(nan) = %v, want NaN", v)
+	}
+}
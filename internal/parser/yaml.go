@@ -1,7 +1,10 @@
 package parser
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -10,8 +13,29 @@ import (
 )
 
 // YAMLParser 處理 YAML 檔案解析
+// 除了解碼後的資料外，也保留 yaml.Node 樹以便回報來源位置
+// 多文件（以 --- 分隔）的檔案會為每個文件各建立一個解析器
 type YAMLParser struct {
-	data map[string]interface{}
+	data  map[string]interface{}
+	root  *yaml.Node    // 文件的根節點（document 的內容節點）
+	docs  []*YAMLParser // 檔案中的所有文件
+	index int           // 文件在檔案中的序號（從 1 開始，空文件也計入）
+
+	suppressions []*Suppression // 檔案中的抑制註解
+}
+
+// Position 來源位置，行與欄皆從 1 開始
+// EndColumn 指向結束位置的下一欄
+type Position struct {
+	Line      int
+	Column    int
+	EndLine   int
+	EndColumn int
+}
+
+// IsZero 是否為空位置（無法定位）
+func (pos Position) IsZero() bool {
+	return pos.Line == 0
 }
 
 // NewYAMLParser 建立新的 YAML 解析器
@@ -22,19 +46,113 @@ func NewYAMLParser() *YAMLParser {
 }
 
 // ParseFile 解析 YAML 檔案
+// 檔案包含多個文件時，GetValue 等方法作用於第一個文件，其餘文件可透過 Documents 取得
 func (p *YAMLParser) ParseFile(filePath string) error {
 	content, err := os.ReadFile(filePath)
 	if err != nil {
 		return fmt.Errorf("讀取檔案失敗: %w", err)
 	}
+	return p.ParseBytes(content)
+}
+
+// ParseBytes 解析 YAML 內容
+func (p *YAMLParser) ParseBytes(content []byte) error {
+	roots, err := DecodeYAMLDocuments(content)
+	if err != nil {
+		return err
+	}
+
+	if err := p.load(roots); err != nil {
+		return err
+	}
+	p.suppressions = parseSuppressions(content, roots)
+	return nil
+}
+
+// DecodeYAMLDocuments 解碼 YAML 內容中每個文件的根節點
+// 空文件（如連續的 --- 或內容只有 null）的根節點為 nil，roots[i] 即檔案中的第 i+1 個文件
+func DecodeYAMLDocuments(content []byte) ([]*yaml.Node, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	var roots []*yaml.Node
+	for {
+		var doc yaml.Node
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("解析 YAML 失敗: %w", err)
+		}
+
+		if len(doc.Content) == 0 || isNullNode(doc.Content[0]) {
+			roots = append(roots, nil)
+			continue
+		}
+		roots = append(roots, doc.Content[0])
+	}
+	return roots, nil
+}
+
+// load 以節點樹建立文件，每個 root 為一個文件，nil 為空文件（不建立文件但保留序號）
+// 其他格式的解析器也會先轉換為節點樹，再共用相同的路徑 API 與位置資訊
+func (p *YAMLParser) load(roots []*yaml.Node) error {
+	p.docs = nil
+	for i, root := range roots {
+		if root == nil {
+			continue
+		}
+		docParser := NewYAMLParser()
+		docParser.root = root
+		docParser.index = i + 1
+		if err := docParser.root.Decode(&docParser.data); err != nil {
+			return fmt.Errorf("解析失敗 (第 %d 個文件): %w", i+1, err)
+		}
+		if docParser.data == nil {
+			docParser.data = make(map[string]interface{})
+		}
+		p.docs = append(p.docs, docParser)
+	}
 
-	if err := yaml.Unmarshal(content, &p.data); err != nil {
-		return fmt.Errorf("解析 YAML 失敗: %w", err)
+	// 空檔案視為單一空文件
+	if len(p.docs) == 0 {
+		p.data = make(map[string]interface{})
+		p.root = nil
+		p.index = 1
+		return nil
 	}
 
+	p.data = p.docs[0].data
+	p.root = p.docs[0].root
+	p.index = p.docs[0].index
 	return nil
 }
 
+// Documents 返回檔案中每個文件
+// 單一文件或尚未解析時只返回自己
+func (p *YAMLParser) Documents() []Document {
+	if len(p.docs) <= 1 {
+		return []Document{p}
+	}
+	docs := make([]Document, len(p.docs))
+	for i, doc := range p.docs {
+		docs[i] = doc
+	}
+	return docs
+}
+
+// DocumentIndex 返回文件在檔案中的序號（從 1 開始）
+// 空文件也計入序號，與編輯器中看到的文件位置一致
+func (p *YAMLParser) DocumentIndex() int {
+	if p.index == 0 {
+		return 1
+	}
+	return p.index
+}
+
+// isNullNode 檢查節點是否為 null 純量
+func isNullNode(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
+
 // GetValue 根據路徑獲取值
 // 路徑格式: "database.pool.maxConnections" 或 "routes[0].path" 或 "routes[*].middlewares[0]"
 // 支援萬用字元 [*] 表示所有陣列項目,當使用 [*] 時會返回多個結果的陣列
@@ -338,8 +456,9 @@ type DuplicateInfo struct {
 
 // PathInfo 路徑資訊，用於追蹤萬用字元展開後的實際路徑
 type PathInfo struct {
-	Path  string      // 實際路徑 (如: "routes[0].middlewares[1]")
-	Value interface{} // 該路徑的值
+	Path     string      // 實際路徑 (如: "routes[0].middlewares[1]")
+	Value    interface{} // 該路徑的值
+	Position Position    // 該路徑在來源檔案中的位置
 }
 
 // ExpandWildcardPath 展開包含萬用字元 [*] 的路徑
@@ -348,7 +467,8 @@ func (p *YAMLParser) ExpandWildcardPath(path string) []*PathInfo {
 	if !strings.Contains(path, "[*]") {
 		// 沒有萬用字元,直接返回
 		if value, exists := p.GetValue(path); exists {
-			return []*PathInfo{{Path: path, Value: value}}
+			pos, _ := p.GetPosition(path)
+			return []*PathInfo{{Path: path, Value: value, Position: pos}}
 		}
 		return nil
 	}
@@ -463,6 +583,11 @@ func (p *YAMLParser) ExpandWildcardPath(path string) []*PathInfo {
 		}
 	}
 
+	// 補上每個實際路徑的來源位置
+	for _, pathInfo := range paths {
+		pathInfo.Position, _ = p.GetPosition(pathInfo.Path)
+	}
+
 	return paths
 }
 
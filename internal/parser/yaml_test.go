@@ -0,0 +1,75 @@
+package parser
+
+import "testing"
+
+func TestYAMLDocumentIndex(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []int // 每個非空文件的序號
+	}{
+		{"單一文件", "a: 1\n", []int{1}},
+		{"多文件", "a: 1\n---\nb: 2\n", []int{1, 2}},
+		{"中間的空文件", "a: 1\n---\n---\nb: 2\n", []int{1, 3}},
+		{"null 文件", "a: 1\n---\nnull\n---\nb: 2\n", []int{1, 3}},
+		{"開頭的空文件", "---\n---\nb: 2\n", []int{2}},
+		{"結尾的 ---", "a: 1\n---\nb: 2\n---\n", []int{1, 2}},
+		{"空檔案", "", []int{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewYAMLParser()
+			if err := p.ParseBytes([]byte(tt.content)); err != nil {
+				t.Fatalf("ParseBytes: %v", err)
+			}
+			docs := p.Documents()
+			if len(docs) != len(tt.want) {
+				t.Fatalf("Documents() = %d 個, want %d", len(docs), len(tt.want))
+			}
+			for i, doc := range docs {
+				if got := doc.DocumentIndex(); got != tt.want[i] {
+					t.Errorf("docs[%d].DocumentIndex() = %d, want %d", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestYAMLDocuments(t *testing.T) {
+	p := NewYAMLParser()
+	if err := p.ParseBytes([]byte("name: first\n---\n---\nname: third\nport: 80\n")); err != nil {
+		t.Fatalf("ParseBytes: %v", err)
+	}
+
+	// 路徑 API 作用於第一個文件
+	if name, _ := p.GetString("name"); name != "first" {
+		t.Errorf("GetString(name) = %q, want first", name)
+	}
+
+	docs := p.Documents()
+	if name, _ := docs[1].GetString("name"); name != "third" {
+		t.Errorf("docs[1] name = %q, want third", name)
+	}
+	if pos, ok := docs[1].GetPosition("port"); !ok || pos.Line != 5 {
+		t.Errorf("docs[1] port 位置 = %+v, want 第 5 行", pos)
+	}
+}
+
+func TestDecodeYAMLDocuments(t *testing.T) {
+	roots, err := DecodeYAMLDocuments([]byte("a: 1\n---\n---\n~\n---\nb: 2\n"))
+	if err != nil {
+		t.Fatalf("DecodeYAMLDocuments: %v", err)
+	}
+	if len(roots) != 4 {
+		t.Fatalf("roots = %d, want 4", len(roots))
+	}
+	for i, wantNil := range []bool{false, true, true, false} {
+		if (roots[i] == nil) != wantNil {
+			t.Errorf("roots[%d] = %v, want nil = %v", i, roots[i], wantNil)
+		}
+	}
+
+	if _, err := DecodeYAMLDocuments([]byte("a: [1, 2\n")); err == nil {
+		t.Error("不合法的 YAML 應返回錯誤")
+	}
+}
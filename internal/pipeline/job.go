@@ -0,0 +1,133 @@
+package pipeline
+
+import (
+	"config-validator/internal/parser"
+	"config-validator/internal/product"
+	"config-validator/internal/rule"
+	"context"
+	"sync"
+)
+
+// RuleSource 依產品名稱取得規則（含繼承的規則）
+type RuleSource func(product string) ([]*rule.ValidationRule, error)
+
+// Job 單一配置檔的驗證工作
+type Job struct {
+	Path      string
+	MatchPath string
+	Format    string
+	Parser    parser.Document
+	Products  []string               // 檔案所屬的產品
+	Rules     []*rule.ValidationRule // 所屬產品的規則（多個產品時已合併）
+}
+
+// NewJob 建立檔案的驗證工作，同一個檔案可能屬於多個產品，合併各產品的規則
+func NewJob(file File, p parser.Document, detections []*product.Detection, rules RuleSource) (*Job, error) {
+	job := &Job{
+		Path:      file.Path,
+		MatchPath: file.MatchPath,
+		Format:    file.Format,
+		Parser:    p,
+	}
+	for _, detection := range detections {
+		job.Products = append(job.Products, detection.Product.Name)
+	}
+	if err := job.LoadRules(rules); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// LoadRules 重新取得檔案所屬產品的規則（產品規則重新載入後呼叫）
+func (j *Job) LoadRules(rules RuleSource) error {
+	var ruleSets [][]*rule.ValidationRule
+	for _, name := range j.Products {
+		productRules, err := rules(name)
+		if err != nil {
+			return err
+		}
+		ruleSets = append(ruleSets, productRules)
+	}
+	j.Rules = rule.MergeRules(ruleSets...)
+	return nil
+}
+
+// MatchedRules 返回適用於這個檔案的規則
+func (j *Job) MatchedRules() []*rule.ValidationRule {
+	return rule.MatchRules(j.Rules, j.MatchPath)
+}
+
+// UsesWorkspace 適用的規則中是否有跨檔案規則
+func (j *Job) UsesWorkspace() bool {
+	for _, r := range j.MatchedRules() {
+		if r.UsesWorkspace() {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate 以適用的規則驗證檔案，並套用配置檔中的抑制註解
+func (j *Job) Validate(workspace *rule.Workspace) []*rule.ValidationResult {
+	executor := rule.NewExecutor(j.Parser)
+	executor.SetWorkspace(workspace)
+	var results []*rule.ValidationResult
+	for _, r := range j.MatchedRules() {
+		results = append(results, executor.Execute(r, j.Path)...)
+	}
+	return rule.ApplySuppressions(j.Parser, j.Path, results)
+}
+
+// ValidateAll 平行驗證所有檔案，結果依 jobs 的順序返回
+// 所有檔案都應已加入 workspace，跨檔案規則才能看到完整的工作區；規則在此階段只會被讀取
+func ValidateAll(ctx context.Context, jobs []*Job, workspace *rule.Workspace, workers int) ([][]*rule.ValidationResult, error) {
+	fileResults := make([][]*rule.ValidationResult, len(jobs))
+	err := ForEach(ctx, workers, len(jobs), func(i int) {
+		fileResults[i] = jobs[i].Validate(workspace)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fileResults, nil
+}
+
+// ForEach 以最多 workers 個 goroutine 執行 fn(0) ~ fn(count-1)
+// ctx 取消後不再開始新的項目，等待執行中的項目結束後返回 ctx 的錯誤
+func ForEach(ctx context.Context, workers, count int, fn func(i int)) error {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > count {
+		workers = count
+	}
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				fn(i)
+			}
+		}()
+	}
+
+	var err error
+feed:
+	for i := 0; i < count; i++ {
+		select {
+		case indices <- i:
+		case <-ctx.Done():
+			err = ctx.Err()
+			break feed
+		}
+	}
+	close(indices)
+	wg.Wait()
+
+	if err == nil {
+		err = ctx.Err()
+	}
+	return err
+}
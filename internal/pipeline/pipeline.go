@@ -0,0 +1,133 @@
+// Package pipeline 驗證配置檔的共用流程：決定格式、解析、檢測產品並合併規則、平行驗證
+// CLI 的各個子命令與 pkg/validator 都經由這裡驗證，同一個配置檔在各種入口得到相同的結果
+package pipeline
+
+import (
+	"config-validator/internal/parser"
+	"config-validator/internal/product"
+	"config-validator/internal/rule"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// File 待處理的配置檔
+type File struct {
+	Path      string // 顯示於結果中的路徑
+	MatchPath string // 用於產品檢測與規則匹配的路徑（掃描目錄時相對於掃描的根目錄）
+	Format    string // 檔案格式
+	Content   []byte // 不是從磁碟讀取的內容（如標準輸入、HTTP 請求），為 nil 時讀取 Path
+}
+
+// FileFormat 決定配置檔格式，不支援時返回空字串
+// 路徑匹配的產品設定了 format 時優先使用，否則依副檔名判斷
+func FileFormat(detector *product.Detector, file File) string {
+	if prod := detector.DetectProduct(file.MatchPath); prod != nil && prod.Format != "" {
+		return prod.Format
+	}
+	return parser.FormatOf(file.Path)
+}
+
+// ScanFormat 決定掃描目錄時檔案的格式，不處理時返回空字串
+// 目錄中預設只處理 YAML；其他格式需有產品的 path_patterns 符合，避免目錄中無關的 JSON、.env 等檔案被當作配置檔
+func ScanFormat(detector *product.Detector, file File) string {
+	format := FileFormat(detector, file)
+	if format == parser.FormatYAML || detector.ClaimsPath(file.MatchPath) {
+		return format
+	}
+	return ""
+}
+
+// MatchPath 計算產品檢測與規則匹配使用的路徑：相對於目前目錄，以 / 分隔
+// 掃描目錄找到的檔案與直接指定的檔案使用相同的路徑，比對結果不受指定方式影響
+// 目前目錄之外的檔案使用清理後的原始路徑
+func MatchPath(path string) string {
+	path = filepath.Clean(path)
+	if filepath.IsAbs(path) {
+		if cwd, err := os.Getwd(); err == nil {
+			rel, err := filepath.Rel(cwd, path)
+			if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				path = rel
+			}
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+// Parse 依格式解析配置檔
+func (f File) Parse() (parser.Document, error) {
+	p, err := parser.NewParser(f.Format)
+	if err != nil {
+		return nil, err
+	}
+	if f.Content != nil {
+		err = p.ParseBytes(f.Content)
+	} else {
+		err = p.ParseFile(f.Path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ParseError 可以識別產品的配置檔解析失敗
+type ParseError struct {
+	Path string
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("解析檔案 %s 失敗: %v", e.Path, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Prepare 平行解析所有配置檔、檢測產品並建立驗證工作，無法識別產品的檔案列於 skipped
+// 解析失敗的檔案只以路徑檢測產品：無法識別時略過，否則返回 *ParseError
+// explain 不為 nil 時依檔案順序以每個檔案的檢測結果呼叫（如 --explain-detection）
+func Prepare(ctx context.Context, detector *product.Detector, files []File, rules RuleSource, workers int,
+	explain func(file File, detections []*product.Detection)) (jobs []*Job, skipped []File, err error) {
+	parsers := make([]parser.Document, len(files))
+	parseErrs := make([]error, len(files))
+	err = ForEach(ctx, workers, len(files), func(i int) {
+		parsers[i], parseErrs[i] = files[i].Parse()
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i, file := range files {
+		detections := detector.Detect(file.MatchPath, parsers[i])
+		if explain != nil {
+			explain(file, detections)
+		}
+		if len(detections) == 0 {
+			skipped = append(skipped, file)
+			continue
+		}
+		if parseErrs[i] != nil {
+			return nil, nil, &ParseError{Path: file.Path, Err: parseErrs[i]}
+		}
+
+		job, err := NewJob(file, parsers[i], detections, rules)
+		if err != nil {
+			return nil, nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, skipped, nil
+}
+
+// NewWorkspace 以所有驗證工作的配置檔建立跨檔案規則的工作區
+func NewWorkspace(jobs []*Job) *rule.Workspace {
+	workspace := rule.NewWorkspace()
+	for _, job := range jobs {
+		workspace.Add(job.Path, job.MatchPath, job.Parser)
+	}
+	return workspace
+}
@@ -0,0 +1,236 @@
+package pipeline
+
+import (
+	"config-validator/internal/parser"
+	"config-validator/internal/product"
+	"config-validator/internal/rule"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// newTestDetector 建立測試用的產品：app（YAML 與 JSON）、env（.env，以 format 指定格式）
+func newTestDetector(t *testing.T) *product.Detector {
+	t.Helper()
+	detector, err := product.NewDetectorFromConfig(product.ProductsConfig{Products: []product.ProductConfig{
+		{Name: "app", RulesDir: "rules/app", PathPatterns: []string{"**/app*.yaml", "**/app*.json"}},
+		{Name: "env", RulesDir: "rules/env", PathPatterns: []string{"**/settings"}, Format: parser.FormatEnv},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return detector
+}
+
+// testRules 每個產品一條 required_field 規則
+func testRules(name string) ([]*rule.ValidationRule, error) {
+	if name == "broken" {
+		return nil, errors.New("載入失敗")
+	}
+	return []*rule.ValidationRule{{
+		ID:       name + "-001",
+		Name:     "name 必填",
+		Enabled:  true,
+		Severity: rule.SeverityError,
+		Targets:  rule.Targets{FilePatterns: []string{"**/*"}},
+		Rule:     rule.Rule{Type: rule.RuleTypeRequiredField, RawRule: map[string]interface{}{"path": "name", "message": "缺少 name"}},
+		Product:  name,
+	}}, nil
+}
+
+func TestFileFormat(t *testing.T) {
+	detector := newTestDetector(t)
+	tests := []struct {
+		path       string
+		format     string
+		scanFormat string
+	}{
+		{"configs/app.yaml", parser.FormatYAML, parser.FormatYAML},
+		{"configs/other.yaml", parser.FormatYAML, parser.FormatYAML}, // 目錄中的 YAML 一律處理
+		{"configs/app.json", parser.FormatJSON, parser.FormatJSON},
+		{"configs/package.json", parser.FormatJSON, ""}, // 沒有產品的 path_patterns 符合時不掃描
+		{"configs/settings", parser.FormatEnv, parser.FormatEnv},
+		{"configs/README.md", "", ""},
+	}
+	for _, tt := range tests {
+		file := File{Path: tt.path, MatchPath: tt.path}
+		if got := FileFormat(detector, file); got != tt.format {
+			t.Errorf("FileFormat(%q) = %q, want %q", tt.path, got, tt.format)
+		}
+		if got := ScanFormat(detector, file); got != tt.scanFormat {
+			t.Errorf("ScanFormat(%q) = %q, want %q", tt.path, got, tt.scanFormat)
+		}
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	outside := filepath.Join(filepath.Dir(cwd), "other", "app.yaml")
+	tests := []struct {
+		path string
+		want string
+	}{
+		// 掃描目錄找到的檔案與直接指定的檔案使用相同的路徑
+		{filepath.Join("services", "api", "service.yaml"), "services/api/service.yaml"},
+		{"./services/api/../api/service.yaml", "services/api/service.yaml"},
+		{filepath.Join(cwd, "services", "api", "service.yaml"), "services/api/service.yaml"},
+		// 目前目錄之外的檔案使用清理後的原始路徑
+		{outside, filepath.ToSlash(outside)},
+		{filepath.Join("..", "other", "app.yaml"), "../other/app.yaml"},
+	}
+	for _, tt := range tests {
+		if got := MatchPath(tt.path); got != tt.want {
+			t.Errorf("MatchPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestFileParse(t *testing.T) {
+	// Content 不為 nil 時不讀取磁碟（包含空的內容）
+	file := File{Path: "does-not-exist.yaml", Format: parser.FormatYAML, Content: []byte{}}
+	if _, err := file.Parse(); err != nil {
+		t.Errorf("Parse(空內容) = %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	if err := os.WriteFile(path, []byte("name: app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := File{Path: path, Format: parser.FormatYAML}.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := p.GetString("name"); value != "app" {
+		t.Errorf("GetString(name) = %q, want app", value)
+	}
+
+	if _, err := (File{Path: "a.xml", Format: "xml", Content: []byte("<a/>")}).Parse(); err == nil {
+		t.Error("Parse(不支援的格式) 應返回錯誤")
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	detector := newTestDetector(t)
+	files := []File{
+		{Path: "app.yaml", MatchPath: "app.yaml", Format: parser.FormatYAML, Content: []byte("port: 1\n")},
+		{Path: "other.yaml", MatchPath: "other.yaml", Format: parser.FormatYAML, Content: []byte("a: 1\n")},
+		{Path: "broken.yaml", MatchPath: "broken.yaml", Format: parser.FormatYAML, Content: []byte("a: [1\n")},
+		{Path: "settings", MatchPath: "settings", Format: parser.FormatEnv, Content: []byte("NAME=x\n")},
+	}
+
+	var explained []string
+	jobs, skipped, err := Prepare(context.Background(), detector, files, testRules, 2, func(file File, detections []*product.Detection) {
+		explained = append(explained, fmt.Sprintf("%s:%d", file.Path, len(detections)))
+	})
+	if err != nil {
+		t.Fatalf("Prepare() error: %v", err)
+	}
+
+	// 無法識別產品的檔案略過，無法解析但路徑沒有符合任何產品的檔案也略過
+	if len(jobs) != 2 || jobs[0].Path != "app.yaml" || jobs[1].Path != "settings" {
+		t.Fatalf("jobs = %v, want [app.yaml settings]", jobs)
+	}
+	if len(skipped) != 2 || skipped[0].Path != "other.yaml" || skipped[1].Path != "broken.yaml" {
+		t.Errorf("skipped = %v, want [other.yaml broken.yaml]", skipped)
+	}
+	if got := strings.Join(explained, ","); got != "app.yaml:1,other.yaml:0,broken.yaml:0,settings:1" {
+		t.Errorf("explain = %s", got)
+	}
+	if jobs[0].Products[0] != "app" || len(jobs[0].Rules) != 1 || jobs[0].Rules[0].ID != "app-001" {
+		t.Errorf("jobs[0] = %+v", jobs[0])
+	}
+
+	// 結果依 jobs 的順序返回，跨檔案規則的工作區包含所有工作的檔案
+	workspace := NewWorkspace(jobs)
+	if len(workspace.Files()) != 2 {
+		t.Errorf("workspace = %v, want 2 個檔案", workspace.Files())
+	}
+	results, err := ValidateAll(context.Background(), jobs, workspace, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results[0]) != 1 || results[0][0].RuleID != "app-001" || results[0][0].File != "app.yaml" {
+		t.Errorf("results[0] = %v, want [app-001]", results[0])
+	}
+	if len(results[1]) != 1 || results[1][0].RuleID != "env-001" {
+		t.Errorf("results[1] = %v, want [env-001]", results[1])
+	}
+}
+
+func TestPrepareErrors(t *testing.T) {
+	detector := newTestDetector(t)
+
+	// 可以識別產品的檔案無法解析時返回 ParseError
+	files := []File{{Path: "app.yaml", MatchPath: "app.yaml", Format: parser.FormatYAML, Content: []byte("a: [1\n")}}
+	_, _, err := Prepare(context.Background(), detector, files, testRules, 1, nil)
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Path != "app.yaml" || !strings.Contains(err.Error(), "解析檔案 app.yaml 失敗") {
+		t.Errorf("Prepare() = %v, want ParseError", err)
+	}
+
+	// 規則載入失敗
+	brokenRules := func(string) ([]*rule.ValidationRule, error) { return testRules("broken") }
+	files = []File{{Path: "app.yaml", MatchPath: "app.yaml", Format: parser.FormatYAML, Content: []byte("a: 1\n")}}
+	if _, _, err := Prepare(context.Background(), detector, files, brokenRules, 1, nil); err == nil || errors.As(err, &parseErr) {
+		t.Errorf("Prepare() = %v, want 載入規則的錯誤", err)
+	}
+
+	// 已取消的 ctx
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := Prepare(ctx, detector, files, testRules, 1, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Prepare(已取消) = %v, want context.Canceled", err)
+	}
+}
+
+func TestJobLoadRules(t *testing.T) {
+	// 多個產品的規則合併，相同 ID 以先出現的產品為準
+	job := &Job{Products: []string{"app", "env"}, MatchPath: "app.yaml"}
+	if err := job.LoadRules(testRules); err != nil {
+		t.Fatal(err)
+	}
+	if len(job.Rules) != 2 || job.Rules[0].ID != "app-001" || job.Rules[1].ID != "env-001" {
+		t.Errorf("Rules = %v", job.Rules)
+	}
+	if job.UsesWorkspace() {
+		t.Error("UsesWorkspace() = true, want false")
+	}
+	if len(job.MatchedRules()) != 2 {
+		t.Errorf("MatchedRules() = %d, want 2", len(job.MatchedRules()))
+	}
+}
+
+func TestForEach(t *testing.T) {
+	var count int32
+	if err := ForEach(context.Background(), 4, 100, func(int) { atomic.AddInt32(&count, 1) }); err != nil || count != 100 {
+		t.Errorf("ForEach() = %v, count = %d, want 100", err, count)
+	}
+
+	// workers 小於 1 時使用一個 goroutine；沒有項目時直接返回
+	if err := ForEach(context.Background(), 0, 0, func(int) { t.Error("不應執行") }); err != nil {
+		t.Errorf("ForEach(0) = %v", err)
+	}
+
+	// 取消後不再開始新的項目，並返回 ctx 的錯誤
+	ctx, cancel := context.WithCancel(context.Background())
+	count = 0
+	err := ForEach(ctx, 1, 100, func(i int) {
+		if atomic.AddInt32(&count, 1) == 3 {
+			cancel()
+		}
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ForEach() = %v, want context.Canceled", err)
+	}
+	if count >= 100 {
+		t.Errorf("取消後仍執行了 %d 個項目", count)
+	}
+}
@@ -0,0 +1,107 @@
+package product
+
+import (
+	"config-validator/internal/parser"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// bracketKeyPattern 匹配 JSONPath 的括號 key，如 ['kind']
+var bracketKeyPattern = regexp.MustCompile(`\[['"]([^'"]+)['"]\]`)
+
+// mode 返回內容匹配模式，未指定時為 fallback
+func (c *ContentMatch) mode() string {
+	if c.Mode == "" {
+		return ContentMatchFallback
+	}
+	return c.Mode
+}
+
+// validate 檢查內容匹配條件
+func (c *ContentMatch) validate() error {
+	switch c.mode() {
+	case ContentMatchFallback, ContentMatchCombine:
+	default:
+		return fmt.Errorf("content_match.mode 必須是 %s 或 %s", ContentMatchFallback, ContentMatchCombine)
+	}
+	if len(c.RequiredKeys) == 0 && len(c.Equals) == 0 && len(c.Exists) == 0 {
+		return fmt.Errorf("content_match 至少需要 required_keys、equals 或 exists 其中之一")
+	}
+	for _, jsonPath := range c.Exists {
+		if _, err := toParserPath(jsonPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// match 檢查檔案內容是否符合條件，符合時返回說明
+// 多文件檔案中任一文件符合即可
+func (c *ContentMatch) match(p parser.Document) (bool, string) {
+	if p == nil {
+		return false, ""
+	}
+	for _, doc := range p.Documents() {
+		if ok, detail := c.matchDocument(doc); ok {
+			return true, detail
+		}
+	}
+	return false, ""
+}
+
+// matchDocument 檢查單一文件是否符合所有條件
+func (c *ContentMatch) matchDocument(doc parser.Document) (bool, string) {
+	var details []string
+
+	for _, key := range c.RequiredKeys {
+		if !doc.HasField(key) {
+			return false, ""
+		}
+		details = append(details, fmt.Sprintf("key %q 存在", key))
+	}
+
+	// 依路徑排序，讓說明的順序穩定
+	paths := make([]string, 0, len(c.Equals))
+	for path := range c.Equals {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		expected := fmt.Sprintf("%v", c.Equals[path])
+		value, exists := doc.GetValue(path)
+		if !exists || fmt.Sprintf("%v", value) != expected {
+			return false, ""
+		}
+		details = append(details, fmt.Sprintf("%s = %q", path, expected))
+	}
+
+	for _, jsonPath := range c.Exists {
+		path, err := toParserPath(jsonPath)
+		if err != nil || len(doc.ExpandWildcardPath(path)) == 0 {
+			return false, ""
+		}
+		details = append(details, fmt.Sprintf("%s 存在", jsonPath))
+	}
+
+	return true, strings.Join(details, "、")
+}
+
+// toParserPath 將 JSONPath 轉換為解析器使用的路徑
+// 支援 $.a.b、$.a[*].b、$.a[0] 與 $['a'] 形式，如 $.apiconfig.routes[*].path -> apiconfig.routes[*].path
+func toParserPath(jsonPath string) (string, error) {
+	if !strings.HasPrefix(jsonPath, "$") {
+		return "", fmt.Errorf("JSONPath %q 必須以 $ 開頭", jsonPath)
+	}
+	if strings.Contains(jsonPath, "..") {
+		return "", fmt.Errorf("JSONPath %q 不支援遞迴搜尋 (..)", jsonPath)
+	}
+
+	path := bracketKeyPattern.ReplaceAllString(jsonPath[1:], ".$1")
+	path = strings.TrimPrefix(path, ".")
+	if path == "" {
+		return "", fmt.Errorf("JSONPath %q 必須指定欄位", jsonPath)
+	}
+	return path, nil
+}
@@ -0,0 +1,143 @@
+package product
+
+import (
+	"config-validator/internal/parser"
+	"testing"
+)
+
+func parseYAML(t *testing.T, content string) parser.Document {
+	t.Helper()
+	p := parser.NewYAMLParser()
+	if err := p.ParseBytes([]byte(content)); err != nil {
+		t.Fatalf("ParseBytes: %v", err)
+	}
+	return p
+}
+
+func TestContentMatch(t *testing.T) {
+	doc := parseYAML(t, `kind: ApiGateway
+version: 2
+apiconfig:
+  routes:
+    - path: /a
+`)
+	tests := []struct {
+		name   string
+		cm     ContentMatch
+		want   bool
+		detail string
+	}{
+		{"required_keys", ContentMatch{RequiredKeys: []string{"kind", "apiconfig"}}, true, `key "kind" 存在、key "apiconfig" 存在`},
+		{"缺少 key", ContentMatch{RequiredKeys: []string{"database"}}, false, ""},
+		{"equals", ContentMatch{Equals: map[string]interface{}{"kind": "ApiGateway", "version": 2}}, true, `kind = "ApiGateway"、version = "2"`},
+		{"equals 不符", ContentMatch{Equals: map[string]interface{}{"kind": "Database"}}, false, ""},
+		{"exists", ContentMatch{Exists: []string{"$.apiconfig.routes[*].path"}}, true, "$.apiconfig.routes[*].path 存在"},
+		{"exists 括號 key", ContentMatch{Exists: []string{"$['apiconfig']['routes'][0]"}}, true, "$['apiconfig']['routes'][0] 存在"},
+		{"exists 不存在", ContentMatch{Exists: []string{"$.apiconfig.routes[*].method"}}, false, ""},
+		{"所有條件都必須成立", ContentMatch{RequiredKeys: []string{"kind"}, Exists: []string{"$.database"}}, false, ""},
+	}
+	for _, tt := range tests {
+		got, detail := tt.cm.match(doc)
+		if got != tt.want || detail != tt.detail {
+			t.Errorf("%s: match() = %v, %q, want %v, %q", tt.name, got, detail, tt.want, tt.detail)
+		}
+	}
+
+	if ok, _ := (&ContentMatch{RequiredKeys: []string{"kind"}}).match(nil); ok {
+		t.Error("沒有內容時不應符合")
+	}
+}
+
+func TestContentMatchMultiDocument(t *testing.T) {
+	doc := parseYAML(t, "kind: Service\n---\nkind: ApiGateway\n")
+	cm := ContentMatch{Equals: map[string]interface{}{"kind": "ApiGateway"}}
+	if ok, _ := cm.match(doc); !ok {
+		t.Error("任一文件符合即可")
+	}
+}
+
+func TestContentMatchValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		cm    ContentMatch
+		valid bool
+	}{
+		{"fallback", ContentMatch{RequiredKeys: []string{"a"}}, true},
+		{"combine", ContentMatch{Mode: ContentMatchCombine, Exists: []string{"$.a"}}, true},
+		{"未知模式", ContentMatch{Mode: "other", RequiredKeys: []string{"a"}}, false},
+		{"沒有條件", ContentMatch{}, false},
+		{"不以 $ 開頭", ContentMatch{Exists: []string{"a.b"}}, false},
+		{"遞迴搜尋", ContentMatch{Exists: []string{"$..name"}}, false},
+		{"只有 $", ContentMatch{Exists: []string{"$"}}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.cm.validate(); (err == nil) != tt.valid {
+			t.Errorf("%s: validate() = %v, want valid = %v", tt.name, err, tt.valid)
+		}
+	}
+}
+
+func TestToParserPath(t *testing.T) {
+	tests := map[string]string{
+		"$.a.b":                   "a.b",
+		"$.a[*].b":                "a[*].b",
+		"$.a[0]":                  "a[0]",
+		"$['a']['b']":             "a.b",
+		`$["a"].b`:                "a.b",
+		"$.apiconfig.routes[*].x": "apiconfig.routes[*].x",
+	}
+	for jsonPath, want := range tests {
+		got, err := toParserPath(jsonPath)
+		if err != nil || got != want {
+			t.Errorf("toParserPath(%q) = %q, %v, want %q", jsonPath, got, err, want)
+		}
+	}
+}
+
+func TestDetectByContent(t *testing.T) {
+	d, err := NewDetectorFromBytes([]byte(`
+products:
+  - name: api
+    rules_dir: rules/api
+    path_patterns: ["**/api/**"]
+  - name: gateway
+    rules_dir: rules/gateway
+    content_match:
+      equals: {kind: ApiGateway}
+  - name: strict
+    rules_dir: rules/strict
+    path_patterns: ["**/strict/**"]
+    content_match:
+      mode: combine
+      required_keys: [strict]
+`))
+	if err != nil {
+		t.Fatalf("NewDetectorFromBytes: %v", err)
+	}
+
+	gateway := parseYAML(t, "kind: ApiGateway\n")
+	strict := parseYAML(t, "strict: true\n")
+	tests := []struct {
+		name string
+		path string
+		doc  parser.Document
+		want string
+	}{
+		{"路徑優先", "services/api/x.yaml", gateway, "api"},
+		{"路徑不符時以內容判斷", "other/x.yaml", gateway, "gateway"},
+		{"都不符", "other/x.yaml", strict, ""},
+		{"combine 路徑與內容都符合", "a/strict/x.yaml", strict, "strict"},
+		{"combine 內容不符時退回內容判斷", "a/strict/x.yaml", gateway, "gateway"},
+		{"combine 只有路徑時不符", "a/strict/x.yaml", nil, ""},
+	}
+	for _, tt := range tests {
+		detections := d.Detect(tt.path, tt.doc)
+		got := ""
+		if len(detections) > 0 {
+			got = detections[0].Product.Name
+		}
+		if got != tt.want {
+			t.Errorf("%s: Detect(%q) = %q, want %q", tt.name, tt.path, got, tt.want)
+		}
+	}
+}
@@ -1,16 +1,18 @@
 package product
 
 import (
+	"config-validator/internal/glob"
+	"config-validator/internal/parser"
 	"fmt"
 	"os"
-	"path/filepath"
 
 	"gopkg.in/yaml.v3"
 )
 
 // Detector 產品檢測器
 type Detector struct {
-	products []ProductConfig
+	products     []ProductConfig
+	multiProduct bool // 所有匹配的產品都套用
 }
 
 // NewDetector 建立新的產品檢測器
@@ -19,77 +21,150 @@ func NewDetector(configPath string) (*Detector, error) {
 	if err != nil {
 		return nil, fmt.Errorf("讀取產品配置失敗: %w", err)
 	}
+	return NewDetectorFromBytes(content)
+}
 
+// NewDetectorFromBytes 從 products.yaml 的內容建立產品檢測器
+func NewDetectorFromBytes(content []byte) (*Detector, error) {
 	var config ProductsConfig
 	if err := yaml.Unmarshal(content, &config); err != nil {
 		return nil, fmt.Errorf("解析產品配置失敗: %w", err)
 	}
-
-	return &Detector{
-		products: config.Products,
-	}, nil
+	return NewDetectorFromConfig(config)
 }
 
-// DetectProduct 根據檔案路徑檢測產品類型
-func (d *Detector) DetectProduct(filePath string) *ProductConfig {
-	// 標準化路徑分隔符
-	normalizedPath := filepath.ToSlash(filePath)
-	fileName := filepath.Base(filePath)
-
-	// 遍歷所有產品配置
-	for _, product := range d.products {
+// NewDetectorFromConfig 從產品配置建立產品檢測器
+func NewDetectorFromConfig(config ProductsConfig) (*Detector, error) {
+	for _, product := range config.Products {
 		for _, pattern := range product.PathPatterns {
-			// 檢查路徑是否匹配
-			if matchPattern(normalizedPath, pattern) || matchPattern(fileName, pattern) {
-				return &product
+			if err := glob.Validate(pattern); err != nil {
+				return nil, fmt.Errorf("產品 %s 的 path_patterns 錯誤: %w", product.Name, err)
+			}
+		}
+		if product.ContentMatch != nil {
+			if err := product.ContentMatch.validate(); err != nil {
+				return nil, fmt.Errorf("產品 %s 的 %w", product.Name, err)
 			}
 		}
+		if product.Format != "" && !parser.IsFormat(product.Format) {
+			return nil, fmt.Errorf("產品 %s 的 format 不支援: %s", product.Name, product.Format)
+		}
+		if product.RulesDir == "" && len(product.Extends) == 0 && len(product.SharedRulesDirs) == 0 {
+			return nil, fmt.Errorf("產品 %s 必須設定 rules_dir、extends 或 shared_rules_dirs", product.Name)
+		}
 	}
 
-	return nil
+	if err := validateExtends(config.Products); err != nil {
+		return nil, err
+	}
+
+	// 複製一份，呼叫端之後修改設定不影響檢測器
+	products := make([]ProductConfig, len(config.Products))
+	for i, product := range config.Products {
+		products[i] = product.Clone()
+	}
+
+	return &Detector{
+		products:     products,
+		multiProduct: config.MultiProduct,
+	}, nil
 }
 
-// matchPattern 簡單的模式匹配
-// 支援 **/ 和 * 通配符
-func matchPattern(path, pattern string) bool {
-	// 處理 **/ 通配符（匹配任意層級目錄）
-	if len(pattern) > 3 && pattern[:3] == "**/" {
-		// 移除 **/ 前綴，檢查後續部分
-		suffix := pattern[3:]
-		matched, _ := filepath.Match(suffix, filepath.Base(path))
-		if matched {
-			return true
-		}
-		// 也檢查完整路徑
-		if containsPattern(path, suffix) {
+// Products 返回所有產品配置
+func (d *Detector) Products() []ProductConfig {
+	return d.products
+}
+
+// Detection 產品檢測結果
+type Detection struct {
+	Product *ProductConfig // 檢測到的產品
+	Reason  string         // 檢測依據，供 --explain-detection 輸出
+}
+
+// NoMatchReason 沒有任何產品匹配時的說明
+const NoMatchReason = "沒有產品的 path_patterns 或 content_match 符合"
+
+// DetectProduct 根據檔案路徑檢測產品類型（不檢查內容）
+// filePath 應為相對於目前目錄的路徑（見 pipeline.MatchPath）
+func (d *Detector) DetectProduct(filePath string) *ProductConfig {
+	detections := d.Detect(filePath, nil)
+	if len(detections) == 0 {
+		return nil
+	}
+	return detections[0].Product
+}
+
+// ClaimsPath 檢查是否有產品的 path_patterns 符合檔案路徑（不檢查內容）
+// 掃描目錄時以此決定是否處理 YAML 以外格式的檔案
+func (d *Detector) ClaimsPath(filePath string) bool {
+	for i := range d.products {
+		if _, matched := glob.MatchingPattern(d.products[i].PathPatterns, filePath); matched {
 			return true
 		}
 	}
+	return false
+}
 
-	// 標準 filepath.Match
-	matched, err := filepath.Match(pattern, filepath.Base(path))
-	if err == nil && matched {
-		return true
+// Detect 根據檔案路徑與內容檢測適用的產品，無法識別時返回空
+// 先比對 path_patterns（combine 模式的產品需同時符合 content_match），
+// 都不匹配時，再以 content_match 判斷。p 為 nil 時只比對路徑
+//
+// 第一個匹配的產品一定套用；其他匹配的產品只有在全域或該產品設定 multi_product 時才一併套用
+func (d *Detector) Detect(filePath string, p parser.Document) []*Detection {
+	matches := d.matchPaths(filePath, p)
+	if len(matches) == 0 {
+		matches = d.matchContents(p)
 	}
 
-	// 檢查完整路徑
-	matched, err = filepath.Match(pattern, path)
-	return err == nil && matched
+	var selected []*Detection
+	exclusive := false
+	for _, m := range matches {
+		if d.multiProduct || m.Product.MultiProduct {
+			selected = append(selected, m)
+		} else if !exclusive {
+			selected = append(selected, m)
+			exclusive = true
+		}
+	}
+	return selected
 }
 
-// containsPattern 檢查路徑中是否包含匹配模式的部分
-func containsPattern(path, pattern string) bool {
-	// 移除模式中的通配符進行簡單匹配
-	// 例如：pattern = "api*.yaml" -> 檢查路徑中是否有包含 "api" 且結尾是 ".yaml"
-	if len(pattern) > 0 && pattern[0] == '*' {
-		pattern = pattern[1:]
-	}
-	if len(pattern) > 0 && pattern[len(pattern)-1] == '*' {
-		pattern = pattern[:len(pattern)-1]
+// matchPaths 找出所有 path_patterns 匹配的產品
+func (d *Detector) matchPaths(filePath string, p parser.Document) []*Detection {
+	var matches []*Detection
+	for i := range d.products {
+		product := &d.products[i]
+		pattern, matched := glob.MatchingPattern(product.PathPatterns, filePath)
+		if !matched {
+			continue
+		}
+
+		reason := fmt.Sprintf("路徑符合 path_patterns %q", pattern)
+		if product.ContentMatch != nil && product.ContentMatch.mode() == ContentMatchCombine {
+			ok, detail := product.ContentMatch.match(p)
+			if !ok {
+				continue
+			}
+			reason += "，且內容符合 content_match: " + detail
+		}
+		matches = append(matches, &Detection{Product: product, Reason: reason})
 	}
+	return matches
+}
 
-	// 檢查檔案名稱
-	fileName := filepath.Base(path)
-	matched, _ := filepath.Match(pattern, fileName)
-	return matched
+// matchContents 找出所有 content_match 匹配的產品
+// combine 模式的產品只有在未設定 path_patterns 時才會單獨以內容判斷
+func (d *Detector) matchContents(p parser.Document) []*Detection {
+	var matches []*Detection
+	for i := range d.products {
+		product := &d.products[i]
+		cm := product.ContentMatch
+		if cm == nil || (cm.mode() == ContentMatchCombine && len(product.PathPatterns) > 0) {
+			continue
+		}
+		if ok, detail := cm.match(p); ok {
+			matches = append(matches, &Detection{Product: product, Reason: "內容符合 content_match: " + detail})
+		}
+	}
+	return matches
 }
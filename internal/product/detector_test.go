@@ -0,0 +1,154 @@
+package product
+
+import (
+	"strings"
+	"testing"
+)
+
+func detectedNames(detections []*Detection) string {
+	var names []string
+	for _, d := range detections {
+		names = append(names, d.Product.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestDetectMultiProduct(t *testing.T) {
+	products := `
+  - name: api
+    rules_dir: rules/api
+    path_patterns: ["**/api/**"]
+  - name: web
+    rules_dir: rules/web
+    path_patterns: ["**/*-web.yaml"]
+  - name: security
+    rules_dir: rules/security
+    path_patterns: ["**/*.yaml"]
+    multi_product: true
+`
+	single, err := NewDetectorFromBytes([]byte("products:" + products))
+	if err != nil {
+		t.Fatalf("NewDetectorFromBytes: %v", err)
+	}
+	multi, err := NewDetectorFromBytes([]byte("multi_product: true\nproducts:" + products))
+	if err != nil {
+		t.Fatalf("NewDetectorFromBytes: %v", err)
+	}
+
+	tests := []struct {
+		path   string
+		single string
+		multi  string
+	}{
+		// 第一個匹配的產品一定套用，multi_product 的產品一併套用
+		{"svc/api/a-web.yaml", "api,security", "api,web,security"},
+		{"svc/a-web.yaml", "web,security", "web,security"},
+		{"svc/other.yaml", "security", "security"},
+		{"svc/other.json", "", ""},
+	}
+	for _, tt := range tests {
+		if got := detectedNames(single.Detect(tt.path, nil)); got != tt.single {
+			t.Errorf("Detect(%q) = %q, want %q", tt.path, got, tt.single)
+		}
+		if got := detectedNames(multi.Detect(tt.path, nil)); got != tt.multi {
+			t.Errorf("全域 multi_product Detect(%q) = %q, want %q", tt.path, got, tt.multi)
+		}
+	}
+
+	if prod := single.DetectProduct("svc/api/a-web.yaml"); prod == nil || prod.Name != "api" {
+		t.Errorf("DetectProduct() = %v, want api", prod)
+	}
+	detections := single.Detect("svc/api/x.yaml", nil)
+	if detections[0].Reason != `路徑符合 path_patterns "**/api/**"` {
+		t.Errorf("Reason = %q", detections[0].Reason)
+	}
+}
+
+func TestNewDetectorFromConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		product ProductConfig
+		wantErr string
+	}{
+		{"正確", ProductConfig{Name: "a", RulesDir: "rules/a", PathPatterns: []string{"**/*.yaml"}}, ""},
+		{"只有 extends", ProductConfig{Name: "a", Extends: []string{"b"}}, ""},
+		{"pattern 錯誤", ProductConfig{Name: "a", RulesDir: "r", PathPatterns: []string{"*.{yaml"}}, "path_patterns 錯誤"},
+		{"content_match 錯誤", ProductConfig{Name: "a", RulesDir: "r", ContentMatch: &ContentMatch{}}, "content_match"},
+		{"format 不支援", ProductConfig{Name: "a", RulesDir: "r", Format: "xml"}, "format 不支援"},
+		{"沒有規則目錄", ProductConfig{Name: "a"}, "必須設定 rules_dir"},
+	}
+	for _, tt := range tests {
+		config := ProductsConfig{Products: []ProductConfig{tt.product}}
+		if tt.product.Extends != nil {
+			config.Products = append(config.Products, ProductConfig{Name: "b", RulesDir: "rules/b"})
+		}
+		_, err := NewDetectorFromConfig(config)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: NewDetectorFromConfig() = %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: NewDetectorFromConfig() = %v, want 包含 %q", tt.name, err, tt.wantErr)
+		}
+	}
+
+	// 之後修改設定不影響檢測器
+	config := ProductsConfig{Products: []ProductConfig{{
+		Name:         "a",
+		RulesDir:     "r",
+		PathPatterns: []string{"*.yaml"},
+		ContentMatch: &ContentMatch{RequiredKeys: []string{"apiconfig"}, Equals: map[string]interface{}{"kind": "Api"}},
+	}}}
+	d, err := NewDetectorFromConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Products[0].Name = "changed"
+	config.Products[0].PathPatterns[0] = "*.json"
+	config.Products[0].ContentMatch.RequiredKeys[0] = "other"
+	config.Products[0].ContentMatch.Equals["kind"] = "Other"
+	got := d.Products()[0]
+	if got.Name != "a" || got.PathPatterns[0] != "*.yaml" {
+		t.Errorf("Products()[0] = %s %v, want a [*.yaml]", got.Name, got.PathPatterns)
+	}
+	if got.ContentMatch.RequiredKeys[0] != "apiconfig" || got.ContentMatch.Equals["kind"] != "Api" {
+		t.Errorf("ContentMatch = %+v, 不應被修改", got.ContentMatch)
+	}
+}
+
+func TestClaimsPath(t *testing.T) {
+	d, err := NewDetectorFromBytes([]byte(`
+products:
+  - name: api
+    rules_dir: rules/api
+    path_patterns: ["**/api*.{yaml,json}"]
+  - name: legacy
+    rules_dir: rules/legacy
+    format: properties
+    path_patterns: ["legacy/*.conf"]
+    content_match:
+      mode: combine
+      required_keys: [server]
+  - name: content
+    rules_dir: rules/content
+    content_match:
+      required_keys: [kind]
+`))
+	if err != nil {
+		t.Fatalf("NewDetectorFromBytes: %v", err)
+	}
+
+	tests := map[string]bool{
+		"svc/api-prod.json": true,
+		"svc/api-prod.toml": false,
+		"legacy/app.conf":   true, // combine 模式也只看路徑
+		"svc/other.json":    false,
+	}
+	for path, want := range tests {
+		if got := d.ClaimsPath(path); got != want {
+			t.Errorf("ClaimsPath(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package product
+
+import (
+	"config-validator/internal/rule"
+	"fmt"
+	"strings"
+)
+
+// RuleLayers 建立每個產品的分層規則設定
+// 上層依序為 shared_rules_dirs 與 extends 的產品；相同的共用目錄在所有產品間共用同一層
+// resolveDir 用於將設定中的目錄轉換為實際路徑
+func (d *Detector) RuleLayers(resolveDir func(string) string) (map[string]*rule.Layer, error) {
+	layers := make(map[string]*rule.Layer, len(d.products))
+	for _, product := range d.products {
+		layer := &rule.Layer{
+			Name:     product.Name,
+			Disabled: product.DisabledRules,
+		}
+		if product.RulesDir != "" {
+			layer.Dir = resolveDir(product.RulesDir)
+		}
+		layers[product.Name] = layer
+	}
+
+	shared := make(map[string]*rule.Layer)
+	for _, product := range d.products {
+		layer := layers[product.Name]
+
+		for _, dir := range product.SharedRulesDirs {
+			sharedLayer, exists := shared[dir]
+			if !exists {
+				sharedLayer = &rule.Layer{Name: dir, Dir: resolveDir(dir)}
+				shared[dir] = sharedLayer
+			}
+			layer.Parents = append(layer.Parents, sharedLayer)
+		}
+
+		for _, name := range product.Extends {
+			parent, exists := layers[name]
+			if !exists {
+				return nil, fmt.Errorf("產品 %s 繼承的產品 %s 不存在", product.Name, name)
+			}
+			layer.Parents = append(layer.Parents, parent)
+		}
+	}
+
+	return layers, nil
+}
+
+// validateExtends 檢查 extends 參照的產品都存在，且繼承關係沒有循環
+// 在建立檢測器時就檢查，不必等到載入規則才發現
+func validateExtends(products []ProductConfig) error {
+	byName := make(map[string]*ProductConfig, len(products))
+	for i := range products {
+		byName[products[i].Name] = &products[i]
+	}
+	for _, product := range products {
+		for _, name := range product.Extends {
+			if _, exists := byName[name]; !exists {
+				return fmt.Errorf("產品 %s 繼承的產品 %s 不存在", product.Name, name)
+			}
+		}
+	}
+
+	// 深度優先搜尋，stack 為目前路徑上的產品
+	done := make(map[string]bool)
+	var stack []string
+	var visit func(name string) error
+	visit = func(name string) error {
+		for i, visiting := range stack {
+			if visiting == name {
+				return fmt.Errorf("產品繼承出現循環: %s", strings.Join(append(stack[i:], name), " -> "))
+			}
+		}
+		if done[name] {
+			return nil
+		}
+		stack = append(stack, name)
+		for _, parent := range byName[name].Extends {
+			if err := visit(parent); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		done[name] = true
+		return nil
+	}
+	for _, product := range products {
+		if err := visit(product.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
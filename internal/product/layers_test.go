@@ -0,0 +1,70 @@
+package product
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateExtends(t *testing.T) {
+	tests := []struct {
+		name     string
+		products string
+		wantErr  string
+	}{
+		{"沒有繼承", `[{name: a, rules_dir: a}, {name: b, rules_dir: b}]`, ""},
+		{"多層繼承", `[{name: a, extends: [b]}, {name: b, extends: [c]}, {name: c, rules_dir: c}]`, ""},
+		{"菱形繼承", `[{name: a, extends: [b, c]}, {name: b, extends: [d]}, {name: c, extends: [d]}, {name: d, rules_dir: d}]`, ""},
+		{"不存在的產品", `[{name: a, extends: [x]}]`, "產品 a 繼承的產品 x 不存在"},
+		{"繼承自己", `[{name: a, extends: [a]}]`, "產品繼承出現循環: a -> a"},
+		{"循環", `[{name: a, rules_dir: a}, {name: b, extends: [c]}, {name: c, extends: [d]}, {name: d, extends: [b]}]`, "產品繼承出現循環: b -> c -> d -> b"},
+	}
+	for _, tt := range tests {
+		_, err := NewDetectorFromBytes([]byte("products: " + tt.products))
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: NewDetectorFromBytes() = %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: NewDetectorFromBytes() = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestRuleLayers(t *testing.T) {
+	d, err := NewDetectorFromBytes([]byte(`
+products:
+  - name: base
+    rules_dir: rules/base
+  - name: api
+    rules_dir: rules/api
+    extends: [base]
+    shared_rules_dirs: [rules/common]
+    disabled_rules: [base-001]
+  - name: web
+    shared_rules_dirs: [rules/common]
+`))
+	if err != nil {
+		t.Fatalf("NewDetectorFromBytes: %v", err)
+	}
+
+	layers, err := d.RuleLayers(func(dir string) string { return "/root/" + dir })
+	if err != nil {
+		t.Fatalf("RuleLayers: %v", err)
+	}
+
+	api := layers["api"]
+	if api.Dir != "/root/rules/api" || len(api.Disabled) != 1 {
+		t.Errorf("api = %+v", api)
+	}
+	// 共用目錄在前，extends 在後
+	if len(api.Parents) != 2 || api.Parents[0].Dir != "/root/rules/common" || api.Parents[1] != layers["base"] {
+		t.Fatalf("api.Parents = %+v", api.Parents)
+	}
+	// 相同的共用目錄為同一層
+	web := layers["web"]
+	if web.Dir != "" || len(web.Parents) != 1 || web.Parents[0] != api.Parents[0] {
+		t.Errorf("web = %+v", web)
+	}
+}
@@ -2,13 +2,66 @@ package product
 
 // ProductConfig 產品配置
 type ProductConfig struct {
-	Name         string   `yaml:"name"`
-	Description  string   `yaml:"description"`
-	RulesDir     string   `yaml:"rules_dir"`
-	PathPatterns []string `yaml:"path_patterns"`
+	Name         string        `yaml:"name"`
+	Description  string        `yaml:"description"`
+	RulesDir     string        `yaml:"rules_dir"`
+	PathPatterns []string      `yaml:"path_patterns"`
+	Format       string        `yaml:"format,omitempty"`        // 檔案格式，未指定時依副檔名判斷
+	ContentMatch *ContentMatch `yaml:"content_match,omitempty"` // 依檔案內容檢測
+	MultiProduct bool          `yaml:"multi_product,omitempty"` // 匹配時一律套用，可與其他產品並存
+
+	Extends         []string `yaml:"extends,omitempty"`           // 繼承其他產品的規則
+	SharedRulesDirs []string `yaml:"shared_rules_dirs,omitempty"` // 繼承共用規則目錄
+	DisabledRules   []string `yaml:"disabled_rules,omitempty"`    // 停用的繼承規則 ID
+}
+
+// Clone 複製產品配置，包含其中的 slice 與內容匹配條件
+func (p ProductConfig) Clone() ProductConfig {
+	p.PathPatterns = cloneStrings(p.PathPatterns)
+	p.Extends = cloneStrings(p.Extends)
+	p.SharedRulesDirs = cloneStrings(p.SharedRulesDirs)
+	p.DisabledRules = cloneStrings(p.DisabledRules)
+	if p.ContentMatch != nil {
+		match := *p.ContentMatch
+		match.RequiredKeys = cloneStrings(match.RequiredKeys)
+		match.Exists = cloneStrings(match.Exists)
+		if match.Equals != nil {
+			match.Equals = make(map[string]interface{}, len(p.ContentMatch.Equals))
+			for key, value := range p.ContentMatch.Equals {
+				match.Equals[key] = value
+			}
+		}
+		p.ContentMatch = &match
+	}
+	return p
+}
+
+func cloneStrings(list []string) []string {
+	if list == nil {
+		return nil
+	}
+	return append([]string(nil), list...)
+}
+
+// ContentMatch 內容匹配條件
+// 所有指定的條件都必須成立；多文件檔案中任一文件成立即可
+type ContentMatch struct {
+	Mode         string                 `yaml:"mode,omitempty"`          // fallback（預設）或 combine
+	RequiredKeys []string               `yaml:"required_keys,omitempty"` // 必須存在的最上層 key
+	Equals       map[string]interface{} `yaml:"equals,omitempty"`        // 路徑 -> 期望值，如 kind: ApiGateway
+	Exists       []string               `yaml:"exists,omitempty"`        // 必須存在的 JSONPath，如 $.apiconfig.routes[*].path
 }
 
+// 內容匹配模式
+const (
+	// ContentMatchFallback 沒有任何產品的 path_patterns 匹配時，才以內容判斷
+	ContentMatchFallback = "fallback"
+	// ContentMatchCombine path_patterns 與 content_match 都必須匹配
+	ContentMatchCombine = "combine"
+)
+
 // ProductsConfig 產品配置集合
 type ProductsConfig struct {
-	Products []ProductConfig `yaml:"products"`
+	MultiProduct bool            `yaml:"multi_product,omitempty"` // 所有匹配的產品都套用
+	Products     []ProductConfig `yaml:"products"`
 }
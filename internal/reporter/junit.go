@@ -0,0 +1,146 @@
+package reporter
+
+import (
+	"config-validator/internal/rule"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// junitTestSuites JUnit XML 最上層結構
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite 對應一個被驗證的檔案
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase 對應一條匹配的規則
+type junitTestCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	Failures  []junitFailure `xml:"failure"`
+}
+
+// junitFailure 對應一個驗證結果
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// PrintJUnit 輸出 JUnit XML 格式
+// 每個檔案為一個 testsuite，每條匹配的規則為一個 testcase
+func (r *Reporter) PrintJUnit() error {
+	data, err := xml.MarshalIndent(r.buildJUnit(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("生成 JUnit XML 失敗: %w", err)
+	}
+
+	fmt.Print(xml.Header)
+	fmt.Println(string(data))
+	return nil
+}
+
+// buildJUnit 建立 JUnit 報告
+func (r *Reporter) buildJUnit() *junitTestSuites {
+	// 被抑制的結果不算失敗
+	fileGroups := r.groupByFile(r.activeResults())
+
+	// 合併同一檔案的執行紀錄
+	runRules := make(map[string][]*rule.ValidationRule)
+	for _, run := range r.runs {
+		runRules[run.File] = append(runRules[run.File], run.Rules...)
+	}
+	for file := range runRules {
+		if _, exists := fileGroups[file]; !exists {
+			fileGroups[file] = nil
+		}
+	}
+
+	report := &junitTestSuites{Name: toolName}
+	for _, file := range r.getSortedFiles(fileGroups) {
+		suite := junitTestSuite{Name: file}
+
+		// 依規則分組結果
+		resultsByRule := make(map[string][]*rule.ValidationResult)
+		for _, result := range fileGroups[file] {
+			resultsByRule[result.RuleID] = append(resultsByRule[result.RuleID], result)
+		}
+
+		// 先列出有執行紀錄的規則，再補上只出現在結果中的規則
+		seen := make(map[string]bool)
+		var cases []junitTestCase
+		for _, vr := range runRules[file] {
+			if seen[vr.ID] {
+				continue
+			}
+			seen[vr.ID] = true
+			cases = append(cases, newJUnitTestCase(file, vr.ID, vr.Name, resultsByRule[vr.ID]))
+		}
+		for _, result := range fileGroups[file] {
+			if seen[result.RuleID] {
+				continue
+			}
+			seen[result.RuleID] = true
+			cases = append(cases, newJUnitTestCase(file, result.RuleID, result.RuleName, resultsByRule[result.RuleID]))
+		}
+
+		for _, tc := range cases {
+			suite.Tests++
+			if len(tc.Failures) > 0 {
+				suite.Failures++
+			}
+		}
+		suite.TestCases = cases
+
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Suites = append(report.Suites, suite)
+	}
+
+	return report
+}
+
+// newJUnitTestCase 建立規則的 testcase，每個結果轉換為一個 failure
+func newJUnitTestCase(file, ruleID, ruleName string, results []*rule.ValidationResult) junitTestCase {
+	tc := junitTestCase{
+		Name:      fmt.Sprintf("[%s] %s", ruleID, ruleName),
+		ClassName: file,
+	}
+	for _, result := range results {
+		tc.Failures = append(tc.Failures, junitFailure{
+			Message: result.Message,
+			Type:    string(result.Severity),
+			Text:    junitFailureText(result),
+		})
+	}
+	return tc
+}
+
+// junitFailureText 組合 failure 的詳細內容
+func junitFailureText(result *rule.ValidationResult) string {
+	var lines []string
+	if result.Path != "" {
+		lines = append(lines, fmt.Sprintf("路徑: %s", result.Path))
+	}
+	if result.Line > 0 {
+		lines = append(lines, fmt.Sprintf("位置: %s:%s", result.File, formatRange(result)))
+	}
+	if result.ActualValue != "" {
+		lines = append(lines, fmt.Sprintf("實際值: %s", result.ActualValue))
+	}
+	if result.ExpectedValue != "" {
+		lines = append(lines, fmt.Sprintf("期望值: %s", result.ExpectedValue))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,72 @@
+package reporter
+
+import (
+	"config-validator/internal/rule"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestBuildJUnit(t *testing.T) {
+	portRule := &rule.ValidationRule{ID: "port", Name: "連接埠"}
+	hostRule := &rule.ValidationRule{ID: "host", Name: "主機"}
+
+	r := NewReporter()
+	r.AddFileRun("a.yaml", []*rule.ValidationRule{portRule, hostRule})
+	r.AddFileRun("b.yaml", []*rule.ValidationRule{portRule})
+	r.AddFileRun("a.yaml", []*rule.ValidationRule{portRule}) // 同一檔案的多次紀錄會合併
+	r.AddResults([]*rule.ValidationResult{
+		{File: "a.yaml", RuleID: "port", RuleName: "連接埠", Severity: rule.SeverityError, Message: "超出範圍",
+			Path: "server.port", Line: 3, Column: 9, EndLine: 3, EndColumn: 13, ActualValue: "99999"},
+		{File: "a.yaml", RuleID: "port", RuleName: "連接埠", Severity: rule.SeverityWarning, Message: "第二個結果"},
+		{File: "b.yaml", RuleID: "port", RuleName: "連接埠", Severity: rule.SeverityError, Message: "已抑制", Suppressed: true},
+		{File: "c.yaml", RuleID: "extra", RuleName: "額外", Severity: rule.SeverityInfo, Message: "沒有執行紀錄"},
+	})
+
+	report := r.buildJUnit()
+	if report.Tests != 4 || report.Failures != 2 {
+		t.Errorf("tests = %d, failures = %d, want 4, 2", report.Tests, report.Failures)
+	}
+
+	var names []string
+	for _, suite := range report.Suites {
+		names = append(names, suite.Name)
+	}
+	if got := strings.Join(names, ","); got != "a.yaml,b.yaml,c.yaml" {
+		t.Fatalf("suites = %s", got)
+	}
+
+	a := report.Suites[0]
+	if a.Tests != 2 || a.Failures != 1 {
+		t.Errorf("a.yaml tests = %d, failures = %d, want 2, 1", a.Tests, a.Failures)
+	}
+	port := a.TestCases[0]
+	if port.Name != "[port] 連接埠" || port.ClassName != "a.yaml" || len(port.Failures) != 2 {
+		t.Fatalf("a.yaml testcase = %+v", port)
+	}
+	if f := port.Failures[0]; f.Type != "error" || f.Message != "超出範圍" ||
+		f.Text != "路徑: server.port\n位置: a.yaml:3:9-3:13\n實際值: 99999" {
+		t.Errorf("failure = %+v", f)
+	}
+	if host := a.TestCases[1]; host.Name != "[host] 主機" || len(host.Failures) != 0 {
+		t.Errorf("通過的規則 = %+v", host)
+	}
+
+	// 被抑制的結果不算失敗
+	if b := report.Suites[1]; b.Tests != 1 || b.Failures != 0 {
+		t.Errorf("b.yaml tests = %d, failures = %d, want 1, 0", b.Tests, b.Failures)
+	}
+
+	// 只出現在結果中的規則也會列出
+	if c := report.Suites[2]; c.Tests != 1 || c.TestCases[0].Name != "[extra] 額外" {
+		t.Errorf("c.yaml = %+v", c)
+	}
+
+	data, err := xml.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), `<testsuites name="config-validator" tests="4" failures="2">`) {
+		t.Errorf("xml = %s", data)
+	}
+}
@@ -4,12 +4,33 @@ import (
 	"config-validator/internal/rule"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"sort"
+	"strings"
+	"sync"
 )
 
 // Reporter 結果輸出器
+// AddResults 與 AddFileRun 可在多個 goroutine 中同時呼叫
 type Reporter struct {
+	mu      sync.Mutex
 	results []*rule.ValidationResult
+	runs    []*FileRun // 每個檔案實際執行的規則（依加入順序）
+	skipped []string   // 無法識別產品而略過驗證的檔案
+}
+
+// AddSkipped 記錄無法識別產品而略過驗證的檔案，JSON 輸出中列於 skipped
+func (r *Reporter) AddSkipped(file string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.skipped = append(r.skipped, file)
+}
+
+// FileRun 單一檔案的規則執行紀錄
+type FileRun struct {
+	File  string
+	Rules []*rule.ValidationRule
 }
 
 // NewReporter 建立新的輸出器
@@ -21,12 +42,43 @@ func NewReporter() *Reporter {
 
 // AddResults 添加驗證結果
 func (r *Reporter) AddResults(results []*rule.ValidationResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.results = append(r.results, results...)
 }
 
-// HasErrors 是否有錯誤
-func (r *Reporter) HasErrors() bool {
+// Results 返回所有驗證結果（包含被抑制的結果）
+func (r *Reporter) Results() []*rule.ValidationResult {
+	return r.results
+}
+
+// Filter 只保留 keep 返回 true 的結果，返回被移除的數量
+func (r *Reporter) Filter(keep func(result *rule.ValidationResult) bool) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := make([]*rule.ValidationResult, 0, len(r.results))
 	for _, result := range r.results {
+		if keep(result) {
+			kept = append(kept, result)
+		}
+	}
+	removed := len(r.results) - len(kept)
+	r.results = kept
+	return removed
+}
+
+// AddFileRun 記錄某個檔案實際執行的規則
+// 沒有產生結果的規則也需要記錄，JUnit 等格式才能列出通過的規則
+func (r *Reporter) AddFileRun(file string, rules []*rule.ValidationRule) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runs = append(r.runs, &FileRun{File: file, Rules: rules})
+}
+
+// HasErrors 是否有錯誤（不含被抑制的結果）
+func (r *Reporter) HasErrors() bool {
+	for _, result := range r.activeResults() {
 		if result.Severity == rule.SeverityError {
 			return true
 		}
@@ -38,13 +90,18 @@ func (r *Reporter) HasErrors() bool {
 func (r *Reporter) PrintConsole(ruleCount int) {
 	fmt.Printf("📋 載入了 %d 條規則\n\n", ruleCount)
 
-	if len(r.results) == 0 {
+	active := r.activeResults()
+	suppressedCount := len(r.results) - len(active)
+	if len(active) == 0 {
 		fmt.Println("✅ 所有驗證通過")
+		if suppressedCount > 0 {
+			fmt.Printf("🔕 %d 個已抑制\n", suppressedCount)
+		}
 		return
 	}
 
 	// 按檔案分組
-	fileGroups := r.groupByFile()
+	fileGroups := r.groupByFile(active)
 
 	// 輸出每個檔案的驗證結果
 	for _, file := range r.getSortedFiles(fileGroups) {
@@ -57,12 +114,18 @@ func (r *Reporter) PrintConsole(ruleCount int) {
 			if result.Path != "" {
 				fmt.Printf("     路徑: %s\n", result.Path)
 			}
+			if result.Line > 0 {
+				fmt.Printf("     位置: %s\n", formatRange(result))
+			}
 			if result.ActualValue != "" {
 				fmt.Printf("     實際值: %s\n", result.ActualValue)
 			}
 			if result.ExpectedValue != "" {
 				fmt.Printf("     期望值: %s\n", result.ExpectedValue)
 			}
+			if len(result.RelatedFiles) > 0 {
+				fmt.Printf("     相關檔案: %s\n", strings.Join(result.RelatedFiles, ", "))
+			}
 		}
 		fmt.Println()
 	}
@@ -76,13 +139,26 @@ func (r *Reporter) PrintConsole(ruleCount int) {
 	if warningCount > 0 {
 		fmt.Printf("⚠️  %d 個警告\n", warningCount)
 	}
+	if suppressedCount > 0 {
+		fmt.Printf("🔕 %d 個已抑制\n", suppressedCount)
+	}
 }
 
 // PrintJSON 輸出 JSON 格式
+// 被抑制的結果也會列出（suppressed 為 true 並附上原因），但不計入 total
 func (r *Reporter) PrintJSON() error {
+	return r.WriteJSON(os.Stdout)
+}
+
+// WriteJSON 將 JSON 格式的結果寫入 w，格式與 PrintJSON 相同
+func (r *Reporter) WriteJSON(w io.Writer) error {
 	output := map[string]interface{}{
-		"total":   len(r.results),
-		"results": r.results,
+		"total":      len(r.activeResults()),
+		"suppressed": len(r.results) - len(r.activeResults()),
+		"results":    r.results,
+	}
+	if len(r.skipped) > 0 {
+		output["skipped"] = r.skipped
 	}
 
 	data, err := json.MarshalIndent(output, "", "  ")
@@ -90,15 +166,26 @@ func (r *Reporter) PrintJSON() error {
 		return fmt.Errorf("生成 JSON 失敗: %w", err)
 	}
 
-	fmt.Println(string(data))
-	return nil
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// activeResults 返回未被抑制的結果
+func (r *Reporter) activeResults() []*rule.ValidationResult {
+	active := make([]*rule.ValidationResult, 0, len(r.results))
+	for _, result := range r.results {
+		if !result.Suppressed {
+			active = append(active, result)
+		}
+	}
+	return active
 }
 
 // groupByFile 按檔案分組結果
-func (r *Reporter) groupByFile() map[string][]*rule.ValidationResult {
+func (r *Reporter) groupByFile(results []*rule.ValidationResult) map[string][]*rule.ValidationResult {
 	groups := make(map[string][]*rule.ValidationResult)
 
-	for _, result := range r.results {
+	for _, result := range results {
 		groups[result.File] = append(groups[result.File], result)
 	}
 
@@ -131,7 +218,7 @@ func (r *Reporter) getSeverityIcon(severity rule.Severity) string {
 
 // countBySeverity 統計各嚴重程度的數量
 func (r *Reporter) countBySeverity() (errors, warnings int) {
-	for _, result := range r.results {
+	for _, result := range r.activeResults() {
 		switch result.Severity {
 		case rule.SeverityError:
 			errors++
@@ -141,3 +228,11 @@ func (r *Reporter) countBySeverity() (errors, warnings int) {
 	}
 	return
 }
+
+// formatRange 格式化結果的來源範圍（行:欄），如: "8:12-8:17"
+func formatRange(result *rule.ValidationResult) string {
+	if result.EndLine == 0 {
+		return fmt.Sprintf("%d:%d", result.Line, result.Column)
+	}
+	return fmt.Sprintf("%d:%d-%d:%d", result.Line, result.Column, result.EndLine, result.EndColumn)
+}
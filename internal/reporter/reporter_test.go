@@ -0,0 +1,94 @@
+package reporter
+
+import (
+	"bytes"
+	"config-validator/internal/rule"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestReporterConcurrentAdd(t *testing.T) {
+	r := NewReporter()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			file := fmt.Sprintf("f%02d.yaml", i)
+			r.AddFileRun(file, []*rule.ValidationRule{{ID: "r1"}})
+			r.AddResults([]*rule.ValidationResult{
+				{File: file, RuleID: "r1", Severity: rule.SeverityWarning},
+				{File: file, RuleID: "r1", Severity: rule.SeverityInfo},
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	if got := len(r.Results()); got != 40 {
+		t.Errorf("Results() = %d, want 40", got)
+	}
+	if got := len(r.runs); got != 20 {
+		t.Errorf("runs = %d, want 20", got)
+	}
+	if r.HasErrors() {
+		t.Error("HasErrors() = true, want false")
+	}
+}
+
+func TestReporterFilter(t *testing.T) {
+	r := NewReporter()
+	r.AddResults([]*rule.ValidationResult{
+		{RuleID: "a", Severity: rule.SeverityError},
+		{RuleID: "b", Severity: rule.SeverityError, Suppressed: true},
+		{RuleID: "c", Severity: rule.SeverityWarning},
+	})
+	if !r.HasErrors() {
+		t.Fatal("HasErrors() = false, want true")
+	}
+
+	removed := r.Filter(func(result *rule.ValidationResult) bool { return result.RuleID != "a" })
+	if removed != 1 || len(r.Results()) != 2 {
+		t.Errorf("Filter() removed = %d, remaining = %d", removed, len(r.Results()))
+	}
+	// 被抑制的 error 不算錯誤
+	if r.HasErrors() {
+		t.Error("HasErrors() = true, want false")
+	}
+	if errors, warnings := r.countBySeverity(); errors != 0 || warnings != 1 {
+		t.Errorf("countBySeverity() = %d, %d", errors, warnings)
+	}
+}
+
+func TestReporterWriteJSONSkipped(t *testing.T) {
+	r := NewReporter()
+	r.AddResults([]*rule.ValidationResult{{File: "a.yaml", RuleID: "a", Severity: rule.SeverityError}})
+
+	// 沒有略過的檔案時不輸出 skipped，與原本的格式相同
+	var out bytes.Buffer
+	if err := r.WriteJSON(&out); err != nil {
+		t.Fatal(err)
+	}
+	var output map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &output); err != nil {
+		t.Fatal(err)
+	}
+	if _, exists := output["skipped"]; exists || output["total"] != 1.0 {
+		t.Errorf("WriteJSON() = %s", out.String())
+	}
+
+	r.AddSkipped("b.yaml")
+	out.Reset()
+	if err := r.WriteJSON(&out); err != nil {
+		t.Fatal(err)
+	}
+	output = nil
+	if err := json.Unmarshal(out.Bytes(), &output); err != nil {
+		t.Fatal(err)
+	}
+	if skipped, _ := output["skipped"].([]interface{}); len(skipped) != 1 || skipped[0] != "b.yaml" {
+		t.Errorf("skipped = %v, want [b.yaml]", output["skipped"])
+	}
+}
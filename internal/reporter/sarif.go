@@ -0,0 +1,244 @@
+package reporter
+
+import (
+	"config-validator/internal/rule"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	toolName     = "config-validator"
+)
+
+// sarifLog SARIF 2.1.0 最上層結構
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string                     `json:"name"`
+	Rules []sarifReportingDescriptor `json:"rules"`
+}
+
+// sarifReportingDescriptor 對應一條 ValidationRule
+type sarifReportingDescriptor struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	FullDescription      *sarifMessage      `json:"fullDescription,omitempty"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+// sarifResult 對應一個 ValidationResult
+type sarifResult struct {
+	RuleID     string                 `json:"ruleId"`
+	RuleIndex  int                    `json:"ruleIndex"`
+	Level      string                 `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Locations  []sarifLocation        `json:"locations"`
+	Related    []sarifLocation        `json:"relatedLocations,omitempty"`
+	Suppressed []sarifSuppression     `json:"suppressions,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+// sarifSuppression 對應配置檔中的抑制註解
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// PrintSARIF 輸出 SARIF 2.1.0 格式
+// rules 為本次載入的所有規則，會轉換為 tool.driver.rules
+func (r *Reporter) PrintSARIF(rules []*rule.ValidationRule) error {
+	data, err := json.MarshalIndent(r.buildSARIF(rules), "", "  ")
+	if err != nil {
+		return fmt.Errorf("生成 SARIF 失敗: %w", err)
+	}
+
+	fmt.Println(string(data))
+	return nil
+}
+
+// buildSARIF 建立 SARIF 記錄
+func (r *Reporter) buildSARIF(rules []*rule.ValidationRule) *sarifLog {
+	// 依規則 ID 去重並排序，確保輸出穩定
+	ruleByID := make(map[string]*rule.ValidationRule)
+	for _, vr := range rules {
+		if _, exists := ruleByID[vr.ID]; !exists {
+			ruleByID[vr.ID] = vr
+		}
+	}
+	ids := make([]string, 0, len(ruleByID))
+	for id := range ruleByID {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	descriptors := make([]sarifReportingDescriptor, 0, len(ids))
+	ruleIndex := make(map[string]int, len(ids))
+	for i, id := range ids {
+		vr := ruleByID[id]
+		descriptor := sarifReportingDescriptor{
+			ID:                   vr.ID,
+			Name:                 vr.Name,
+			ShortDescription:     sarifMessage{Text: vr.Name},
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevel(vr.Severity)},
+		}
+		if vr.Description != "" {
+			descriptor.FullDescription = &sarifMessage{Text: vr.Description}
+		}
+		descriptors = append(descriptors, descriptor)
+		ruleIndex[id] = i
+	}
+
+	results := make([]sarifResult, 0, len(r.results))
+	for _, result := range r.results {
+		index, known := ruleIndex[result.RuleID]
+		if !known {
+			index = -1
+		}
+		sr := sarifResult{
+			RuleID:     result.RuleID,
+			RuleIndex:  index,
+			Level:      sarifLevel(result.Severity),
+			Message:    sarifMessage{Text: result.Message},
+			Locations:  []sarifLocation{sarifLocationOf(result)},
+			Related:    sarifRelatedLocations(result),
+			Properties: sarifProperties(result),
+		}
+		if result.Suppressed {
+			sr.Suppressed = []sarifSuppression{{Kind: "inSource", Justification: result.SuppressionReason}}
+		}
+		results = append(results, sr)
+	}
+
+	return &sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:  toolName,
+				Rules: descriptors,
+			}},
+			Results: results,
+		}},
+	}
+}
+
+// sarifLocationOf 建立結果的實體位置與邏輯路徑
+func sarifLocationOf(result *rule.ValidationResult) sarifLocation {
+	location := sarifLocation{
+		PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(result.File)},
+		},
+	}
+	if result.Line > 0 {
+		location.PhysicalLocation.Region = &sarifRegion{
+			StartLine:   result.Line,
+			StartColumn: result.Column,
+			EndLine:     result.EndLine,
+			EndColumn:   result.EndColumn,
+		}
+	}
+	if result.Path != "" {
+		location.LogicalLocations = []sarifLogicalLocation{{
+			FullyQualifiedName: result.Path,
+			Kind:               "member",
+		}}
+	}
+	return location
+}
+
+// sarifRelatedLocations 將相關檔案（如跨檔案參考的來源）轉換為 relatedLocations
+func sarifRelatedLocations(result *rule.ValidationResult) []sarifLocation {
+	var related []sarifLocation
+	for _, file := range result.RelatedFiles {
+		related = append(related, sarifLocation{
+			PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(file)},
+			},
+		})
+	}
+	return related
+}
+
+// sarifProperties 將實際值、期望值與所屬產品放入 properties
+func sarifProperties(result *rule.ValidationResult) map[string]interface{} {
+	props := make(map[string]interface{})
+	if result.ActualValue != "" {
+		props["actualValue"] = result.ActualValue
+	}
+	if result.ExpectedValue != "" {
+		props["expectedValue"] = result.ExpectedValue
+	}
+	if result.Product != "" {
+		props["product"] = result.Product
+	}
+	if len(props) == 0 {
+		return nil
+	}
+	return props
+}
+
+// sarifLevel 將嚴重程度轉換為 SARIF level
+func sarifLevel(severity rule.Severity) string {
+	switch severity {
+	case rule.SeverityError:
+		return "error"
+	case rule.SeverityWarning:
+		return "warning"
+	case rule.SeverityInfo:
+		return "note"
+	default:
+		return "none"
+	}
+}
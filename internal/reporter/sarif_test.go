@@ -0,0 +1,100 @@
+package reporter
+
+import (
+	"config-validator/internal/rule"
+	"encoding/json"
+	"testing"
+)
+
+func TestBuildSARIF(t *testing.T) {
+	rules := []*rule.ValidationRule{
+		{ID: "b-002", Name: "規則 B", Severity: rule.SeverityWarning},
+		{ID: "a-001", Name: "規則 A", Severity: rule.SeverityError, Description: "說明"},
+		{ID: "a-001", Name: "重複的規則", Severity: rule.SeverityError},
+	}
+
+	r := NewReporter()
+	r.AddResults([]*rule.ValidationResult{
+		{
+			File: "configs/api.yaml", RuleID: "a-001", Severity: rule.SeverityError, Message: "錯誤",
+			Path: "server.port", Line: 3, Column: 9, EndLine: 3, EndColumn: 13,
+			ActualValue: "80", Product: "api",
+		},
+		{File: "configs/api.yaml", RuleID: "b-002", Severity: rule.SeverityWarning, Message: "已抑制",
+			Suppressed: true, SuppressionReason: "暫時"},
+		{File: "configs/web.yaml", RuleID: "unknown", Severity: rule.SeverityInfo, Message: "不在規則列表中",
+			RelatedFiles: []string{"configs/a.yaml"}},
+	})
+
+	log := r.buildSARIF(rules)
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("version = %q, runs = %d", log.Version, len(log.Runs))
+	}
+	run := log.Runs[0]
+
+	// 規則依 ID 去重並排序
+	if got := len(run.Tool.Driver.Rules); got != 2 {
+		t.Fatalf("rules = %d, want 2", got)
+	}
+	if a := run.Tool.Driver.Rules[0]; a.ID != "a-001" || a.Name != "規則 A" || a.FullDescription == nil || a.DefaultConfiguration.Level != "error" {
+		t.Errorf("rules[0] = %+v", a)
+	}
+	if b := run.Tool.Driver.Rules[1]; b.ID != "b-002" || b.FullDescription != nil || b.DefaultConfiguration.Level != "warning" {
+		t.Errorf("rules[1] = %+v", b)
+	}
+
+	if len(run.Results) != 3 {
+		t.Fatalf("results = %d, want 3", len(run.Results))
+	}
+
+	first := run.Results[0]
+	if first.RuleIndex != 0 || first.Level != "error" {
+		t.Errorf("results[0] ruleIndex = %d, level = %q", first.RuleIndex, first.Level)
+	}
+	location := first.Locations[0]
+	if uri := location.PhysicalLocation.ArtifactLocation.URI; uri != "configs/api.yaml" {
+		t.Errorf("uri = %q, want configs/api.yaml", uri)
+	}
+	if region := location.PhysicalLocation.Region; region == nil || *region != (sarifRegion{3, 9, 3, 13}) {
+		t.Errorf("region = %+v", region)
+	}
+	if len(location.LogicalLocations) != 1 || location.LogicalLocations[0].FullyQualifiedName != "server.port" {
+		t.Errorf("logicalLocations = %+v", location.LogicalLocations)
+	}
+	if first.Properties["actualValue"] != "80" || first.Properties["product"] != "api" {
+		t.Errorf("properties = %v", first.Properties)
+	}
+
+	second := run.Results[1]
+	if len(second.Suppressed) != 1 || second.Suppressed[0].Kind != "inSource" || second.Suppressed[0].Justification != "暫時" {
+		t.Errorf("suppressions = %+v", second.Suppressed)
+	}
+	if second.Locations[0].PhysicalLocation.Region != nil {
+		t.Errorf("沒有行號的結果不應有 region")
+	}
+	if second.Properties != nil {
+		t.Errorf("properties = %v, want nil", second.Properties)
+	}
+
+	third := run.Results[2]
+	if third.RuleIndex != -1 || third.Level != "note" {
+		t.Errorf("results[2] ruleIndex = %d, level = %q", third.RuleIndex, third.Level)
+	}
+	if len(third.Related) != 1 || third.Related[0].PhysicalLocation.ArtifactLocation.URI != "configs/a.yaml" {
+		t.Errorf("relatedLocations = %+v", third.Related)
+	}
+
+	// 沒有結果時 results 仍為空陣列（SARIF 要求此欄位）
+	data, err := json.Marshal(NewReporter().buildSARIF(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	runs := decoded["runs"].([]interface{})
+	if results, ok := runs[0].(map[string]interface{})["results"].([]interface{}); !ok || len(results) != 0 {
+		t.Errorf("空的 results = %v", runs[0].(map[string]interface{})["results"])
+	}
+}
@@ -0,0 +1,103 @@
+package rule
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExecuteNotMissingPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		branch  string
+		content string
+		want    int
+	}{
+		// pattern_match 在欄位不存在時不回報，分支不適用，not 也不回報
+		{"pattern_match 符合", "type: pattern_match\n    path: env\n    pattern: '^prod$'\n    message: 是 prod", "env: prod\n", 1},
+		{"pattern_match 不符合", "type: pattern_match\n    path: env\n    pattern: '^prod$'\n    message: 是 prod", "env: dev\n", 0},
+		{"pattern_match 欄位不存在", "type: pattern_match\n    path: env\n    pattern: '^prod$'\n    message: 是 prod", "port: 80\n", 0},
+		{"萬用字元路徑沒有項目", "type: pattern_match\n    path: 'routes[*].path'\n    pattern: '^/'\n    message: 以 / 開頭", "routes: []\n", 0},
+		{"萬用字元路徑都符合", "type: pattern_match\n    path: 'routes[*].path'\n    pattern: '^/'\n    message: 以 / 開頭", "routes: [{path: /a}]\n", 1},
+
+		// required_field 本身檢查欄位是否存在，一律適用
+		{"required_field 存在", "type: required_field\n    path: debug\n    message: 缺少 debug", "debug: true\n", 1},
+		{"required_field 不存在", "type: required_field\n    path: debug\n    message: 缺少 debug", "port: 80\n", 0},
+
+		// expression 引用的路徑不存在時不適用，exists() 可以明確判斷
+		{"expression 成立", "type: expression\n    expression: port < 1024\n    message: 特權連接埠", "port: 80\n", 1},
+		{"expression 路徑不存在", "type: expression\n    expression: port < 1024\n    message: 特權連接埠", "name: a\n", 0},
+		{"expression 以 exists 判斷", "type: expression\n    expression: '!exists(port)'\n    message: 缺少 port", "name: a\n", 1},
+		{"for_each 所有項目都不適用", "type: expression\n    for_each: 'routes[*]'\n    expression: '@.timeout > 0'\n    message: 有 timeout", "routes: [{path: /a}]\n", 0},
+		{"for_each 部分項目適用", "type: expression\n    for_each: 'routes[*]'\n    expression: '@.timeout > 0'\n    message: 有 timeout", "routes: [{path: /a}, {timeout: 5}]\n", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := mustRule(t, `
+id: not-test
+name: not 測試
+enabled: true
+severity: error
+targets:
+  file_patterns: ["*.yaml"]
+rule:
+  type: not
+  message: 不應通過
+  rule:
+    `+tt.branch+`
+`)
+			results := NewExecutor(mustParse(t, tt.content)).Execute(r, "app.yaml")
+			if len(results) != tt.want {
+				t.Errorf("results = %d, want %d", len(results), tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteNoneOfMissingPath(t *testing.T) {
+	r := mustRule(t, `
+id: deprecated
+name: 已棄用的設定
+enabled: true
+severity: warning
+targets:
+  file_patterns: ["*.yaml"]
+rule:
+  type: none_of
+  message: 不可使用已棄用的設定
+  rules:
+    - type: required_field
+      path: legacy.mode
+      message: legacy.mode 已棄用
+    - type: field_type
+      path: timeout
+      expected_type: string
+      message: timeout 不應為字串
+    - type: expression
+      expression: debug == true
+      message: 不可開啟 debug
+`)
+
+	tests := []struct {
+		content string
+		want    []string // 通過的分支
+	}{
+		{"port: 80\n", nil},
+		{"legacy:\n  mode: old\n", []string{"分支 1"}},
+		{"timeout: '30s'\ndebug: true\n", []string{"分支 2", "分支 3"}},
+		{"timeout: 30\ndebug: false\n", nil},
+	}
+	for _, tt := range tests {
+		results := NewExecutor(mustParse(t, tt.content)).Execute(r, "app.yaml")
+		var got []string
+		for _, result := range results {
+			for _, label := range []string{"分支 1", "分支 2", "分支 3"} {
+				if strings.Contains(result.Message, label+" (") {
+					got = append(got, label)
+				}
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%q: 通過的分支 = %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
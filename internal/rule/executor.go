@@ -1,6 +1,7 @@
 package rule
 
 import (
+	"config-validator/internal/expr"
 	"config-validator/internal/parser"
 	"crypto/md5"
 	"crypto/sha1"
@@ -9,7 +10,10 @@ import (
 	"encoding/hex"
 	"fmt"
 	"hash"
+	"reflect"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -17,18 +21,69 @@ import (
 
 // Executor 規則執行引擎
 type Executor struct {
-	parser *parser.YAMLParser
+	parser    parser.Document
+	workspace *Workspace // 跨檔案規則使用，未設定時只能看到目前的檔案
 }
 
 // NewExecutor 建立新的執行引擎
-func NewExecutor(p *parser.YAMLParser) *Executor {
+func NewExecutor(p parser.Document) *Executor {
 	return &Executor{
 		parser: p,
 	}
 }
 
+// SetWorkspace 設定工作區，供跨檔案規則查詢其他檔案
+func (e *Executor) SetWorkspace(ws *Workspace) {
+	e.workspace = ws
+}
+
 // Execute 執行規則驗證
+// 回傳的每個結果都會附上來源檔案中的行列位置
+// 多文件的檔案會對每個文件各執行一次，並在路徑前加上文件序號，如: "[doc 2] apiconfig.routes[0].path"
 func (e *Executor) Execute(rule *ValidationRule, filePath string) []*ValidationResult {
+	var results []*ValidationResult
+
+	docs := e.parser.Documents()
+	if len(docs) == 1 {
+		results = e.executeRule(rule, filePath)
+		e.attachPositions(results)
+	} else {
+		for _, doc := range docs {
+			docExecutor := NewExecutor(doc)
+			docExecutor.SetWorkspace(e.workspace)
+			docResults := docExecutor.executeRule(rule, filePath)
+			docExecutor.attachPositions(docResults)
+			for _, result := range docResults {
+				result.Document = doc.DocumentIndex()
+				result.Path = strings.TrimSpace(fmt.Sprintf("[doc %d] %s", result.Document, result.Path))
+			}
+			results = append(results, docResults...)
+		}
+	}
+
+	for _, result := range results {
+		result.Product = rule.Product
+	}
+	return results
+}
+
+// attachPositions 根據結果路徑補上來源位置
+// 路徑不存在時（如缺少必要欄位）會定位到最近的上層節點
+func (e *Executor) attachPositions(results []*ValidationResult) {
+	for _, result := range results {
+		if result.Path == "" || result.Line != 0 {
+			continue
+		}
+		pos := e.parser.LocatePath(result.Path)
+		result.Line = pos.Line
+		result.Column = pos.Column
+		result.EndLine = pos.EndLine
+		result.EndColumn = pos.EndColumn
+	}
+}
+
+// executeRule 依規則類型分派至對應的執行函數
+func (e *Executor) executeRule(rule *ValidationRule, filePath string) []*ValidationResult {
 	switch rule.Rule.Type {
 	case RuleTypeRequiredField:
 		return e.executeRequiredField(rule, filePath)
@@ -54,6 +109,18 @@ func (e *Executor) Execute(rule *ValidationRule, filePath string) []*ValidationR
 		return e.executeContainsKeywords(rule, filePath)
 	case RuleTypeNoTrailingWhitespace:
 		return e.executeNoTrailingWhitespace(rule, filePath)
+	case RuleTypeConditional:
+		return e.executeConditional(rule, filePath)
+	case RuleTypeReferenceExists:
+		return e.executeReferenceExists(rule, filePath)
+	case RuleTypeExpression:
+		return e.executeExpression(rule, filePath)
+	case RuleTypeAllOf, RuleTypeAnyOf, RuleTypeNoneOf:
+		return e.executeComposite(rule, filePath)
+	case RuleTypeNot:
+		return e.executeNot(rule, filePath)
+	case RuleTypeArraySorted:
+		return e.executeArraySorted(rule, filePath)
 	default:
 		return []*ValidationResult{
 			{
@@ -272,13 +339,20 @@ func (e *Executor) executeValueRange(rule *ValidationRule, filePath string) []*V
 	}
 
 	return e.processPathWithWildcard(ruleDetail.Path, func(actualPath string, value interface{}) *ValidationResult {
-		// 轉換為數字
+		// 轉換為數字；.env、.properties、INI 的值一律為字串，內容是數字時也檢查
 		var numValue float64
 		switch v := value.(type) {
 		case int:
 			numValue = float64(v)
 		case float64:
 			numValue = v
+		case string:
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				// 不是數字，跳過檢查
+				return nil
+			}
+			numValue = parsed
 		default:
 			// 不是數字，跳過檢查
 			return nil
@@ -472,6 +546,7 @@ func (e *Executor) executeArrayItemField(rule *ValidationRule, filePath string)
 							Path:          fmt.Sprintf("%s[%d].%s", pathInfo.Path, i, ruleDetail.Field),
 							ActualValue:   fieldStr,
 							ExpectedValue: fmt.Sprintf("one of [%s]", strings.Join(ruleDetail.Validation.AllowedValues, ", ")),
+							Fix:           caseFix(fmt.Sprintf("%s[%d].%s", pathInfo.Path, i, ruleDetail.Field), fieldStr, ruleDetail.Validation.AllowedValues),
 						})
 					}
 				}
@@ -533,6 +608,7 @@ func (e *Executor) executeArrayItemField(rule *ValidationRule, filePath string)
 					Path:          fmt.Sprintf("%s[%d].%s", ruleDetail.Path, i, ruleDetail.Field),
 					ActualValue:   fieldStr,
 					ExpectedValue: fmt.Sprintf("one of [%s]", strings.Join(ruleDetail.Validation.AllowedValues, ", ")),
+					Fix:           caseFix(fmt.Sprintf("%s[%d].%s", ruleDetail.Path, i, ruleDetail.Field), fieldStr, ruleDetail.Validation.AllowedValues),
 				})
 			}
 		}
@@ -626,6 +702,7 @@ func (e *Executor) executeArrayNoDuplicates(rule *ValidationRule, filePath strin
 			}
 
 			// 為每個重複的索引建立一個錯誤
+			arr, _ := pathInfo.Value.([]interface{})
 			for _, dup := range duplicates {
 				for _, idx := range dup.Indices {
 					results = append(results, &ValidationResult{
@@ -635,6 +712,7 @@ func (e *Executor) executeArrayNoDuplicates(rule *ValidationRule, filePath strin
 						Severity: rule.Severity,
 						Message:  fmt.Sprintf("%s (重複值: %s)", ruleDetail.Message, dup.Value),
 						Path:     fmt.Sprintf("%s[%d].%s", pathInfo.Path, idx, ruleDetail.Field),
+						Fix:      duplicateFix(pathInfo.Path, arr, dup.Indices, idx),
 					})
 				}
 			}
@@ -649,6 +727,7 @@ func (e *Executor) executeArrayNoDuplicates(rule *ValidationRule, filePath strin
 		return nil
 	}
 
+	arr, _ := e.parser.GetArray(ruleDetail.Path)
 	var results []*ValidationResult
 	for _, dup := range duplicates {
 		// 為每個重複的索引建立一個錯誤
@@ -660,6 +739,7 @@ func (e *Executor) executeArrayNoDuplicates(rule *ValidationRule, filePath strin
 				Severity: rule.Severity,
 				Message:  fmt.Sprintf("%s (重複值: %s)", ruleDetail.Message, dup.Value),
 				Path:     fmt.Sprintf("%s[%d].%s", ruleDetail.Path, idx, ruleDetail.Field),
+				Fix:      duplicateFix(ruleDetail.Path, arr, dup.Indices, idx),
 			})
 		}
 	}
@@ -698,6 +778,41 @@ func (e *Executor) executeArrayNoDuplicatesCombine(rule *ValidationRule, filePat
 	return results
 }
 
+// caseFix 值與某個允許值只差在大小寫時（如 get 與 GET），返回改為該允許值的修正
+func caseFix(path, value string, allowed []string) *Fix {
+	for _, candidate := range allowed {
+		if strings.EqualFold(value, candidate) {
+			return &Fix{
+				Kind:        FixReplaceValue,
+				Description: fmt.Sprintf("將 %q 改為 %q", value, candidate),
+				Path:        path,
+				Value:       candidate,
+			}
+		}
+	}
+	return nil
+}
+
+// duplicateFix 返回移除重複項目的修正
+// 只有與第一次出現的項目完全相同時才會移除，保留第一個項目
+func duplicateFix(arrayPath string, arr []interface{}, indices []int, idx int) *Fix {
+	first := indices[0]
+	for _, i := range indices {
+		if i < first {
+			first = i
+		}
+	}
+	if idx == first || first >= len(arr) || idx >= len(arr) || !reflect.DeepEqual(arr[first], arr[idx]) {
+		return nil
+	}
+	return &Fix{
+		Kind:        FixRemoveItem,
+		Description: fmt.Sprintf("移除與 %s[%d] 相同的重複項目", arrayPath, first),
+		Path:        arrayPath,
+		Index:       idx,
+	}
+}
+
 // makeErrorResult 建立錯誤結果
 func makeErrorResult(rule *ValidationRule, filePath, path, message string) []*ValidationResult {
 	return []*ValidationResult{
@@ -976,6 +1091,12 @@ func (e *Executor) scanForTrailingWhitespace(data interface{}, currentPath strin
 				Severity: rule.Severity,
 				Message:  fmt.Sprintf("%s (%s有空白字元)", message, wsType),
 				Path:     currentPath,
+				Fix: &Fix{
+					Kind:        FixReplaceValue,
+					Description: "移除前後空白字元",
+					Path:        currentPath,
+					Value:       strings.TrimSpace(v),
+				},
 			})
 		}
 
@@ -1005,3 +1126,555 @@ func (e *Executor) checkTrailingWhitespace(value string) string {
 
 	return wsType
 }
+
+// executeConditional 執行條件規則
+// when 與 then 的路徑共用相同的萬用字元前綴時（如 routes[*]），會逐一對每個陣列項目判斷條件
+// 例如 when: routes[*].method == POST, then: routes[*].body_schema 必須存在
+func (e *Executor) executeConditional(rule *ValidationRule, filePath string) []*ValidationResult {
+	var ruleDetail ConditionalRule
+	if err := unmarshalRule(rule.Rule.RawRule, &ruleDetail); err != nil {
+		return makeErrorResult(rule, filePath, "", err.Error())
+	}
+
+	thenPath, _ := ruleDetail.Then["path"].(string)
+	scope := wildcardScope(ruleDetail.When.Path, thenPath)
+
+	// 沒有共同的萬用字元範圍，整份文件只判斷一次
+	if scope == "" {
+		matched, err := e.evaluateCondition(ruleDetail.When, ruleDetail.When.Path)
+		if err != nil {
+			return makeErrorResult(rule, filePath, ruleDetail.When.Path, err.Error())
+		}
+		if !matched {
+			return nil
+		}
+		return e.executeNested(rule, ruleDetail.Then, filePath)
+	}
+
+	// 逐一判斷每個陣列項目
+	var results []*ValidationResult
+	for _, item := range e.parser.ExpandWildcardPath(scope) {
+		whenPath := item.Path + strings.TrimPrefix(ruleDetail.When.Path, scope)
+		matched, err := e.evaluateCondition(ruleDetail.When, whenPath)
+		if err != nil {
+			return makeErrorResult(rule, filePath, whenPath, err.Error())
+		}
+		if !matched {
+			continue
+		}
+
+		// 將 then 的路徑替換為該項目的實際路徑
+		body := make(map[string]interface{}, len(ruleDetail.Then))
+		for k, v := range ruleDetail.Then {
+			body[k] = v
+		}
+		body["path"] = item.Path + strings.TrimPrefix(thenPath, scope)
+
+		results = append(results, e.executeNested(rule, body, filePath)...)
+	}
+	return results
+}
+
+// executeNested 以父規則的 ID、名稱與嚴重程度執行巢狀的規則內容
+func (e *Executor) executeNested(rule *ValidationRule, body map[string]interface{}, filePath string) []*ValidationResult {
+	nested, err := nestedRule(rule, body)
+	if err != nil {
+		return makeErrorResult(rule, filePath, "", err.Error())
+	}
+	return e.executeRule(nested, filePath)
+}
+
+// executeComposite 執行組合規則
+// all_of：任一分支未通過即失敗，回報各分支的問題
+// any_of：所有分支都未通過才失敗，回報一筆列出各分支原因的結果
+// none_of：任一分支通過即失敗，每個通過的分支回報一筆結果；不適用的分支（見 branchApplies）不算通過
+func (e *Executor) executeComposite(rule *ValidationRule, filePath string) []*ValidationResult {
+	var ruleDetail CompositeRule
+	if err := unmarshalRule(rule.Rule.RawRule, &ruleDetail); err != nil {
+		return makeErrorResult(rule, filePath, "", err.Error())
+	}
+
+	var results []*ValidationResult
+	var reasons []string
+	var firstFailure *ValidationResult
+
+	for i, body := range ruleDetail.Rules {
+		label := branchLabel(i, body)
+		branchResults := e.executeNested(rule, body, filePath)
+
+		switch rule.Rule.Type {
+		case RuleTypeAllOf:
+			for _, result := range branchResults {
+				result.Message = fmt.Sprintf("%s (%s 未通過: %s)", ruleDetail.Message, label, result.Message)
+				results = append(results, result)
+			}
+		case RuleTypeAnyOf:
+			if len(branchResults) == 0 {
+				return nil
+			}
+			if firstFailure == nil {
+				firstFailure = branchResults[0]
+			}
+			reasons = append(reasons, fmt.Sprintf("%s: %s", label, summarizeBranch(branchResults)))
+		case RuleTypeNoneOf:
+			if len(branchResults) == 0 && e.branchApplies(rule, body) {
+				results = append(results, branchPassedResult(rule, filePath, ruleDetail.Message, label, body))
+			}
+		}
+	}
+
+	if rule.Rule.Type == RuleTypeAnyOf && firstFailure != nil {
+		return []*ValidationResult{
+			{
+				File:          filePath,
+				RuleID:        rule.ID,
+				RuleName:      rule.Name,
+				Severity:      rule.Severity,
+				Message:       fmt.Sprintf("%s (所有分支都未通過: %s)", ruleDetail.Message, strings.Join(reasons, "; ")),
+				Path:          firstFailure.Path,
+				ExpectedValue: fmt.Sprintf("%d 個分支中至少一個通過", len(ruleDetail.Rules)),
+			},
+		}
+	}
+	return results
+}
+
+// executeNot 執行反向規則，分支通過時回報錯誤；分支不適用時（見 branchApplies）不回報
+func (e *Executor) executeNot(rule *ValidationRule, filePath string) []*ValidationResult {
+	var ruleDetail NotRule
+	if err := unmarshalRule(rule.Rule.RawRule, &ruleDetail); err != nil {
+		return makeErrorResult(rule, filePath, "", err.Error())
+	}
+
+	if len(e.executeNested(rule, ruleDetail.Rule, filePath)) > 0 || !e.branchApplies(rule, ruleDetail.Rule) {
+		return nil
+	}
+	label := branchLabel(-1, ruleDetail.Rule)
+	return []*ValidationResult{branchPassedResult(rule, filePath, ruleDetail.Message, label, ruleDetail.Rule)}
+}
+
+// branchApplies 檢查分支是否適用於文件，供 not 與 none_of 區分「通過」與「不適用」
+// 多數規則類型在 path 不存在時不回報問題，若直接視為通過，not 與 none_of 會對缺少欄位的文件回報錯誤：
+//   - required_field、required_fields 本身就在檢查欄位是否存在，一律適用
+//   - expression 引用的路徑不存在時不適用（設定 for_each 時，所有項目都不適用才算不適用）
+//   - 其他有 path 的分支，path 不存在（含 [*] 時沒有任何項目）即不適用
+//   - 沒有 path 的分支（如 conditional、組合規則）一律適用
+func (e *Executor) branchApplies(rule *ValidationRule, body map[string]interface{}) bool {
+	switch RuleType(fmt.Sprintf("%v", body["type"])) {
+	case RuleTypeRequiredField, RuleTypeRequiredFields:
+		return true
+	case RuleTypeExpression:
+		return e.expressionApplies(rule, body)
+	}
+
+	path, _ := body["path"].(string)
+	if path == "" {
+		return true
+	}
+	if strings.Contains(path, "[*]") {
+		return len(e.parser.ExpandWildcardPath(path)) > 0
+	}
+	return e.parser.HasField(path)
+}
+
+// expressionApplies 檢查表達式引用的路徑是否存在，求值時沒有 MissingError 即適用
+func (e *Executor) expressionApplies(rule *ValidationRule, body map[string]interface{}) bool {
+	source, _ := body["expression"].(string)
+	forEach, _ := body["for_each"].(string)
+	compiled, err := compileExpression(rule, source, forEach != "")
+	if err != nil {
+		return true
+	}
+
+	root, _ := e.parser.GetValue("")
+	applies := func(current interface{}) bool {
+		_, err := compiled.Eval(root, current)
+		_, missing := err.(*expr.MissingError)
+		return !missing
+	}
+	if forEach == "" {
+		return applies(nil)
+	}
+	for _, item := range e.parser.ExpandWildcardPath(forEach) {
+		if applies(item.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+// branchLabel 描述組合規則的分支，如 "分支 2 (required_field auth.jwt)"
+// index 小於 0 表示只有一個分支（not）
+func branchLabel(index int, body map[string]interface{}) string {
+	desc := fmt.Sprintf("%v", body["type"])
+	if path, ok := body["path"].(string); ok && path != "" {
+		desc += " " + path
+	} else if expression, ok := body["expression"].(string); ok {
+		desc += " " + expression
+	}
+	if index < 0 {
+		return fmt.Sprintf("分支 (%s)", desc)
+	}
+	return fmt.Sprintf("分支 %d (%s)", index+1, desc)
+}
+
+// summarizeBranch 以分支的第一個問題描述未通過的原因
+func summarizeBranch(results []*ValidationResult) string {
+	summary := results[0].Message
+	if results[0].Path != "" {
+		summary = fmt.Sprintf("%s [%s]", summary, results[0].Path)
+	}
+	if len(results) > 1 {
+		summary = fmt.Sprintf("%s 等 %d 個問題", summary, len(results))
+	}
+	return summary
+}
+
+// branchPassedResult 建立分支「不應通過卻通過」的結果，定位到分支的 path
+func branchPassedResult(rule *ValidationRule, filePath, message, label string, body map[string]interface{}) *ValidationResult {
+	path, _ := body["path"].(string)
+	if strings.Contains(path, "[*]") {
+		path = ""
+	}
+	return &ValidationResult{
+		File:          filePath,
+		RuleID:        rule.ID,
+		RuleName:      rule.Name,
+		Severity:      rule.Severity,
+		Message:       fmt.Sprintf("%s (%s 不應通過)", message, label),
+		Path:          path,
+		ExpectedValue: fmt.Sprintf("%s 未通過", label),
+	}
+}
+
+// nestedRule 將巢狀的規則內容（包含 type）轉換為 ValidationRule
+func nestedRule(parent *ValidationRule, body map[string]interface{}) (*ValidationRule, error) {
+	ruleType, ok := body["type"].(string)
+	if !ok || ruleType == "" {
+		return nil, fmt.Errorf("巢狀規則缺少 type 欄位")
+	}
+
+	rawRule := make(map[string]interface{}, len(body))
+	for k, v := range body {
+		if k != "type" {
+			rawRule[k] = v
+		}
+	}
+
+	nested := *parent
+	nested.Rule = Rule{Type: RuleType(ruleType), RawRule: rawRule}
+	return &nested, nil
+}
+
+// wildcardScope 找出兩個路徑共同的萬用字元前綴
+// 如: "routes[*].method" 與 "routes[*].body_schema" -> "routes[*]"
+func wildcardScope(a, b string) string {
+	if a == "" || b == "" {
+		return ""
+	}
+
+	partsA := strings.Split(a, ".")
+	partsB := strings.Split(b, ".")
+
+	scopeLen := 0
+	for i := 0; i < len(partsA) && i < len(partsB); i++ {
+		if partsA[i] != partsB[i] {
+			break
+		}
+		if strings.HasSuffix(partsA[i], "[*]") {
+			scopeLen = i + 1
+		}
+	}
+
+	return strings.Join(partsA[:scopeLen], ".")
+}
+
+// evaluateCondition 判斷條件是否成立
+// 路徑仍包含萬用字元時，任一個值符合即成立
+func (e *Executor) evaluateCondition(cond Condition, path string) (bool, error) {
+	var values []interface{}
+	for _, pathInfo := range e.parser.ExpandWildcardPath(path) {
+		values = append(values, pathInfo.Value)
+	}
+
+	if cond.Exists != nil {
+		return (len(values) > 0) == *cond.Exists, nil
+	}
+
+	var re *regexp.Regexp
+	if cond.Matches != "" {
+		var err error
+		re, err = regexp.Compile(cond.Matches)
+		if err != nil {
+			return false, fmt.Errorf("正則表達式錯誤: %v", err)
+		}
+	}
+
+	for _, value := range values {
+		strValue := fmt.Sprintf("%v", value)
+		switch {
+		case cond.Equals != nil:
+			if strValue == fmt.Sprintf("%v", cond.Equals) {
+				return true, nil
+			}
+		case re != nil:
+			if re.MatchString(strValue) {
+				return true, nil
+			}
+		case cond.In != nil:
+			for _, candidate := range cond.In {
+				if strValue == fmt.Sprintf("%v", candidate) {
+					return true, nil
+				}
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// maxRelatedFiles reference_exists 結果最多列出的來源檔案數量
+const maxRelatedFiles = 5
+
+// executeReferenceExists 執行跨檔案參考檢查
+// 從工作區中所有符合 source.file_patterns 的檔案收集 source.path 的值，
+// 再檢查目前檔案中 path 的每個值都能找到對應的定義
+func (e *Executor) executeReferenceExists(rule *ValidationRule, filePath string) []*ValidationResult {
+	var ruleDetail ReferenceExistsRule
+	if err := unmarshalRule(rule.Rule.RawRule, &ruleDetail); err != nil {
+		return makeErrorResult(rule, filePath, "", err.Error())
+	}
+
+	// 未設定工作區時只能參考目前的文件
+	var set *referenceSet
+	if e.workspace != nil {
+		set = e.workspace.collectValues(ruleDetail.Source.Path, ruleDetail.Source.FilePatterns)
+	} else {
+		set = &referenceSet{values: make(map[string][]string), files: []string{filePath}}
+		collectDocumentValues(e.parser, ruleDetail.Source.Path, filePath, set)
+	}
+
+	// 訊息只列出來源的檔名模式而不是實際的檔案：訊息是 baseline 指紋的一部分，不應隨來源檔案增減而改變
+	sourceDesc := "所有檔案"
+	if len(ruleDetail.Source.FilePatterns) > 0 {
+		sourceDesc = strings.Join(ruleDetail.Source.FilePatterns, ", ")
+	}
+	related := set.files
+	if len(related) > maxRelatedFiles {
+		related = related[:maxRelatedFiles]
+	}
+
+	return e.processPathWithWildcard(ruleDetail.Path, func(actualPath string, value interface{}) *ValidationResult {
+		// 只檢查純量值
+		switch value.(type) {
+		case map[string]interface{}, map[interface{}]interface{}, []interface{}:
+			return nil
+		}
+
+		strValue := fmt.Sprintf("%v", value)
+		if _, exists := set.values[strValue]; exists {
+			return nil
+		}
+
+		return &ValidationResult{
+			File:          filePath,
+			RuleID:        rule.ID,
+			RuleName:      rule.Name,
+			Severity:      rule.Severity,
+			Message:       fmt.Sprintf("%s (%q 在 %s 的 %s 中找不到)", ruleDetail.Message, strValue, sourceDesc, ruleDetail.Source.Path),
+			Path:          actualPath,
+			ActualValue:   strValue,
+			ExpectedValue: fmt.Sprintf("%s 中已定義的值", ruleDetail.Source.Path),
+			RelatedFiles:  related,
+		}
+	})
+}
+
+// expressionKey 返回表達式在 ValidationRule.expressions 中的 key
+func expressionKey(source string, allowCurrent bool) string {
+	return fmt.Sprintf("%t\x00%s", allowCurrent, source)
+}
+
+// compileExpression 取得規則載入時編譯的表達式
+// 未經 Validate 檢查的規則（如直接建立的結構）沒有編譯結果，此時才重新編譯
+func compileExpression(rule *ValidationRule, source string, allowCurrent bool) (*expr.Expression, error) {
+	if compiled, ok := rule.expressions[expressionKey(source, allowCurrent)]; ok {
+		return compiled, nil
+	}
+	return expr.Compile(source, allowCurrent)
+}
+
+// executeExpression 執行表達式規則
+// 表達式引用的路徑不存在時視為不適用，不回報錯誤（需要時可用 exists() 明確檢查）
+func (e *Executor) executeExpression(rule *ValidationRule, filePath string) []*ValidationResult {
+	var ruleDetail ExpressionRule
+	if err := unmarshalRule(rule.Rule.RawRule, &ruleDetail); err != nil {
+		return makeErrorResult(rule, filePath, "", err.Error())
+	}
+
+	compiled, err := compileExpression(rule, ruleDetail.Expression, ruleDetail.ForEach != "")
+	if err != nil {
+		return makeErrorResult(rule, filePath, "", fmt.Sprintf("expression 錯誤: %v", err))
+	}
+
+	root, _ := e.parser.GetValue("")
+	check := func(current interface{}, path string) *ValidationResult {
+		ok, err := compiled.Eval(root, current)
+		if _, missing := err.(*expr.MissingError); missing {
+			return nil
+		}
+		message := ruleDetail.Message
+		if err != nil {
+			message = fmt.Sprintf("%s (表達式無法求值: %v)", ruleDetail.Message, err)
+		} else if ok {
+			return nil
+		}
+		return &ValidationResult{
+			File:          filePath,
+			RuleID:        rule.ID,
+			RuleName:      rule.Name,
+			Severity:      rule.Severity,
+			Message:       message,
+			Path:          path,
+			ActualValue:   compiled.Describe(root, current),
+			ExpectedValue: ruleDetail.Expression,
+		}
+	}
+
+	if ruleDetail.ForEach == "" {
+		path := ruleDetail.Path
+		if path == "" {
+			if paths := compiled.Paths(); len(paths) > 0 {
+				path = paths[0]
+			}
+		}
+		if result := check(nil, path); result != nil {
+			return []*ValidationResult{result}
+		}
+		return nil
+	}
+
+	// 逐一對每個陣列項目求值，path 可以用 @ 指向項目中的欄位
+	var results []*ValidationResult
+	for _, item := range e.parser.ExpandWildcardPath(ruleDetail.ForEach) {
+		path := item.Path
+		if strings.HasPrefix(ruleDetail.Path, "@.") {
+			path += strings.TrimPrefix(ruleDetail.Path, "@")
+		} else if ruleDetail.Path != "" && ruleDetail.Path != "@" {
+			path = ruleDetail.Path
+		}
+		if result := check(item.Value, path); result != nil {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// executeArraySorted 執行陣列排序檢查
+// 支援萬用字元，例如 routes[*].middlewares 會檢查每個 route 的 middlewares 是否已排序
+func (e *Executor) executeArraySorted(rule *ValidationRule, filePath string) []*ValidationResult {
+	var ruleDetail ArraySortedRule
+	if err := unmarshalRule(rule.Rule.RawRule, &ruleDetail); err != nil {
+		return makeErrorResult(rule, filePath, "", err.Error())
+	}
+	desc := ruleDetail.Order == "desc"
+
+	return e.processPathWithWildcard(ruleDetail.Path, func(actualPath string, value interface{}) *ValidationResult {
+		arr, ok := value.([]interface{})
+		if !ok {
+			return nil
+		}
+
+		// 取得每個項目的排序鍵，缺少欄位的項目無法排序
+		keys := make([]interface{}, len(arr))
+		for i, item := range arr {
+			key := item
+			if ruleDetail.Field != "" {
+				itemMap, ok := item.(map[string]interface{})
+				if !ok {
+					return nil
+				}
+				if key, ok = itemMap[ruleDetail.Field]; !ok {
+					return nil
+				}
+			}
+			switch key.(type) {
+			case map[string]interface{}, map[interface{}]interface{}, []interface{}:
+				return nil
+			}
+			keys[i] = key
+		}
+
+		less := func(a, b int) bool {
+			if desc {
+				return compareSortKeys(keys[b], keys[a]) < 0
+			}
+			return compareSortKeys(keys[a], keys[b]) < 0
+		}
+
+		order := make([]int, len(arr))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool { return less(order[a], order[b]) })
+
+		for i, idx := range order {
+			if idx == i {
+				continue
+			}
+			// 找到第一個順序錯誤的位置
+			expected := make([]string, len(order))
+			for j, k := range order {
+				expected[j] = fmt.Sprintf("%v", keys[k])
+			}
+			return &ValidationResult{
+				File:          filePath,
+				RuleID:        rule.ID,
+				RuleName:      rule.Name,
+				Severity:      rule.Severity,
+				Message:       fmt.Sprintf("%s (第 %d 個項目 %v 的位置不正確)", ruleDetail.Message, i+1, keys[i]),
+				Path:          actualPath,
+				ActualValue:   fmt.Sprintf("%v", keys),
+				ExpectedValue: "[" + strings.Join(expected, " ") + "]",
+				Fix: &Fix{
+					Kind:        FixReorderItems,
+					Description: "重新排序陣列項目",
+					Path:        actualPath,
+					Order:       order,
+				},
+			}
+		}
+		return nil
+	})
+}
+
+// compareSortKeys 比較兩個排序鍵，兩者皆為數字時依數值比較，否則依字串比較
+func compareSortKeys(a, b interface{}) int {
+	af, aNum := toFloat(a)
+	bf, bNum := toFloat(b)
+	if aNum && bNum {
+		switch {
+		case af < bf:
+			return -1
+		case af > bf:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(fmt.Sprintf("%v", a), fmt.Sprintf("%v", b))
+}
+
+// toFloat 將數字轉換為 float64
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
@@ -0,0 +1,115 @@
+package rule
+
+import (
+	"config-validator/internal/parser"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// mustRule 從 YAML 建立並檢查規則
+func mustRule(t *testing.T, content string) *ValidationRule {
+	t.Helper()
+	var r ValidationRule
+	if err := yaml.Unmarshal([]byte(content), &r); err != nil {
+		t.Fatalf("解析規則失敗: %v", err)
+	}
+	if err := Validate(&r); err != nil {
+		t.Fatalf("規則不合法: %v", err)
+	}
+	return &r
+}
+
+// mustParse 解析 YAML 配置檔
+func mustParse(t *testing.T, content string) parser.Document {
+	t.Helper()
+	p := parser.NewYAMLParser()
+	if err := p.ParseBytes([]byte(content)); err != nil {
+		t.Fatalf("解析配置檔失敗: %v", err)
+	}
+	return p
+}
+
+func TestExecuteMultiDocument(t *testing.T) {
+	r := mustRule(t, `
+id: name-required
+name: 必須有名稱
+enabled: true
+severity: error
+targets:
+  file_patterns: ["*.yaml"]
+rule:
+  type: required_field
+  path: name
+  message: 缺少 name
+`)
+
+	tests := []struct {
+		name     string
+		content  string
+		wantDocs []int
+		wantPath []string
+	}{
+		{
+			name:     "略過空文件後保留原始序號",
+			content:  "port: 1\n---\n---\nport: 2\n",
+			wantDocs: []int{1, 3},
+			wantPath: []string{"[doc 1] name", "[doc 3] name"},
+		},
+		{
+			name:     "只有一個非空文件時不加前綴",
+			content:  "---\n---\nport: 2\n",
+			wantDocs: []int{0},
+			wantPath: []string{"name"},
+		},
+		{
+			name:     "只有缺少欄位的文件產生結果",
+			content:  "name: a\n---\nport: 2\n---\nname: c\n",
+			wantDocs: []int{2},
+			wantPath: []string{"[doc 2] name"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := NewExecutor(mustParse(t, tt.content)).Execute(r, "app.yaml")
+			if len(results) != len(tt.wantDocs) {
+				t.Fatalf("results = %d, want %d", len(results), len(tt.wantDocs))
+			}
+			for i, result := range results {
+				if result.Document != tt.wantDocs[i] || result.Path != tt.wantPath[i] {
+					t.Errorf("results[%d] = doc %d %q, want doc %d %q",
+						i, result.Document, result.Path, tt.wantDocs[i], tt.wantPath[i])
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteValueRangeStrings(t *testing.T) {
+	r := mustRule(t, `
+id: port-range
+name: 連接埠範圍
+enabled: true
+severity: error
+targets:
+  file_patterns: ["*"]
+rule:
+  type: value_range
+  path: "ports[*]"
+  min: 1
+  max: 65535
+  message: 連接埠超出範圍
+`)
+
+	// .env、.properties、INI 的值一律為字串，內容是數字時轉換後檢查，其他字串略過
+	results := NewExecutor(mustParse(t, `ports: [80, "8080", " 70000 ", "0", "http", 99999]`)).Execute(r, "app.yaml")
+	var got []string
+	for _, result := range results {
+		got = append(got, result.Path+"="+result.ActualValue)
+	}
+	want := []string{"ports[2]=70000", "ports[3]=0", "ports[5]=99999"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("results = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,208 @@
+package rule
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Layer 分層規則集合中的一層
+// 一層的規則 = 所有上層的規則（依 ID 合併）- 停用的規則，再以本層目錄中的規則依 ID 覆蓋
+type Layer struct {
+	Name     string   // 層名稱（產品名稱或共用規則目錄），用於錯誤訊息
+	Dir      string   // 本層的規則目錄，空字串表示沒有自己的規則
+	Parents  []*Layer // 繼承的上層，依宣告順序
+	Disabled []string // 停用的繼承規則 ID
+}
+
+// DependsOn 檢查本層或任何上層是否使用指定的規則目錄
+func (l *Layer) DependsOn(dir string) bool {
+	return l.dependsOn(dir, make(map[*Layer]bool))
+}
+
+func (l *Layer) dependsOn(dir string, visited map[*Layer]bool) bool {
+	if visited[l] {
+		return false
+	}
+	visited[l] = true
+
+	if l.Dir == dir {
+		return true
+	}
+	for _, parent := range l.Parents {
+		if parent.dependsOn(dir, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// RuleSource 讀取一個規則目錄中的規則
+type RuleSource func(dir string) ([]*ValidationRule, error)
+
+// LayeredLoader 分層規則載入器
+// 同一層只會載入一次，被多個產品共用的上層（如 rules/common）不會重複讀取
+type LayeredLoader struct {
+	source RuleSource
+	loaded map[*Layer][]*ValidationRule
+	stack  []*Layer // 目前載入中的層，用於偵測循環繼承
+}
+
+// NewLayeredLoader 建立從磁碟讀取規則目錄的分層規則載入器
+func NewLayeredLoader() *LayeredLoader {
+	return NewLayeredLoaderFrom(func(dir string) ([]*ValidationRule, error) {
+		return NewLoader(dir).LoadRules()
+	})
+}
+
+// NewLayeredLoaderFrom 建立以 source 讀取規則目錄的分層規則載入器（如 fs.FS 或記憶體中的規則）
+func NewLayeredLoaderFrom(source RuleSource) *LayeredLoader {
+	return &LayeredLoader{
+		source: source,
+		loaded: make(map[*Layer][]*ValidationRule),
+	}
+}
+
+// Load 載入指定層的規則
+func (l *LayeredLoader) Load(layer *Layer) ([]*ValidationRule, error) {
+	if rules, exists := l.loaded[layer]; exists {
+		return rules, nil
+	}
+
+	// 偵測循環繼承
+	for i, loading := range l.stack {
+		if loading == layer {
+			var names []string
+			for _, s := range l.stack[i:] {
+				names = append(names, s.Name)
+			}
+			names = append(names, layer.Name)
+			return nil, fmt.Errorf("規則繼承出現循環: %s", strings.Join(names, " -> "))
+		}
+	}
+	l.stack = append(l.stack, layer)
+	defer func() { l.stack = l.stack[:len(l.stack)-1] }()
+
+	var own []*ValidationRule
+	if layer.Dir != "" {
+		var err error
+		own, err = l.source(layer.Dir)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", layer.Name, err)
+		}
+	}
+
+	inherited, err := l.loadParents(layer, own)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := overrideRules(layer, inherited, own)
+	if err != nil {
+		return nil, err
+	}
+
+	l.loaded[layer] = rules
+	return rules, nil
+}
+
+// Invalidate 清除使用指定規則目錄的層（含繼承它的層）的快取，下次 Load 時重新讀取
+func (l *LayeredLoader) Invalidate(dir string) {
+	for layer := range l.loaded {
+		if layer.DependsOn(dir) {
+			delete(l.loaded, layer)
+		}
+	}
+}
+
+// loadParents 載入並合併所有上層的規則
+// 不同上層定義了相同 ID 的不同規則時，本層必須覆蓋或停用，否則視為不明確
+func (l *LayeredLoader) loadParents(layer *Layer, own []*ValidationRule) ([]*ValidationRule, error) {
+	resolved := make(map[string]bool)
+	for _, id := range layer.Disabled {
+		resolved[id] = true
+	}
+	for _, r := range own {
+		resolved[r.ID] = true
+	}
+
+	var inherited []*ValidationRule
+	byID := make(map[string]*ValidationRule)
+	origin := make(map[string]string)
+
+	for _, parent := range layer.Parents {
+		rules, err := l.Load(parent)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range rules {
+			existing, exists := byID[r.ID]
+			if !exists {
+				byID[r.ID] = r
+				origin[r.ID] = parent.Name
+				inherited = append(inherited, r)
+				continue
+			}
+			// 菱形繼承：兩個上層共用同一條規則，不算衝突
+			if existing == r {
+				continue
+			}
+			if !resolved[r.ID] {
+				return nil, fmt.Errorf("%s 的規則 %s 同時繼承自 %s 與 %s，請在 %s 中覆蓋或停用此規則",
+					layer.Name, r.ID, origin[r.ID], parent.Name, layer.Name)
+			}
+		}
+	}
+
+	return inherited, nil
+}
+
+// overrideRules 以本層規則覆蓋繼承的規則，並移除停用的規則
+func overrideRules(layer *Layer, inherited, own []*ValidationRule) ([]*ValidationRule, error) {
+	ownByID := make(map[string]*ValidationRule)
+	for _, r := range own {
+		if existing, exists := ownByID[r.ID]; exists {
+			return nil, fmt.Errorf("%s 中的規則 ID %s 重複: %s 與 %s", layer.Name, r.ID, existing.Source, r.Source)
+		}
+		ownByID[r.ID] = r
+	}
+
+	disabled := make(map[string]bool)
+	for _, id := range layer.Disabled {
+		disabled[id] = true
+	}
+	found := make(map[string]bool)
+
+	var rules []*ValidationRule
+	for _, r := range inherited {
+		if disabled[r.ID] {
+			found[r.ID] = true
+			continue
+		}
+		// 覆蓋的規則保留在原本的位置
+		if override, exists := ownByID[r.ID]; exists {
+			rules = append(rules, override)
+			delete(ownByID, r.ID)
+			continue
+		}
+		rules = append(rules, r)
+	}
+
+	for _, id := range layer.Disabled {
+		if !found[id] {
+			return nil, fmt.Errorf("%s 停用的規則 %s 不在繼承的規則中", layer.Name, id)
+		}
+		if _, exists := ownByID[id]; exists {
+			return nil, fmt.Errorf("%s 同時停用並覆蓋了規則 %s", layer.Name, id)
+		}
+	}
+
+	// 本層新增的規則
+	for _, r := range own {
+		if _, exists := ownByID[r.ID]; exists {
+			rules = append(rules, r)
+		}
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,142 @@
+package rule
+
+import (
+	"strings"
+	"testing"
+)
+
+// fakeSource 以記憶體中的規則作為規則目錄，並記錄讀取次數
+type fakeSource struct {
+	dirs  map[string][]*ValidationRule
+	reads map[string]int
+}
+
+func newFakeSource(dirs map[string][]string) *fakeSource {
+	s := &fakeSource{dirs: make(map[string][]*ValidationRule), reads: make(map[string]int)}
+	for dir, ids := range dirs {
+		for _, id := range ids {
+			s.dirs[dir] = append(s.dirs[dir], &ValidationRule{ID: id, Source: dir + "/" + id + ".yaml"})
+		}
+	}
+	return s
+}
+
+func (s *fakeSource) load(dir string) ([]*ValidationRule, error) {
+	s.reads[dir]++
+	return s.dirs[dir], nil
+}
+
+// ruleSummary 以 "ID@目錄" 列出規則
+func ruleSummary(rules []*ValidationRule) string {
+	var parts []string
+	for _, r := range rules {
+		parts = append(parts, r.ID+"@"+strings.Split(r.Source, "/")[0])
+	}
+	return strings.Join(parts, ",")
+}
+
+func TestLayeredLoader(t *testing.T) {
+	source := newFakeSource(map[string][]string{
+		"common": {"c1", "c2"},
+		"base":   {"b1", "b2", "c2"},
+		"api":    {"a1", "b2"},
+	})
+	common := &Layer{Name: "common", Dir: "common"}
+	base := &Layer{Name: "base", Dir: "base", Parents: []*Layer{common}}
+	api := &Layer{Name: "api", Dir: "api", Parents: []*Layer{base}, Disabled: []string{"b1"}}
+	web := &Layer{Name: "web", Parents: []*Layer{common}}
+
+	loader := NewLayeredLoaderFrom(source.load)
+	tests := []struct {
+		layer *Layer
+		want  string
+	}{
+		// 覆蓋的規則保留在繼承的位置，新增的規則在最後
+		{base, "c1@common,c2@base,b1@base,b2@base"},
+		{api, "c1@common,c2@base,b2@api,a1@api"},
+		{web, "c1@common,c2@common"},
+	}
+	for _, tt := range tests {
+		rules, err := loader.Load(tt.layer)
+		if err != nil {
+			t.Fatalf("Load(%s): %v", tt.layer.Name, err)
+		}
+		if got := ruleSummary(rules); got != tt.want {
+			t.Errorf("Load(%s) = %s, want %s", tt.layer.Name, got, tt.want)
+		}
+	}
+	// 共用的上層只讀取一次
+	if source.reads["common"] != 1 {
+		t.Errorf("common 讀取 %d 次, want 1", source.reads["common"])
+	}
+
+	// Invalidate 清除使用該目錄的層，其他層保留快取
+	loader.Invalidate("base")
+	for _, layer := range []*Layer{api, web, base} {
+		if _, err := loader.Load(layer); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if source.reads["base"] != 2 || source.reads["api"] != 2 || source.reads["common"] != 1 {
+		t.Errorf("Invalidate 後讀取次數 = %v", source.reads)
+	}
+}
+
+func TestLayeredLoaderErrors(t *testing.T) {
+	source := newFakeSource(map[string][]string{
+		"x": {"r1"},
+		"y": {"r1"},
+		"z": {"r2"},
+	})
+	x := &Layer{Name: "x", Dir: "x"}
+	y := &Layer{Name: "y", Dir: "y"}
+	z := &Layer{Name: "z", Dir: "z"}
+
+	cyclic := &Layer{Name: "a"}
+	cyclic.Parents = []*Layer{{Name: "b", Parents: []*Layer{cyclic}}}
+
+	tests := []struct {
+		name    string
+		layer   *Layer
+		wantErr string
+	}{
+		{"上層衝突", &Layer{Name: "p", Parents: []*Layer{x, y}}, "規則 r1 同時繼承自 x 與 y"},
+		{"停用不存在的規則", &Layer{Name: "p", Parents: []*Layer{z}, Disabled: []string{"r9"}}, "停用的規則 r9 不在繼承的規則中"},
+		{"同時停用並覆蓋", &Layer{Name: "p", Dir: "x", Parents: []*Layer{x}, Disabled: []string{"r1"}}, "同時停用並覆蓋了規則 r1"},
+		{"循環", cyclic, "規則繼承出現循環: a -> b -> a"},
+	}
+	for _, tt := range tests {
+		_, err := NewLayeredLoaderFrom(source.load).Load(tt.layer)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: Load() = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+
+	// 停用衝突的規則即可解決
+	rules, err := NewLayeredLoaderFrom(source.load).Load(&Layer{Name: "p", Parents: []*Layer{x, y}, Disabled: []string{"r1"}})
+	if err != nil || len(rules) != 0 {
+		t.Errorf("停用衝突的規則後 Load() = %v, %v", rules, err)
+	}
+}
+
+func TestLayerDependsOn(t *testing.T) {
+	common := &Layer{Name: "common", Dir: "rules/common"}
+	base := &Layer{Name: "base", Dir: "rules/base", Parents: []*Layer{common}}
+	api := &Layer{Name: "api", Dir: "rules/api", Parents: []*Layer{base, common}}
+
+	tests := []struct {
+		layer *Layer
+		dir   string
+		want  bool
+	}{
+		{api, "rules/api", true},
+		{api, "rules/common", true},
+		{base, "rules/api", false},
+		{common, "rules/base", false},
+	}
+	for _, tt := range tests {
+		if got := tt.layer.DependsOn(tt.dir); got != tt.want {
+			t.Errorf("%s.DependsOn(%q) = %v, want %v", tt.layer.Name, tt.dir, got, tt.want)
+		}
+	}
+}
@@ -1,7 +1,11 @@
 package rule
 
 import (
+	"config-validator/internal/expr"
+	"config-validator/internal/glob"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -11,9 +15,13 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+// TestDirSuffix 規則測試 fixture 目錄的後綴，如 rules/api/api-005.test/
+const TestDirSuffix = ".test"
+
 // Loader 規則載入器
 type Loader struct {
 	rulesDir string
+	fsys     fs.FS // 為 nil 時從磁碟讀取
 }
 
 // NewLoader 建立新的規則載入器
@@ -23,29 +31,41 @@ func NewLoader(rulesDir string) *Loader {
 	}
 }
 
+// NewFSLoader 建立從 fs.FS（如 embed.FS）讀取規則的載入器
+// rulesDir 為 fs.FS 中的路徑，使用 / 分隔
+func NewFSLoader(fsys fs.FS, rulesDir string) *Loader {
+	return &Loader{
+		rulesDir: rulesDir,
+		fsys:     fsys,
+	}
+}
+
 // LoadRules 載入所有規則（遞迴載入子目錄）
 func (l *Loader) LoadRules() ([]*ValidationRule, error) {
 	var rules []*ValidationRule
 
 	// 檢查規則目錄是否存在
-	if _, err := os.Stat(l.rulesDir); os.IsNotExist(err) {
+	if _, err := l.stat(l.rulesDir); errors.Is(err, fs.ErrNotExist) {
 		return nil, fmt.Errorf("規則目錄不存在: %s", l.rulesDir)
 	}
 
 	// 收集所有規則檔案路徑（遞迴）
 	var ruleFiles []string
-	err := filepath.Walk(l.rulesDir, func(path string, info os.FileInfo, err error) error {
+	err := l.walk(l.rulesDir, func(path string, entry fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
-		// 跳過目錄
-		if info.IsDir() {
+		// 跳過目錄，規則測試的 fixture 目錄（如 api-005.test）不是規則
+		if entry.IsDir() {
+			if path != l.rulesDir && strings.HasSuffix(entry.Name(), TestDirSuffix) {
+				return fs.SkipDir
+			}
 			return nil
 		}
 
 		// 只處理 YAML 檔案
-		if strings.HasSuffix(info.Name(), ".yaml") || strings.HasSuffix(info.Name(), ".yml") {
+		if strings.HasSuffix(entry.Name(), ".yaml") || strings.HasSuffix(entry.Name(), ".yml") {
 			ruleFiles = append(ruleFiles, path)
 		}
 
@@ -77,7 +97,7 @@ func (l *Loader) LoadRules() ([]*ValidationRule, error) {
 
 // loadRuleFile 載入單個規則檔案
 func (l *Loader) loadRuleFile(filePath string) (*ValidationRule, error) {
-	content, err := os.ReadFile(filePath)
+	content, err := l.readFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("讀取檔案失敗: %w", err)
 	}
@@ -92,9 +112,37 @@ func (l *Loader) loadRuleFile(filePath string) (*ValidationRule, error) {
 		return nil, err
 	}
 
+	rule.Source = filePath
 	return &rule, nil
 }
 
+// stat、walk、readFile 依載入來源讀取磁碟或 fs.FS
+func (l *Loader) stat(path string) (fs.FileInfo, error) {
+	if l.fsys == nil {
+		return os.Stat(path)
+	}
+	return fs.Stat(l.fsys, path)
+}
+
+func (l *Loader) walk(root string, fn fs.WalkDirFunc) error {
+	if l.fsys == nil {
+		return filepath.WalkDir(root, fn)
+	}
+	return fs.WalkDir(l.fsys, root, fn)
+}
+
+func (l *Loader) readFile(path string) ([]byte, error) {
+	if l.fsys == nil {
+		return os.ReadFile(path)
+	}
+	return fs.ReadFile(l.fsys, path)
+}
+
+// Validate 檢查規則的必要欄位與規則類型的設定，供不是從檔案載入的規則使用
+func Validate(rule *ValidationRule) error {
+	return NewLoader("").validateRule(rule)
+}
+
 // validateRule 驗證規則的完整性
 func (l *Loader) validateRule(rule *ValidationRule) error {
 	if rule.ID == "" {
@@ -109,11 +157,17 @@ func (l *Loader) validateRule(rule *ValidationRule) error {
 	if len(rule.Targets.FilePatterns) == 0 {
 		return fmt.Errorf("規則 %s 缺少 file_patterns", rule.ID)
 	}
+	for _, pattern := range rule.Targets.FilePatterns {
+		if err := glob.Validate(pattern); err != nil {
+			return fmt.Errorf("規則 %s 的 file_patterns 錯誤: %w", rule.ID, err)
+		}
+	}
 	if rule.Rule.Type == "" {
 		return fmt.Errorf("規則 %s 缺少 rule.type", rule.ID)
 	}
 
-	// 驗證規則類型的詳細配置
+	// 驗證規則類型的詳細配置；巢狀規則複製自 rule，編譯的表達式一併保存在 rule 上
+	rule.expressions = make(map[string]*expr.Expression)
 	if err := l.validateRuleDetails(rule); err != nil {
 		return fmt.Errorf("規則 %s 配置錯誤: %w", rule.ID, err)
 	}
@@ -148,6 +202,18 @@ func (l *Loader) validateRuleDetails(rule *ValidationRule) error {
 		return validateContainsKeywordsRule(rule.Rule.RawRule)
 	case RuleTypeNoTrailingWhitespace:
 		return validateNoTrailingWhitespaceRule(rule.Rule.RawRule)
+	case RuleTypeConditional:
+		return l.validateConditionalRule(rule)
+	case RuleTypeReferenceExists:
+		return validateReferenceExistsRule(rule.Rule.RawRule)
+	case RuleTypeExpression:
+		return validateExpressionRule(rule)
+	case RuleTypeAllOf, RuleTypeAnyOf, RuleTypeNoneOf:
+		return l.validateCompositeRule(rule)
+	case RuleTypeNot:
+		return l.validateNotRule(rule)
+	case RuleTypeArraySorted:
+		return validateArraySortedRule(rule.Rule.RawRule)
 	default:
 		return fmt.Errorf("不支援的規則類型: %s", rule.Rule.Type)
 	}
@@ -396,38 +462,219 @@ func validateNoTrailingWhitespaceRule(rawRule map[string]interface{}) error {
 	return nil
 }
 
-// MatchRules 根據檔案路徑匹配適用的規則
-func MatchRules(rules []*ValidationRule, filePath string) []*ValidationRule {
-	var matched []*ValidationRule
+// validateArraySortedRule 驗證 array_sorted 規則
+func validateArraySortedRule(rawRule map[string]interface{}) error {
+	path, ok := rawRule["path"].(string)
+	if !ok || path == "" {
+		return fmt.Errorf("array_sorted 規則必須包含 path 欄位")
+	}
+	if order, exists := rawRule["order"]; exists && order != "asc" && order != "desc" {
+		return fmt.Errorf("array_sorted 的 order 必須是 asc 或 desc")
+	}
+	message, ok := rawRule["message"].(string)
+	if !ok || message == "" {
+		return fmt.Errorf("array_sorted 規則必須包含 message 欄位")
+	}
+	return nil
+}
 
-	fileName := filepath.Base(filePath)
+// validateConditionalRule 驗證 conditional 規則
+// then 的內容會以對應規則類型的檢查方式一併驗證
+func (l *Loader) validateConditionalRule(rule *ValidationRule) error {
+	when, ok := rule.Rule.RawRule["when"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("conditional 規則必須包含 when 欄位")
+	}
+	if err := validateCondition(when); err != nil {
+		return err
+	}
 
-	for _, rule := range rules {
-		if matchFilePatterns(fileName, rule.Targets.FilePatterns) {
-			matched = append(matched, rule)
+	then, ok := rule.Rule.RawRule["then"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("conditional 規則必須包含 then 欄位")
+	}
+	if err := l.validateNestedBody(rule, then); err != nil {
+		return fmt.Errorf("then: %w", err)
+	}
+	return nil
+}
+
+// validateCondition 驗證條件判斷（when）
+func validateCondition(when map[string]interface{}) error {
+	path, ok := when["path"].(string)
+	if !ok || path == "" {
+		return fmt.Errorf("when 必須包含 path 欄位")
+	}
+
+	predicates := 0
+	if _, ok := when["exists"]; ok {
+		if _, isBool := when["exists"].(bool); !isBool {
+			return fmt.Errorf("when.exists 必須是布林值")
+		}
+		predicates++
+	}
+	if equals, ok := when["equals"]; ok {
+		// 欄位不存在時沒有值可以比較，null 永遠不會成立
+		if equals == nil {
+			return fmt.Errorf("when.equals 不能是 null，判斷欄位是否存在請使用 exists")
+		}
+		predicates++
+	}
+	if matches, ok := when["matches"]; ok {
+		pattern, isString := matches.(string)
+		if !isString || pattern == "" {
+			return fmt.Errorf("when.matches 必須是非空字串")
+		}
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("when.matches 正則表達式無效: %w", err)
+		}
+		predicates++
+	}
+	if in, ok := when["in"]; ok {
+		if list, isList := in.([]interface{}); !isList || len(list) == 0 {
+			return fmt.Errorf("when.in 必須是非空陣列")
 		}
+		predicates++
 	}
 
-	return matched
+	if predicates != 1 {
+		return fmt.Errorf("when 必須包含 exists、equals、matches、in 其中一個條件")
+	}
+	return nil
 }
 
-// matchFilePatterns 檢查檔案名稱是否匹配任一 pattern
-func matchFilePatterns(fileName string, patterns []string) bool {
-	for _, pattern := range patterns {
-		if matched, _ := filepath.Match(pattern, fileName); matched {
-			return true
+// validateCompositeRule 驗證 all_of、any_of、none_of 規則
+// 每個分支都會以對應規則類型的檢查方式一併驗證
+func (l *Loader) validateCompositeRule(rule *ValidationRule) error {
+	branches, ok := rule.Rule.RawRule["rules"].([]interface{})
+	if !ok || len(branches) == 0 {
+		return fmt.Errorf("%s 規則必須包含非空的 rules 欄位", rule.Rule.Type)
+	}
+	for i, branch := range branches {
+		body, ok := branch.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("rules[%d]: 必須是規則內容", i)
 		}
+		if err := l.validateNestedBody(rule, body); err != nil {
+			return fmt.Errorf("rules[%d]: %w", i, err)
+		}
+	}
+	message, ok := rule.Rule.RawRule["message"].(string)
+	if !ok || message == "" {
+		return fmt.Errorf("%s 規則必須包含 message 欄位", rule.Rule.Type)
+	}
+	return nil
+}
+
+// validateNotRule 驗證 not 規則
+func (l *Loader) validateNotRule(rule *ValidationRule) error {
+	body, ok := rule.Rule.RawRule["rule"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("not 規則必須包含 rule 欄位")
+	}
+	if err := l.validateNestedBody(rule, body); err != nil {
+		return fmt.Errorf("rule: %w", err)
+	}
+	message, ok := rule.Rule.RawRule["message"].(string)
+	if !ok || message == "" {
+		return fmt.Errorf("not 規則必須包含 message 欄位")
+	}
+	return nil
+}
+
+// validateNestedBody 以對應規則類型的檢查方式驗證巢狀的規則內容
+func (l *Loader) validateNestedBody(parent *ValidationRule, body map[string]interface{}) error {
+	nested, err := nestedRule(parent, body)
+	if err != nil {
+		return err
+	}
+	return l.validateRuleDetails(nested)
+}
+
+// validateReferenceExistsRule 驗證 reference_exists 規則
+func validateReferenceExistsRule(rawRule map[string]interface{}) error {
+	path, ok := rawRule["path"].(string)
+	if !ok || path == "" {
+		return fmt.Errorf("reference_exists 規則必須包含 path 欄位")
+	}
+	source, ok := rawRule["source"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("reference_exists 規則必須包含 source 欄位")
+	}
+	sourcePath, ok := source["path"].(string)
+	if !ok || sourcePath == "" {
+		return fmt.Errorf("reference_exists 規則必須包含 source.path 欄位")
+	}
+	if patterns, exists := source["file_patterns"]; exists {
+		list, ok := patterns.([]interface{})
+		if !ok {
+			return fmt.Errorf("source.file_patterns 必須是陣列")
+		}
+		for _, pattern := range list {
+			if err := glob.Validate(fmt.Sprintf("%v", pattern)); err != nil {
+				return fmt.Errorf("source.file_patterns 錯誤: %w", err)
+			}
+		}
+	}
+	message, ok := rawRule["message"].(string)
+	if !ok || message == "" {
+		return fmt.Errorf("reference_exists 規則必須包含 message 欄位")
+	}
+	return nil
+}
+
+// validateExpressionRule 驗證 expression 規則
+// 表達式在此完整解析與型別檢查，錯誤的表達式不會等到驗證檔案時才發現；編譯結果保存在規則上供執行時使用
+func validateExpressionRule(rule *ValidationRule) error {
+	rawRule := rule.Rule.RawRule
+	source, ok := rawRule["expression"].(string)
+	if !ok || source == "" {
+		return fmt.Errorf("expression 規則必須包含 expression 欄位")
+	}
+	forEach, _ := rawRule["for_each"].(string)
+	if _, exists := rawRule["for_each"]; exists && !strings.Contains(forEach, "[*]") {
+		return fmt.Errorf("for_each 必須是包含 [*] 的陣列路徑")
+	}
+	compiled, err := expr.Compile(source, forEach != "")
+	if err != nil {
+		return fmt.Errorf("expression 錯誤: %w", err)
+	}
+	if rule.expressions != nil {
+		rule.expressions[expressionKey(source, forEach != "")] = compiled
+	}
+	message, ok := rawRule["message"].(string)
+	if !ok || message == "" {
+		return fmt.Errorf("expression 規則必須包含 message 欄位")
+	}
+	return nil
+}
 
-		// 支援 ** 通配符（簡化版本，只檢查檔案名稱）
-		if strings.Contains(pattern, "**") {
-			// 提取實際的檔案 pattern
-			parts := strings.Split(pattern, "/")
-			filePattern := parts[len(parts)-1]
-			if matched, _ := filepath.Match(filePattern, fileName); matched {
-				return true
+// MergeRules 合併多個規則集合，依規則 ID 去重，先出現的規則優先
+func MergeRules(ruleSets ...[]*ValidationRule) []*ValidationRule {
+	var merged []*ValidationRule
+	seen := make(map[string]bool)
+	for _, rules := range ruleSets {
+		for _, rule := range rules {
+			if seen[rule.ID] {
+				continue
 			}
+			seen[rule.ID] = true
+			merged = append(merged, rule)
+		}
+	}
+	return merged
+}
+
+// MatchRules 根據檔案路徑匹配適用的規則
+// filePath 應為相對於目前目錄的路徑（見 pipeline.MatchPath）
+func MatchRules(rules []*ValidationRule, filePath string) []*ValidationRule {
+	var matched []*ValidationRule
+
+	for _, rule := range rules {
+		if glob.MatchAny(rule.Targets.FilePatterns, filePath) {
+			matched = append(matched, rule)
 		}
 	}
 
-	return false
+	return matched
 }
@@ -0,0 +1,215 @@
+package rule
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestValidateConditionalRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		when    string
+		wantErr string // 空字串表示應通過
+	}{
+		{"exists", "{path: a, exists: true}", ""},
+		{"equals", "{path: a, equals: prod}", ""},
+		{"equals false", "{path: a, equals: false}", ""},
+		{"equals 0", "{path: a, equals: 0}", ""},
+		{"matches", "{path: a, matches: '^v\\d+$'}", ""},
+		{"in", "{path: a, in: [GET, POST]}", ""},
+		{"缺少 path", "{equals: 1}", "when 必須包含 path"},
+		{"equals null", "{path: a, equals: null}", "when.equals 不能是 null"},
+		{"equals ~", "{path: a, equals: ~}", "when.equals 不能是 null"},
+		{"exists 非布林", "{path: a, exists: yes please}", "when.exists 必須是布林值"},
+		{"matches 無效", "{path: a, matches: '['}", "正則表達式無效"},
+		{"in 空陣列", "{path: a, in: []}", "when.in 必須是非空陣列"},
+		{"沒有條件", "{path: a}", "其中一個條件"},
+		{"多個條件", "{path: a, exists: true, equals: 1}", "其中一個條件"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := `
+id: cond
+name: 條件
+enabled: true
+severity: error
+targets:
+  file_patterns: ["*.yaml"]
+rule:
+  type: conditional
+  when: ` + tt.when + `
+  then:
+    type: required_field
+    path: b
+    message: 缺少 b
+`
+			var r ValidationRule
+			if err := yaml.Unmarshal([]byte(content), &r); err != nil {
+				t.Fatalf("解析規則失敗: %v", err)
+			}
+			err := Validate(&r)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() = %v, want 包含 %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecuteConditional(t *testing.T) {
+	r := mustRule(t, `
+id: body-schema
+name: POST 需要 body_schema
+enabled: true
+severity: error
+targets:
+  file_patterns: ["*.yaml"]
+rule:
+  type: conditional
+  when:
+    path: routes[*].method
+    in: [POST, PUT]
+  then:
+    type: required_field
+    path: routes[*].body_schema
+    message: 缺少 body_schema
+`)
+	doc := mustParse(t, `
+routes:
+  - method: GET
+  - method: POST
+    body_schema: create
+  - method: PUT
+`)
+	results := NewExecutor(doc).Execute(r, "api.yaml")
+	if len(results) != 1 || results[0].Path != "routes[2].body_schema" {
+		t.Fatalf("results = %+v, want routes[2].body_schema", results)
+	}
+
+	equals := mustRule(t, `
+id: ssl-cert
+name: 啟用 SSL 需要憑證
+enabled: true
+severity: error
+targets:
+  file_patterns: ["*.yaml"]
+rule:
+  type: conditional
+  when:
+    path: ssl.enabled
+    equals: true
+  then:
+    type: required_field
+    path: ssl.cert
+    message: 缺少 ssl.cert
+`)
+	for content, want := range map[string]int{
+		"ssl:\n  enabled: true\n":  1,
+		"ssl:\n  enabled: false\n": 0,
+		"port: 80\n":               0,
+	} {
+		if got := len(NewExecutor(mustParse(t, content)).Execute(equals, "db.yaml")); got != want {
+			t.Errorf("%q: results = %d, want %d", content, got, want)
+		}
+	}
+}
+
+func TestMergeRules(t *testing.T) {
+	a1 := &ValidationRule{ID: "a", Product: "api"}
+	a2 := &ValidationRule{ID: "a", Product: "security"}
+	b := &ValidationRule{ID: "b", Product: "security"}
+
+	merged := MergeRules([]*ValidationRule{a1}, []*ValidationRule{a2, b}, nil)
+	if len(merged) != 2 || merged[0] != a1 || merged[1] != b {
+		t.Errorf("MergeRules() = %v, want [a1 b]（先出現的規則優先）", merged)
+	}
+}
+
+func TestValidateCompilesExpressions(t *testing.T) {
+	r := mustRule(t, `
+id: pool
+name: 連接池設定
+enabled: true
+severity: error
+targets:
+  file_patterns: ["*.yaml"]
+rule:
+  type: any_of
+  message: 連接池設定錯誤
+  rules:
+    - type: expression
+      expression: pool.min <= pool.max
+      message: min 不可大於 max
+    - type: expression
+      expression: "@.size > 0"
+      for_each: "pool.shards[*]"
+      message: shard 大小必須大於 0
+`)
+
+	// 巢狀規則中的表達式在載入時編譯並保存在規則上，執行時不再重新解析
+	if len(r.expressions) != 2 {
+		t.Fatalf("expressions = %d, want 2", len(r.expressions))
+	}
+	if _, ok := r.expressions[expressionKey("@.size > 0", true)]; !ok {
+		t.Errorf("expressions 缺少 for_each 的表達式")
+	}
+
+	// 複製的規則（如標記產品）共用編譯結果
+	tagged := *r
+	tagged.Product = "api"
+	results := NewExecutor(mustParse(t, "pool:\n  min: 10\n  max: 5\n  shards: [{size: 0}]\n")).Execute(&tagged, "db.yaml")
+	if len(results) != 1 {
+		t.Fatalf("results = %d, want 1", len(results))
+	}
+
+	// 沒有經過 Validate 的規則在執行時編譯
+	direct := &ValidationRule{
+		ID:   "direct",
+		Rule: Rule{Type: RuleTypeExpression, RawRule: map[string]interface{}{"expression": "port > 0", "message": "port 必須大於 0"}},
+	}
+	if results := NewExecutor(mustParse(t, "port: 0\n")).Execute(direct, "app.yaml"); len(results) != 1 || results[0].Message != "port 必須大於 0" {
+		t.Errorf("未檢查的規則 results = %v, want 1 筆", results)
+	}
+}
+
+func TestClone(t *testing.T) {
+	r := mustRule(t, `
+id: routes
+name: 路由設定
+enabled: true
+severity: error
+targets:
+  file_patterns: ["*.yaml"]
+rule:
+  type: all_of
+  message: 路由設定錯誤
+  rules:
+    - type: required_fields
+      path: server
+      fields: [host, port]
+      message: 缺少欄位
+`)
+	cloned := r.Clone()
+
+	// 修改複製的規則不影響原本的規則
+	cloned.Targets.FilePatterns[0] = "*.json"
+	branch := cloned.Rule.RawRule["rules"].([]interface{})[0].(map[string]interface{})
+	branch["path"] = "client"
+	branch["fields"].([]interface{})[0] = "url"
+	cloned.Rule.RawRule["message"] = "changed"
+
+	if r.Targets.FilePatterns[0] != "*.yaml" {
+		t.Errorf("FilePatterns = %v, 不應被修改", r.Targets.FilePatterns)
+	}
+	original := r.Rule.RawRule["rules"].([]interface{})[0].(map[string]interface{})
+	if original["path"] != "server" || original["fields"].([]interface{})[0] != "host" || r.Rule.RawRule["message"] != "路由設定錯誤" {
+		t.Errorf("RawRule = %v, 不應被修改", r.Rule.RawRule)
+	}
+}
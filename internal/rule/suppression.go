@@ -0,0 +1,68 @@
+package rule
+
+import (
+	"config-validator/internal/parser"
+	"fmt"
+	"strings"
+)
+
+// StaleSuppressionRuleID 過期抑制註解的規則 ID
+const StaleSuppressionRuleID = "stale-suppression"
+
+// ApplySuppressions 套用配置檔中的抑制註解
+// 符合的結果會被標記為 Suppressed（仍保留在結果中以便統計），
+// 沒有抑制任何結果的註解會以 warning 回報為過期
+func ApplySuppressions(p parser.Document, filePath string, results []*ValidationResult) []*ValidationResult {
+	suppressions := p.Suppressions()
+	if len(suppressions) == 0 {
+		return results
+	}
+
+	// 記錄每個註解中每個規則 ID 是否有被使用
+	used := make(map[*parser.Suppression]map[string]bool)
+	for _, s := range suppressions {
+		used[s] = make(map[string]bool)
+	}
+
+	for _, result := range results {
+		for _, s := range suppressions {
+			if !s.Matches(result.RuleID, result.Line) {
+				continue
+			}
+			result.Suppressed = true
+			result.SuppressionReason = s.Reason
+			used[s][result.RuleID] = true
+			break
+		}
+	}
+
+	for _, s := range suppressions {
+		var stale []string
+		if len(s.RuleIDs) == 0 {
+			if len(used[s]) == 0 {
+				stale = append(stale, "*")
+			}
+		} else {
+			for _, id := range s.RuleIDs {
+				if !used[s][id] {
+					stale = append(stale, id)
+				}
+			}
+		}
+		if len(stale) == 0 {
+			continue
+		}
+
+		results = append(results, &ValidationResult{
+			File:     filePath,
+			RuleID:   StaleSuppressionRuleID,
+			RuleName: "過期的抑制註解",
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("抑制註解沒有對應任何問題: %s", strings.Join(stale, ", ")),
+			Line:     s.Line,
+			Column:   1,
+		})
+	}
+
+	return results
+}
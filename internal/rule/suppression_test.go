@@ -0,0 +1,45 @@
+package rule
+
+import "testing"
+
+func TestApplySuppressions(t *testing.T) {
+	doc := mustParse(t, `server:
+  port: 80 # validator:ignore port-range reason="內部服務"
+  host: "" # validator:ignore host-required,unused-rule
+  # validator:ignore
+  name: x
+`)
+	results := []*ValidationResult{
+		{RuleID: "port-range", Line: 2},
+		{RuleID: "host-required", Line: 3},
+		{RuleID: "port-range", Line: 5}, // 不在範圍內
+	}
+
+	results = ApplySuppressions(doc, "app.yaml", results)
+	if !results[0].Suppressed || results[0].SuppressionReason != "內部服務" {
+		t.Errorf("results[0] = %+v, want suppressed", results[0])
+	}
+	if !results[1].Suppressed {
+		t.Errorf("results[1] = %+v, want suppressed", results[1])
+	}
+	// 獨立一行的註解作用於下一行，抑制所有規則
+	if !results[2].Suppressed {
+		t.Errorf("results[2] = %+v, want suppressed", results[2])
+	}
+
+	// 沒有使用到的規則 ID 回報為過期
+	if len(results) != 4 {
+		t.Fatalf("results = %d, want 4", len(results))
+	}
+	stale := results[3]
+	if stale.RuleID != StaleSuppressionRuleID || stale.Line != 3 || stale.Severity != SeverityWarning ||
+		stale.Message != "抑制註解沒有對應任何問題: unused-rule" {
+		t.Errorf("stale = %+v", stale)
+	}
+
+	// 沒有抑制任何結果的萬用註解也是過期
+	results = ApplySuppressions(mustParse(t, "# validator:ignore\nname: x\n"), "app.yaml", nil)
+	if len(results) != 1 || results[0].Message != "抑制註解沒有對應任何問題: *" {
+		t.Errorf("results = %+v", results)
+	}
+}
@@ -1,5 +1,7 @@
 package rule
 
+import "config-validator/internal/expr"
+
 // Severity 定義規則的嚴重程度
 type Severity string
 
@@ -25,6 +27,14 @@ const (
 	RuleTypeHashedValueCheck         RuleType = "hashed_value_check"
 	RuleTypeContainsKeywords         RuleType = "contains_keywords"
 	RuleTypeNoTrailingWhitespace     RuleType = "no_trailing_whitespace"
+	RuleTypeConditional              RuleType = "conditional"
+	RuleTypeReferenceExists          RuleType = "reference_exists"
+	RuleTypeExpression               RuleType = "expression"
+	RuleTypeAllOf                    RuleType = "all_of"
+	RuleTypeAnyOf                    RuleType = "any_of"
+	RuleTypeNot                      RuleType = "not"
+	RuleTypeNoneOf                   RuleType = "none_of"
+	RuleTypeArraySorted              RuleType = "array_sorted"
 )
 
 // FieldType 定義欄位類型
@@ -47,6 +57,48 @@ type ValidationRule struct {
 	Description string   `yaml:"description,omitempty"`
 	Targets     Targets  `yaml:"targets"`
 	Rule        Rule     `yaml:"rule"`
+
+	Source  string `yaml:"-"` // 規則檔案路徑（由 Loader 設定）
+	Product string `yaml:"-"` // 規則所屬的產品
+
+	// expressions 載入時編譯的表達式（含巢狀規則中的表達式），key 由 expressionKey 產生
+	// 只在檢查規則時寫入，複製規則時共用
+	expressions map[string]*expr.Expression
+}
+
+// Clone 深層複製規則，修改複製後的 Targets 與 Rule 不影響原本的規則
+// 已編譯的表達式不會被修改，仍與原本的規則共用
+func (r *ValidationRule) Clone() *ValidationRule {
+	cloned := *r
+	if r.Targets.FilePatterns != nil {
+		cloned.Targets.FilePatterns = append([]string(nil), r.Targets.FilePatterns...)
+	}
+	if r.Rule.RawRule != nil {
+		cloned.Rule.RawRule = cloneValue(r.Rule.RawRule).(map[string]interface{})
+	}
+	return &cloned
+}
+
+// cloneValue 深層複製 YAML 解碼後的值（object、array 與純量）
+func cloneValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		cloned := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			cloned[key] = cloneValue(item)
+		}
+		return cloned
+	case []interface{}:
+		cloned := make([]interface{}, len(v))
+		for i, item := range v {
+			cloned[i] = cloneValue(item)
+		}
+		return cloned
+	case []string:
+		return append([]string(nil), v...)
+	default:
+		return v
+	}
 }
 
 // Targets 定義規則適用的目標檔案
@@ -154,6 +206,68 @@ type NoTrailingWhitespaceRule struct {
 	Message string `yaml:"message"`
 }
 
+// ArraySortedRule 陣列排序規則
+// 陣列項目為物件時依 field 的值排序，否則依項目本身的值排序
+type ArraySortedRule struct {
+	Path    string `yaml:"path"`
+	Field   string `yaml:"field,omitempty"`
+	Order   string `yaml:"order,omitempty"` // asc（預設）、desc
+	Message string `yaml:"message"`
+}
+
+// ConditionalRule 條件規則
+// 當 when 條件成立時，才執行 then 中的規則內容（可為任何既有的規則類型）
+type ConditionalRule struct {
+	When Condition              `yaml:"when"`
+	Then map[string]interface{} `yaml:"then"`
+}
+
+// Condition 條件判斷，exists、equals、matches、in 只能擇一使用
+type Condition struct {
+	Path    string        `yaml:"path"`
+	Exists  *bool         `yaml:"exists,omitempty"`  // 欄位是否存在
+	Equals  interface{}   `yaml:"equals,omitempty"`  // 值是否相等
+	Matches string        `yaml:"matches,omitempty"` // 值是否符合正則表達式
+	In      []interface{} `yaml:"in,omitempty"`      // 值是否在列表中
+}
+
+// ReferenceExistsRule 跨檔案參考檢查規則
+// path 的每個值都必須出現在本次驗證的檔案中 source.path 的值之中
+type ReferenceExistsRule struct {
+	Path    string          `yaml:"path"`
+	Source  ReferenceSource `yaml:"source"`
+	Message string          `yaml:"message"`
+}
+
+// ReferenceSource 參考值的來源
+type ReferenceSource struct {
+	Path         string   `yaml:"path"`
+	FilePatterns []string `yaml:"file_patterns,omitempty"` // 來源檔案，未指定時為所有檔案
+}
+
+// ExpressionRule 表達式規則
+// expression 結果為 false 時驗證失敗，語法與型別在載入時檢查
+// 設定 for_each 時會對每個陣列項目求值一次，表達式中以 @ 代表目前項目
+type ExpressionRule struct {
+	Expression string `yaml:"expression"`
+	ForEach    string `yaml:"for_each,omitempty"` // 逐一求值的陣列路徑，如 routes[*]
+	Path       string `yaml:"path,omitempty"`     // 回報錯誤的路徑，未指定時為表達式引用的第一個路徑
+	Message    string `yaml:"message"`
+}
+
+// CompositeRule 組合規則（all_of、any_of、none_of）
+// rules 中的每個分支都是任一規則類型的內容（包含 type），分支沒有產生結果即為通過
+type CompositeRule struct {
+	Rules   []map[string]interface{} `yaml:"rules"`
+	Message string                   `yaml:"message"`
+}
+
+// NotRule 反向規則，rule 分支通過時驗證失敗
+type NotRule struct {
+	Rule    map[string]interface{} `yaml:"rule"`
+	Message string                 `yaml:"message"`
+}
+
 // ValidationResult 驗證結果
 type ValidationResult struct {
 	File          string   `json:"file"`
@@ -164,4 +278,36 @@ type ValidationResult struct {
 	Path          string   `json:"path"`
 	ActualValue   string   `json:"actual_value,omitempty"`   // 實際值
 	ExpectedValue string   `json:"expected_value,omitempty"` // 期望值
+	Line          int      `json:"line,omitempty"`           // 起始行（從 1 開始）
+	Column        int      `json:"column,omitempty"`         // 起始欄（從 1 開始）
+	EndLine       int      `json:"end_line,omitempty"`       // 結束行
+	EndColumn     int      `json:"end_column,omitempty"`     // 結束欄（不含）
+	Document      int      `json:"document,omitempty"`       // 多文件檔案中的文件序號（從 1 開始）
+	RelatedFiles  []string `json:"related_files,omitempty"`  // 相關的其他檔案（如跨檔案參考的來源）
+	Product       string   `json:"product,omitempty"`        // 產生此結果的規則所屬產品
+
+	Suppressed        bool   `json:"suppressed,omitempty"`         // 是否被抑制註解忽略
+	SuppressionReason string `json:"suppression_reason,omitempty"` // 抑制原因
+
+	Fix *Fix `json:"fix,omitempty"` // 自動修正方式，無法自動修正時為 nil
+}
+
+// FixKind 自動修正的類型
+type FixKind string
+
+const (
+	FixReplaceValue FixKind = "replace_value" // 將純量值替換為 Value
+	FixRemoveItem   FixKind = "remove_item"   // 移除陣列 Path 的第 Index 個項目
+	FixReorderItems FixKind = "reorder_items" // 依 Order 重新排列陣列 Path 的項目
+)
+
+// Fix 描述如何自動修正一個問題，由 --fix 透過 YAML 節點樹套用
+// Path 為文件內的實際路徑，不含多文件的 [doc N] 前綴（文件序號見 ValidationResult.Document）
+type Fix struct {
+	Kind        FixKind `json:"kind"`
+	Description string  `json:"description"`
+	Path        string  `json:"path"`
+	Value       string  `json:"value,omitempty"` // replace_value 的新值
+	Index       int     `json:"index,omitempty"` // remove_item 要移除的項目
+	Order       []int   `json:"order,omitempty"` // reorder_items 新順序中每個位置對應的原索引
 }
@@ -0,0 +1,153 @@
+package rule
+
+import (
+	"config-validator/internal/glob"
+	"config-validator/internal/parser"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Workspace 本次執行中所有已解析的配置檔
+// 供 reference_exists 等跨檔案規則查詢其他檔案的內容
+type Workspace struct {
+	mu         sync.Mutex
+	files      map[string]parser.Document
+	matchPaths map[string]string // 檔案路徑 -> 相對於目前目錄的路徑
+	order      []string
+	values     map[string]*referenceSet // 依來源條件快取收集到的值
+}
+
+// referenceSet 跨檔案收集到的參考值
+type referenceSet struct {
+	values map[string][]string // 值 -> 定義該值的檔案
+	files  []string            // 所有符合來源條件的檔案
+}
+
+// NewWorkspace 建立新的工作區
+func NewWorkspace() *Workspace {
+	return &Workspace{
+		files:      make(map[string]parser.Document),
+		matchPaths: make(map[string]string),
+		values:     make(map[string]*referenceSet),
+	}
+}
+
+// Add 加入已解析的檔案
+// matchPath 為相對於目前目錄的路徑，用於比對 source.file_patterns
+func (w *Workspace) Add(filePath, matchPath string, p parser.Document) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, exists := w.files[filePath]; !exists {
+		w.order = append(w.order, filePath)
+		sort.Strings(w.order)
+	}
+	w.files[filePath] = p
+	w.matchPaths[filePath] = matchPath
+	w.values = make(map[string]*referenceSet)
+}
+
+// Remove 移除檔案（例如檔案已被刪除）
+func (w *Workspace) Remove(filePath string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, exists := w.files[filePath]; !exists {
+		return
+	}
+	delete(w.files, filePath)
+	delete(w.matchPaths, filePath)
+	for i, file := range w.order {
+		if file == filePath {
+			w.order = append(w.order[:i], w.order[i+1:]...)
+			break
+		}
+	}
+	w.values = make(map[string]*referenceSet)
+}
+
+// Files 返回所有檔案路徑（已排序）
+func (w *Workspace) Files() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	files := make([]string, len(w.order))
+	copy(files, w.order)
+	return files
+}
+
+// Parser 返回檔案的解析器
+func (w *Workspace) Parser(filePath string) (parser.Document, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	p, exists := w.files[filePath]
+	return p, exists
+}
+
+// collectValues 從所有符合 filePatterns 的檔案中收集 path 的值
+// filePatterns 為空時表示所有檔案
+func (w *Workspace) collectValues(path string, filePatterns []string) *referenceSet {
+	key := path + "|" + strings.Join(filePatterns, ",")
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if set, exists := w.values[key]; exists {
+		return set
+	}
+
+	set := &referenceSet{values: make(map[string][]string)}
+	for _, file := range w.order {
+		if len(filePatterns) > 0 && !glob.MatchAny(filePatterns, w.matchPaths[file]) {
+			continue
+		}
+		set.files = append(set.files, file)
+		collectDocumentValues(w.files[file], path, file, set)
+	}
+
+	w.values[key] = set
+	return set
+}
+
+// collectDocumentValues 收集單一檔案（含多文件）中 path 的值
+func collectDocumentValues(p parser.Document, path, file string, set *referenceSet) {
+	for _, doc := range p.Documents() {
+		for _, pathInfo := range doc.ExpandWildcardPath(path) {
+			value := fmt.Sprintf("%v", pathInfo.Value)
+			files := set.values[value]
+			if len(files) == 0 || files[len(files)-1] != file {
+				set.values[value] = append(files, file)
+			}
+		}
+	}
+}
+
+// UsesWorkspace 規則（含 conditional、組合規則中的巢狀規則）是否會查詢其他檔案
+// 其他檔案變更時，這些規則的結果也可能改變
+func (r *ValidationRule) UsesWorkspace() bool {
+	return r.Rule.Type == RuleTypeReferenceExists || nestedUsesWorkspace(r.Rule.RawRule)
+}
+
+func nestedUsesWorkspace(v interface{}) bool {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if val["type"] == string(RuleTypeReferenceExists) {
+			return true
+		}
+		for _, child := range val {
+			if nestedUsesWorkspace(child) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, child := range val {
+			if nestedUsesWorkspace(child) {
+				return true
+			}
+		}
+	}
+	return false
+}
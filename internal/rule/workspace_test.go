@@ -0,0 +1,110 @@
+package rule
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+const referenceRule = `
+id: db-ref
+name: 資料庫連線參考
+enabled: true
+severity: error
+targets:
+  file_patterns: ["**/api*.yaml"]
+rule:
+  type: reference_exists
+  path: routes[*].db
+  source:
+    path: connections[*].name
+    file_patterns: ["**/db*.yaml"]
+  message: 連線不存在
+`
+
+func TestExecuteReferenceExists(t *testing.T) {
+	r := mustRule(t, referenceRule)
+	api := mustParse(t, "routes:\n  - db: main\n  - db: reports\n  - db: {nested: true}\n")
+
+	workspace := NewWorkspace()
+	workspace.Add("configs/api.yaml", "configs/api.yaml", api)
+	workspace.Add("configs/db.yaml", "configs/db.yaml", mustParse(t, "connections:\n  - name: main\n"))
+	workspace.Add("configs/other.yaml", "configs/other.yaml", mustParse(t, "connections:\n  - name: reports\n"))
+
+	executor := NewExecutor(api)
+	executor.SetWorkspace(workspace)
+	results := executor.Execute(r, "configs/api.yaml")
+	if len(results) != 1 {
+		t.Fatalf("results = %d, want 1（other.yaml 不符合 source.file_patterns，物件值不檢查）", len(results))
+	}
+	result := results[0]
+	wantMessage := `連線不存在 ("reports" 在 **/db*.yaml 的 connections[*].name 中找不到)`
+	if result.Message != wantMessage || result.Path != "routes[1].db" || result.ActualValue != "reports" {
+		t.Errorf("result = %q %s %s", result.Message, result.Path, result.ActualValue)
+	}
+	if !reflect.DeepEqual(result.RelatedFiles, []string{"configs/db.yaml"}) {
+		t.Errorf("RelatedFiles = %v", result.RelatedFiles)
+	}
+
+	// 來源檔案增加時訊息不變，相關檔案有上限
+	for i := 0; i < 10; i++ {
+		name := fmt.Sprintf("configs/db%02d.yaml", i)
+		workspace.Add(name, name, mustParse(t, "connections:\n  - name: extra\n"))
+	}
+	results = executor.Execute(r, "configs/api.yaml")
+	if len(results) != 1 || results[0].Message != wantMessage {
+		t.Fatalf("來源檔案增加後 results = %+v", results)
+	}
+	if got := len(results[0].RelatedFiles); got != maxRelatedFiles {
+		t.Errorf("RelatedFiles = %d 個, want %d", got, maxRelatedFiles)
+	}
+
+	// 定義移除後重新收集
+	workspace.Add("configs/db.yaml", "configs/db.yaml", mustParse(t, "connections:\n  - name: reports\n"))
+	if results := executor.Execute(r, "configs/api.yaml"); len(results) != 1 || results[0].ActualValue != "main" {
+		t.Errorf("更新來源後 results = %+v", results)
+	}
+}
+
+func TestExecuteReferenceExistsWithoutWorkspace(t *testing.T) {
+	r := mustRule(t, referenceRule)
+	doc := mustParse(t, "connections:\n  - name: main\nroutes:\n  - db: main\n  - db: missing\n")
+
+	// 沒有工作區時只參考目前的文件
+	results := NewExecutor(doc).Execute(r, "api.yaml")
+	if len(results) != 1 || results[0].ActualValue != "missing" {
+		t.Fatalf("results = %+v", results)
+	}
+}
+
+func TestWorkspace(t *testing.T) {
+	w := NewWorkspace()
+	w.Add("b.yaml", "b.yaml", mustParse(t, "names: [x]\n"))
+	w.Add("a.yaml", "sub/a.yaml", mustParse(t, "names: [y]\n---\nnames: [z]\n"))
+	w.Add("b.yaml", "b.yaml", mustParse(t, "names: [w]\n")) // 重複加入時取代
+
+	if got := w.Files(); !reflect.DeepEqual(got, []string{"a.yaml", "b.yaml"}) {
+		t.Errorf("Files() = %v", got)
+	}
+
+	set := w.collectValues("names[*]", nil)
+	want := map[string][]string{"y": {"a.yaml"}, "z": {"a.yaml"}, "w": {"b.yaml"}}
+	if !reflect.DeepEqual(set.values, want) {
+		t.Errorf("values = %v, want %v", set.values, want)
+	}
+	if set := w.collectValues("names[*]", []string{"sub/**"}); !reflect.DeepEqual(set.files, []string{"a.yaml"}) {
+		t.Errorf("依 matchPath 篩選 files = %v", set.files)
+	}
+
+	w.Remove("a.yaml")
+	w.Remove("missing.yaml")
+	if got := w.Files(); !reflect.DeepEqual(got, []string{"b.yaml"}) {
+		t.Errorf("Remove 後 Files() = %v", got)
+	}
+	if _, exists := w.Parser("a.yaml"); exists {
+		t.Error("Remove 後仍可取得解析器")
+	}
+	if set := w.collectValues("names[*]", nil); len(set.values) != 1 {
+		t.Errorf("Remove 後 values = %v", set.values)
+	}
+}
@@ -0,0 +1,184 @@
+package ruletest
+
+import (
+	"config-validator/internal/parser"
+	"config-validator/internal/rule"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ExpectedFile fail 目錄中描述預期結果的檔案名稱
+const ExpectedFile = "expected.yaml"
+
+// Expectation 預期的單一驗證結果
+// Message 為空時只比對路徑
+type Expectation struct {
+	Path    string `yaml:"path"`
+	Message string `yaml:"message,omitempty"`
+}
+
+// CaseResult 單一 fixture 的測試結果
+type CaseResult struct {
+	Rule     *rule.ValidationRule
+	Fixture  string   // fixture 路徑
+	Kind     string   // pass 或 fail
+	Failures []string // 不符合預期的說明，空表示通過
+}
+
+// Passed 是否通過
+func (c *CaseResult) Passed() bool {
+	return len(c.Failures) == 0
+}
+
+// FixtureDir 返回規則的 fixture 目錄，如 rules/api/api-005.test
+func FixtureDir(r *rule.ValidationRule) string {
+	return filepath.Join(filepath.Dir(r.Source), r.ID+rule.TestDirSuffix)
+}
+
+// Run 執行規則的所有 fixture
+// pass/*.yaml 不可產生任何結果；fail/*.yaml 產生的結果必須與 fail/expected.yaml 完全一致
+// 沒有 fixture 目錄的規則返回空結果
+func Run(r *rule.ValidationRule) ([]*CaseResult, error) {
+	dir := FixtureDir(r)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	var cases []*CaseResult
+
+	passFiles, err := fixtureFiles(filepath.Join(dir, "pass"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range passFiles {
+		results, err := execute(r, file)
+		if err != nil {
+			return nil, err
+		}
+		c := &CaseResult{Rule: r, Fixture: file, Kind: "pass"}
+		for _, result := range results {
+			c.Failures = append(c.Failures, fmt.Sprintf("非預期的結果: %s (%s)", result.Path, result.Message))
+		}
+		cases = append(cases, c)
+	}
+
+	failDir := filepath.Join(dir, "fail")
+	failFiles, err := fixtureFiles(failDir)
+	if err != nil {
+		return nil, err
+	}
+	var expected map[string][]Expectation
+	if len(failFiles) > 0 {
+		expected, err = loadExpected(filepath.Join(failDir, ExpectedFile))
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, file := range failFiles {
+		results, err := execute(r, file)
+		if err != nil {
+			return nil, err
+		}
+		c := &CaseResult{Rule: r, Fixture: file, Kind: "fail"}
+		want, listed := expected[filepath.Base(file)]
+		if !listed {
+			c.Failures = append(c.Failures, fmt.Sprintf("%s 中沒有此 fixture 的預期結果", ExpectedFile))
+		} else {
+			c.Failures = compare(want, results)
+		}
+		cases = append(cases, c)
+	}
+
+	return cases, nil
+}
+
+// execute 只以指定規則驗證 fixture（不檢查 file_patterns）
+func execute(r *rule.ValidationRule, file string) ([]*rule.ValidationResult, error) {
+	p, err := parser.NewParser(parser.FormatOf(file))
+	if err != nil {
+		return nil, fmt.Errorf("解析 fixture %s 失敗: %w", file, err)
+	}
+	if err := p.ParseFile(file); err != nil {
+		return nil, fmt.Errorf("解析 fixture %s 失敗: %w", file, err)
+	}
+	return rule.NewExecutor(p).Execute(r, file), nil
+}
+
+// compare 比對預期與實際結果，返回不符合之處
+func compare(want []Expectation, results []*rule.ValidationResult) []string {
+	var failures []string
+	matched := make([]bool, len(results))
+
+	for _, exp := range want {
+		found := false
+		for i, result := range results {
+			if matched[i] || result.Path != exp.Path {
+				continue
+			}
+			if exp.Message != "" && result.Message != exp.Message {
+				continue
+			}
+			matched[i] = true
+			found = true
+			break
+		}
+		if !found {
+			if exp.Message != "" {
+				failures = append(failures, fmt.Sprintf("缺少預期的結果: %s (%s)", exp.Path, exp.Message))
+			} else {
+				failures = append(failures, fmt.Sprintf("缺少預期的結果: %s", exp.Path))
+			}
+		}
+	}
+
+	for i, result := range results {
+		if !matched[i] {
+			failures = append(failures, fmt.Sprintf("非預期的結果: %s (%s)", result.Path, result.Message))
+		}
+	}
+
+	return failures
+}
+
+// fixtureFiles 列出目錄中的 YAML fixture（不含 expected.yaml）
+func fixtureFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("讀取 fixture 目錄失敗: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || name == ExpectedFile {
+			continue
+		}
+		if parser.FormatOf(name) != "" {
+			files = append(files, filepath.Join(dir, name))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// loadExpected 載入 expected.yaml
+// 格式為 fixture 檔名對應預期結果列表
+func loadExpected(path string) (map[string][]Expectation, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("讀取 %s 失敗: %w", path, err)
+	}
+
+	var expected map[string][]Expectation
+	if err := yaml.Unmarshal(content, &expected); err != nil {
+		return nil, fmt.Errorf("解析 %s 失敗: %w", path, err)
+	}
+	return expected, nil
+}
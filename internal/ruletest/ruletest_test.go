@@ -0,0 +1,130 @@
+package ruletest
+
+import (
+	"config-validator/internal/rule"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFiles 在 dir 下建立檔案（相對路徑 -> 內容）
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func portRule(dir string) *rule.ValidationRule {
+	return &rule.ValidationRule{
+		ID:       "port",
+		Name:     "連接埠範圍",
+		Enabled:  true,
+		Severity: rule.SeverityError,
+		Source:   filepath.Join(dir, "port.yaml"),
+		Rule: rule.Rule{
+			Type:    rule.RuleTypeValueRange,
+			RawRule: map[string]interface{}{"path": "port", "min": 1024, "max": 65535, "message": "連接埠超出範圍"},
+		},
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"port.test/pass/ok.yaml":       "port: 8080\n",
+		"port.test/pass/bad.yaml":      "port: 80\n", // pass 中產生結果
+		"port.test/pass/notes.txt":     "不是 fixture\n",
+		"port.test/fail/low.yaml":      "port: 80\n",
+		"port.test/fail/mismatch.yaml": "port: 1\n",
+		"port.test/fail/unlisted.yaml": "port: 2\n",
+		"port.test/fail/expected.yaml": `
+low.yaml:
+  - path: port
+    message: 連接埠超出範圍
+mismatch.yaml:
+  - path: other
+`,
+	})
+
+	cases, err := Run(portRule(dir))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	got := make(map[string]*CaseResult)
+	for _, c := range cases {
+		got[c.Kind+"/"+filepath.Base(c.Fixture)] = c
+	}
+	if len(got) != 5 {
+		t.Fatalf("cases = %d, want 5: %v", len(got), got)
+	}
+
+	want := map[string]string{
+		"pass/ok.yaml":       "",
+		"pass/bad.yaml":      "非預期的結果: port",
+		"fail/low.yaml":      "",
+		"fail/mismatch.yaml": "缺少預期的結果: other",
+		"fail/unlisted.yaml": "沒有此 fixture 的預期結果",
+	}
+	for name, failure := range want {
+		c := got[name]
+		if failure == "" {
+			if !c.Passed() {
+				t.Errorf("%s failures = %v, want passed", name, c.Failures)
+			}
+			continue
+		}
+		if c.Passed() || !strings.Contains(c.Failures[0], failure) {
+			t.Errorf("%s failures = %v, want %q", name, c.Failures, failure)
+		}
+	}
+	// 預期以外的結果也要列出
+	if failures := got["fail/mismatch.yaml"].Failures; len(failures) != 2 || !strings.Contains(failures[1], "非預期的結果: port") {
+		t.Errorf("mismatch failures = %v", failures)
+	}
+}
+
+func TestRunWithoutFixtures(t *testing.T) {
+	cases, err := Run(portRule(t.TempDir()))
+	if err != nil || cases != nil {
+		t.Errorf("Run() = %v, %v, want nil, nil", cases, err)
+	}
+}
+
+func TestRunMissingExpected(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"port.test/fail/low.yaml": "port: 80\n"})
+	if _, err := Run(portRule(dir)); err == nil {
+		t.Error("缺少 expected.yaml 應返回錯誤")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	results := []*rule.ValidationResult{
+		{Path: "a", Message: "m1"},
+		{Path: "a", Message: "m2"},
+	}
+	tests := []struct {
+		name string
+		want []Expectation
+		fail int
+	}{
+		{"完全符合", []Expectation{{Path: "a", Message: "m2"}, {Path: "a"}}, 0},
+		{"同一路徑只配對一次", []Expectation{{Path: "a"}, {Path: "a"}, {Path: "a"}}, 1},
+		{"訊息不同", []Expectation{{Path: "a", Message: "x"}}, 3},
+		{"沒有預期", nil, 2},
+	}
+	for _, tt := range tests {
+		if got := compare(tt.want, results); len(got) != tt.fail {
+			t.Errorf("%s: compare() = %v, want %d 個失敗", tt.name, got, tt.fail)
+		}
+	}
+}
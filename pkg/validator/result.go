@@ -0,0 +1,88 @@
+package validator
+
+import (
+	"config-validator/internal/rule"
+	"sort"
+)
+
+// Result 驗證結果
+// 行列從 1 開始，欄位以字元（rune）計算，EndColumn 不包含在範圍內；無法定位時為 0
+type Result struct {
+	File          string   `json:"file"`
+	RuleID        string   `json:"rule_id"`
+	RuleName      string   `json:"rule_name"`
+	Severity      Severity `json:"severity"`
+	Message       string   `json:"message"`
+	Path          string   `json:"path"`
+	ActualValue   string   `json:"actual_value,omitempty"`   // 實際值
+	ExpectedValue string   `json:"expected_value,omitempty"` // 期望值
+	Line          int      `json:"line,omitempty"`
+	Column        int      `json:"column,omitempty"`
+	EndLine       int      `json:"end_line,omitempty"`
+	EndColumn     int      `json:"end_column,omitempty"`
+	Document      int      `json:"document,omitempty"`      // 多文件檔案中的文件序號（從 1 開始）
+	RelatedFiles  []string `json:"related_files,omitempty"` // 相關的其他檔案（如跨檔案參考的來源）
+	Product       string   `json:"product,omitempty"`       // 產生此結果的規則所屬產品
+
+	SuppressionReason string `json:"suppression_reason,omitempty"` // 被抑制註解忽略的原因
+}
+
+// newResult 轉換內部的驗證結果
+func newResult(r *rule.ValidationResult) *Result {
+	return &Result{
+		File:              r.File,
+		RuleID:            r.RuleID,
+		RuleName:          r.RuleName,
+		Severity:          r.Severity,
+		Message:           r.Message,
+		Path:              r.Path,
+		ActualValue:       r.ActualValue,
+		ExpectedValue:     r.ExpectedValue,
+		Line:              r.Line,
+		Column:            r.Column,
+		EndLine:           r.EndLine,
+		EndColumn:         r.EndColumn,
+		Document:          r.Document,
+		RelatedFiles:      r.RelatedFiles,
+		Product:           r.Product,
+		SuppressionReason: r.SuppressionReason,
+	}
+}
+
+// Report 一次驗證的結果
+type Report struct {
+	Results    []*Result // 未被抑制的結果，依檔案與位置排序
+	Suppressed []*Result // 被抑制註解忽略的結果
+	Files      []string  // 驗證過的檔案
+	Skipped    []string  // 無法識別產品而略過的檔案（只有 ValidateTree 會設定）
+}
+
+// HasErrors 是否有 error 等級的結果
+func (r *Report) HasErrors() bool {
+	return r.Count(SeverityError) > 0
+}
+
+// Count 計算指定嚴重程度的結果數量（不含被抑制的結果）
+func (r *Report) Count(severity Severity) int {
+	count := 0
+	for _, result := range r.Results {
+		if result.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// sortResults 依檔案、行、欄排序，相同位置保持驗證順序
+func sortResults(results []*Result) {
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
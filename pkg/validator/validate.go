@@ -0,0 +1,130 @@
+package validator
+
+import (
+	"config-validator/internal/pipeline"
+	"config-validator/internal/rule"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ErrUnsupportedFormat 檔案格式不支援
+var ErrUnsupportedFormat = errors.New("不支援的檔案格式")
+
+// ErrUnknownProduct 無法識別配置檔所屬的產品
+var ErrUnknownProduct = errors.New("無法識別配置檔的產品類型")
+
+// ValidateFile 讀取並驗證磁碟上的配置檔
+// 相對於目前目錄的路徑用於產品檢測與規則的 file_patterns 匹配，與 CLI 相同
+// 跨檔案規則（如 reference_exists）只會看到這個檔案，需要其他檔案時請使用 ValidateTree
+func (v *Validator) ValidateFile(ctx context.Context, path string) (*Report, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("讀取檔案失敗: %w", err)
+	}
+	return v.validateDocument(ctx, pipeline.File{Path: path, MatchPath: pipeline.MatchPath(path), Content: content})
+}
+
+// ValidateBytes 驗證記憶體中的配置檔內容
+// path 為邏輯路徑（如 services/api/api-prod.yaml），用於決定格式、產品檢測與規則的 file_patterns 匹配，不會讀取磁碟
+func (v *Validator) ValidateBytes(ctx context.Context, path string, data []byte) (*Report, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if data == nil {
+		data = []byte{} // Content 為 nil 時會讀取磁碟
+	}
+	return v.validateDocument(ctx, pipeline.File{Path: path, MatchPath: filepath.ToSlash(path), Content: data})
+}
+
+// validateDocument 解析並驗證單一配置檔
+func (v *Validator) validateDocument(ctx context.Context, file pipeline.File) (*Report, error) {
+	if file.Format = pipeline.FileFormat(v.detector, file); file.Format == "" {
+		return nil, fmt.Errorf("%s: %w", file.Path, ErrUnsupportedFormat)
+	}
+	jobs, skipped, err := pipeline.Prepare(ctx, v.detector, []pipeline.File{file}, v.productRules, v.jobs, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(skipped) > 0 {
+		return nil, fmt.Errorf("%s: %w", file.Path, ErrUnknownProduct)
+	}
+	return v.validate(ctx, jobs)
+}
+
+// ValidateTree 驗證目錄中的配置檔，檔案的選擇與 CLI 掃描目錄時相同
+// 預設只處理 YAML 檔案；JSON、TOML 等其他格式的檔案需有產品的 path_patterns 符合才會處理
+// 檔案相對於目前目錄的路徑用於產品檢測與規則匹配（與 ValidateFile 相同）；跨檔案規則可以查詢目錄中的所有配置檔
+// 無法識別產品的檔案（含無法解析的檔案）列於 Report.Skipped；可以識別產品的檔案無法解析時返回錯誤
+func (v *Validator) ValidateTree(ctx context.Context, root string) (*Report, error) {
+	var files []pipeline.File
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		file := pipeline.File{Path: path, MatchPath: pipeline.MatchPath(path)}
+		if file.Format = pipeline.ScanFormat(v.detector, file); file.Format != "" {
+			files = append(files, file)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("掃描配置檔失敗 %s: %w", root, err)
+	}
+
+	jobs, skipped, err := pipeline.Prepare(ctx, v.detector, files, v.productRules, v.jobs, nil)
+	if err != nil {
+		return nil, err
+	}
+	report, err := v.validate(ctx, jobs)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range skipped {
+		report.Skipped = append(report.Skipped, file.Path)
+	}
+	return report, nil
+}
+
+// productRules 返回產品的規則，作為 pipeline 的規則來源
+func (v *Validator) productRules(name string) ([]*rule.ValidationRule, error) {
+	return v.rules[name], nil
+}
+
+// validate 以所有配置檔建立工作區後平行驗證
+func (v *Validator) validate(ctx context.Context, jobs []*pipeline.Job) (*Report, error) {
+	fileResults, err := pipeline.ValidateAll(ctx, jobs, pipeline.NewWorkspace(jobs), v.jobs)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{}
+	for i, job := range jobs {
+		report.Files = append(report.Files, job.Path)
+		for _, r := range fileResults[i] {
+			if r.Suppressed {
+				report.Suppressed = append(report.Suppressed, newResult(r))
+			} else {
+				report.Results = append(report.Results, newResult(r))
+			}
+		}
+	}
+	sort.Strings(report.Files)
+	sortResults(report.Results)
+	sortResults(report.Suppressed)
+	return report, nil
+}
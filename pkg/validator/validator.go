@@ -0,0 +1,211 @@
+// Package validator 提供在 Go 程式中直接使用配置驗證器的 API
+//
+// 驗證器由產品配置（products.yaml）與規則建立，產品與規則可以從磁碟、fs.FS（如 embed.FS）
+// 或記憶體中的結構載入。建立後的 Validator 不會再讀取規則，可以在多個 goroutine 間共用：
+//
+//	v, err := validator.Load("configs/products.yaml")
+//	if err != nil {
+//		return err
+//	}
+//	report, err := v.ValidateFile(ctx, "services/api/api-prod.yaml")
+//	if err != nil {
+//		return err
+//	}
+//	if report.HasErrors() {
+//		for _, r := range report.Results {
+//			log.Printf("%s:%d [%s] %s", r.File, r.Line, r.RuleID, r.Message)
+//		}
+//	}
+package validator
+
+import (
+	"config-validator/internal/product"
+	"config-validator/internal/rule"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"runtime"
+)
+
+// Product 產品配置，欄位與 products.yaml 相同
+type Product = product.ProductConfig
+
+// ContentMatch 產品的內容匹配條件
+type ContentMatch = product.ContentMatch
+
+// Rule 驗證規則，欄位與規則檔案相同
+type Rule = rule.ValidationRule
+
+// Targets 規則適用的目標檔案
+type Targets = rule.Targets
+
+// RuleBody 規則的驗證邏輯（type 與該類型的設定）
+type RuleBody = rule.Rule
+
+// Severity 規則的嚴重程度
+type Severity = rule.Severity
+
+const (
+	SeverityError   = rule.SeverityError
+	SeverityWarning = rule.SeverityWarning
+	SeverityInfo    = rule.SeverityInfo
+)
+
+// Config 以記憶體中的結構建立驗證器的設定
+type Config struct {
+	MultiProduct bool      // 所有匹配的產品都套用
+	Products     []Product // 產品配置
+
+	// Rules 規則目錄名稱 -> 規則，目錄名稱即產品的 rules_dir 與 shared_rules_dirs
+	// 與從檔案載入相同，Enabled 為 false 的規則不會套用
+	Rules map[string][]*Rule
+}
+
+// Validator 配置驗證器
+type Validator struct {
+	detector *product.Detector
+	rules    map[string][]*rule.ValidationRule // 產品 -> 規則（含繼承的規則）
+	jobs     int
+}
+
+// Load 從磁碟載入 products.yaml 與規則
+// 產品的 rules_dir 與 shared_rules_dirs 為相對路徑時，相對於 products.yaml 所在的目錄
+func Load(productsPath string) (*Validator, error) {
+	detector, err := product.NewDetector(productsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	baseDir := filepath.Dir(productsPath)
+	resolveDir := func(dir string) string {
+		if filepath.IsAbs(dir) {
+			return dir
+		}
+		return filepath.Join(baseDir, dir)
+	}
+	return newValidator(detector, resolveDir, rule.NewLayeredLoader())
+}
+
+// LoadFS 從 fs.FS（如 embed.FS）載入 products.yaml 與規則
+// 產品的 rules_dir 與 shared_rules_dirs 相對於 products.yaml 所在的目錄
+func LoadFS(fsys fs.FS, productsPath string) (*Validator, error) {
+	content, err := fs.ReadFile(fsys, productsPath)
+	if err != nil {
+		return nil, fmt.Errorf("讀取產品配置失敗: %w", err)
+	}
+	detector, err := product.NewDetectorFromBytes(content)
+	if err != nil {
+		return nil, err
+	}
+
+	baseDir := path.Dir(productsPath)
+	resolveDir := func(dir string) string {
+		return path.Join(baseDir, dir)
+	}
+	loader := rule.NewLayeredLoaderFrom(func(dir string) ([]*rule.ValidationRule, error) {
+		return rule.NewFSLoader(fsys, dir).LoadRules()
+	})
+	return newValidator(detector, resolveDir, loader)
+}
+
+// New 以記憶體中的產品與規則建立驗證器
+// 規則會與從檔案載入時一樣檢查設定是否正確；傳入的規則會被深層複製（含 Targets 與 Rule），之後修改不影響驗證器
+func New(config Config) (*Validator, error) {
+	detector, err := product.NewDetectorFromConfig(product.ProductsConfig{
+		MultiProduct: config.MultiProduct,
+		Products:     config.Products,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	loader := rule.NewLayeredLoaderFrom(func(dir string) ([]*rule.ValidationRule, error) {
+		defined, exists := config.Rules[dir]
+		if !exists {
+			return nil, fmt.Errorf("規則目錄不存在: %s", dir)
+		}
+
+		var rules []*rule.ValidationRule
+		for i, r := range defined {
+			if r == nil {
+				return nil, fmt.Errorf("第 %d 條規則為 nil", i+1)
+			}
+			// 先深層複製再檢查，檢查時編譯的表達式只存在複製的規則上，不修改呼叫端的結構
+			copied := r.Clone()
+			if err := rule.Validate(copied); err != nil {
+				return nil, fmt.Errorf("第 %d 條規則錯誤: %w", i+1, err)
+			}
+			if !copied.Enabled {
+				continue
+			}
+			if copied.Source == "" {
+				copied.Source = dir
+			}
+			rules = append(rules, copied)
+		}
+		return rules, nil
+	})
+	return newValidator(detector, func(dir string) string { return dir }, loader)
+}
+
+// newValidator 載入所有產品的規則
+func newValidator(detector *product.Detector, resolveDir func(string) string, loader *rule.LayeredLoader) (*Validator, error) {
+	layers, err := detector.RuleLayers(resolveDir)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &Validator{
+		detector: detector,
+		rules:    make(map[string][]*rule.ValidationRule),
+		jobs:     runtime.NumCPU(),
+	}
+	for _, prod := range detector.Products() {
+		layerRules, err := loader.Load(layers[prod.Name])
+		if err != nil {
+			return nil, fmt.Errorf("載入產品 %s 的規則失敗: %w", prod.Name, err)
+		}
+
+		// 繼承的規則可能被多個產品共用，複製後再標記所屬產品
+		rules := make([]*rule.ValidationRule, len(layerRules))
+		for i, r := range layerRules {
+			tagged := *r
+			tagged.Product = prod.Name
+			rules[i] = &tagged
+		}
+		v.rules[prod.Name] = rules
+	}
+	return v, nil
+}
+
+// SetJobs 設定 ValidateTree 同時驗證的檔案數量，預設為 CPU 數量
+// 應在開始驗證前呼叫
+func (v *Validator) SetJobs(jobs int) {
+	if jobs < 1 {
+		jobs = 1
+	}
+	v.jobs = jobs
+}
+
+// Products 返回所有產品配置的複本
+func (v *Validator) Products() []Product {
+	products := make([]Product, len(v.detector.Products()))
+	for i, prod := range v.detector.Products() {
+		products[i] = prod.Clone()
+	}
+	return products
+}
+
+// Rules 返回產品的規則（含繼承的規則）的複本，產品不存在時返回 nil
+func (v *Validator) Rules(productName string) []*Rule {
+	productRules, exists := v.rules[productName]
+	if !exists {
+		return nil
+	}
+	rules := make([]*Rule, len(productRules))
+	for i, r := range productRules {
+		rules[i] = r.Clone()
+	}
+	return rules
+}
@@ -0,0 +1,243 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const testProducts = `products:
+  - name: app
+    rules_dir: rules/app
+    path_patterns: ["**/app*.yaml"]
+`
+
+const testRule = `id: app-001
+name: "name 必填"
+enabled: true
+severity: error
+targets:
+  file_patterns: ["**/app*.yaml"]
+rule:
+  type: required_field
+  path: name
+  message: "缺少 name"
+`
+
+// writeFile 寫入測試檔案，必要時建立目錄
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// ruleIDs 返回結果的規則 ID，以逗號分隔
+func ruleIDs(results []*Result) string {
+	var list []string
+	for _, r := range results {
+		list = append(list, r.RuleID)
+	}
+	return strings.Join(list, ",")
+}
+
+// newTestValidator 以記憶體中的產品與規則建立驗證器
+func newTestValidator(t *testing.T, rules ...*Rule) *Validator {
+	t.Helper()
+	v, err := New(Config{
+		Products: []Product{{Name: "app", RulesDir: "app", PathPatterns: []string{"**/app*.yaml"}}},
+		Rules:    map[string][]*Rule{"app": rules},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return v
+}
+
+// requiredField 建立 required_field 規則
+func requiredField(id, path string) *Rule {
+	return &Rule{
+		ID:       id,
+		Name:     path + " 必填",
+		Enabled:  true,
+		Severity: SeverityError,
+		Targets:  Targets{FilePatterns: []string{"**/*.yaml"}},
+		Rule:     RuleBody{Type: "required_field", RawRule: map[string]interface{}{"path": path, "message": "缺少 " + path}},
+	}
+}
+
+func TestLoad(t *testing.T) {
+	// rules_dir 相對於 products.yaml 所在的目錄，而不是工作目錄
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "products.yaml"), testProducts)
+	writeFile(t, filepath.Join(dir, "rules", "app", "app-001.yaml"), testRule)
+
+	v, err := Load(filepath.Join(dir, "products.yaml"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if rules := v.Rules("app"); len(rules) != 1 || rules[0].ID != "app-001" || rules[0].Product != "app" {
+		t.Errorf("Rules(app) = %v, want [app-001]", rules)
+	}
+
+	report, err := v.ValidateBytes(context.Background(), "configs/app.yaml", []byte("port: 8080\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ruleIDs(report.Results); got != "app-001" || !report.HasErrors() {
+		t.Errorf("Results = %s, want app-001", got)
+	}
+
+	if _, err := Load(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("Load(不存在的檔案) 應返回錯誤")
+	}
+}
+
+func TestLoadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"config/products.yaml":          {Data: []byte(testProducts)},
+		"config/rules/app/app-001.yaml": {Data: []byte(testRule)},
+	}
+	v, err := LoadFS(fsys, "config/products.yaml")
+	if err != nil {
+		t.Fatalf("LoadFS: %v", err)
+	}
+	report, err := v.ValidateBytes(context.Background(), "app.yaml", []byte("name: app\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Results) != 0 || len(report.Files) != 1 {
+		t.Errorf("report = %+v, want 1 個檔案、沒有結果", report)
+	}
+
+	// 規則目錄不存在時返回錯誤
+	delete(fsys, "config/rules/app/app-001.yaml")
+	if _, err := LoadFS(fsys, "config/products.yaml"); err == nil || !strings.Contains(err.Error(), "app") {
+		t.Errorf("LoadFS(缺少規則目錄) = %v, want 錯誤", err)
+	}
+	if _, err := LoadFS(fsys, "missing.yaml"); err == nil {
+		t.Error("LoadFS(不存在的檔案) 應返回錯誤")
+	}
+}
+
+func TestNew(t *testing.T) {
+	name := requiredField("app-001", "name")
+	disabled := requiredField("app-002", "port")
+	disabled.Enabled = false
+	v := newTestValidator(t, name, disabled)
+
+	// 停用的規則不套用
+	if rules := v.Rules("app"); len(rules) != 1 || rules[0].ID != "app-001" {
+		t.Fatalf("Rules(app) = %v, want [app-001]", rules)
+	}
+
+	// 傳入的規則被深層複製，之後修改不影響驗證器
+	name.ID = "changed"
+	name.Targets.FilePatterns[0] = "*.json"
+	name.Rule.RawRule["path"] = "port"
+	report, err := v.ValidateBytes(context.Background(), "app.yaml", []byte("port: 8080\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Results) != 1 || report.Results[0].RuleID != "app-001" || report.Results[0].Path != "name" {
+		t.Errorf("Results = %+v, want app-001 於 name", report.Results)
+	}
+
+	// Rules 返回的規則也是複本
+	v.Rules("app")[0].Rule.RawRule["path"] = "port"
+	if v.Rules("app")[0].Rule.RawRule["path"] != "name" {
+		t.Error("修改 Rules() 的結果不應影響驗證器")
+	}
+
+	// 規則設定錯誤或規則目錄不存在時返回錯誤
+	invalid := requiredField("app-003", "name")
+	invalid.Rule.Type = "unknown"
+	if _, err := New(Config{
+		Products: []Product{{Name: "app", RulesDir: "app", PathPatterns: []string{"**/*.yaml"}}},
+		Rules:    map[string][]*Rule{"app": {invalid}},
+	}); err == nil {
+		t.Error("New(無效的規則) 應返回錯誤")
+	}
+	if _, err := New(Config{Products: []Product{{Name: "app", RulesDir: "app", PathPatterns: []string{"**/*.yaml"}}}}); err == nil {
+		t.Error("New(缺少規則目錄) 應返回錯誤")
+	}
+}
+
+func TestValidateBytesErrors(t *testing.T) {
+	v := newTestValidator(t, requiredField("app-001", "name"))
+	ctx := context.Background()
+
+	if _, err := v.ValidateBytes(ctx, "app.txt", []byte("name: app\n")); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("ValidateBytes(app.txt) = %v, want ErrUnsupportedFormat", err)
+	}
+	if _, err := v.ValidateBytes(ctx, "other.yaml", []byte("name: app\n")); !errors.Is(err, ErrUnknownProduct) {
+		t.Errorf("ValidateBytes(other.yaml) = %v, want ErrUnknownProduct", err)
+	}
+	if _, err := v.ValidateBytes(ctx, "app.yaml", []byte("name: [app\n")); err == nil || !strings.Contains(err.Error(), "app.yaml") {
+		t.Errorf("ValidateBytes(無法解析) = %v, want 解析錯誤", err)
+	}
+
+	// nil 內容視為空的配置檔，不讀取磁碟
+	report, err := v.ValidateBytes(ctx, "app.yaml", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ruleIDs(report.Results); got != "app-001" {
+		t.Errorf("ValidateBytes(nil) = %s, want app-001", got)
+	}
+}
+
+func TestValidateTree(t *testing.T) {
+	v := newTestValidator(t, requiredField("app-001", "name"))
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "api", "app.yaml"), "port: 8080\n")
+	writeFile(t, filepath.Join(dir, "api", "app-ok.yaml"), "name: api\n")
+	writeFile(t, filepath.Join(dir, "other.yaml"), "a: [1\n")
+	writeFile(t, filepath.Join(dir, "package.json"), "{}\n")
+
+	report, err := v.ValidateTree(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("ValidateTree: %v", err)
+	}
+	if len(report.Files) != 2 {
+		t.Errorf("Files = %v, want 2 個檔案", report.Files)
+	}
+	if got := ruleIDs(report.Results); got != "app-001" || report.Results[0].File != filepath.Join(dir, "api", "app.yaml") {
+		t.Errorf("Results = %+v, want app-001 於 api/app.yaml", report.Results)
+	}
+	// 無法識別產品的檔案（含無法解析的檔案）列於 Skipped，不處理的格式不列出
+	if len(report.Skipped) != 1 || report.Skipped[0] != filepath.Join(dir, "other.yaml") {
+		t.Errorf("Skipped = %v, want [other.yaml]", report.Skipped)
+	}
+
+	// 可以識別產品的檔案無法解析時返回錯誤
+	writeFile(t, filepath.Join(dir, "api", "app.yaml"), "name: [api\n")
+	if _, err := v.ValidateTree(context.Background(), dir); err == nil {
+		t.Error("ValidateTree(無法解析) 應返回錯誤")
+	}
+}
+
+func TestValidateCanceled(t *testing.T) {
+	v := newTestValidator(t, requiredField("app-001", "name"))
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "app.yaml"), "name: app\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := v.ValidateFile(ctx, filepath.Join(dir, "app.yaml")); !errors.Is(err, context.Canceled) {
+		t.Errorf("ValidateFile = %v, want context.Canceled", err)
+	}
+	if _, err := v.ValidateBytes(ctx, "app.yaml", []byte("name: app\n")); !errors.Is(err, context.Canceled) {
+		t.Errorf("ValidateBytes = %v, want context.Canceled", err)
+	}
+	if _, err := v.ValidateTree(ctx, dir); !errors.Is(err, context.Canceled) {
+		t.Errorf("ValidateTree = %v, want context.Canceled", err)
+	}
+}